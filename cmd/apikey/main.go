@@ -0,0 +1,39 @@
+// Command apikey generates a client API key and the API_KEYS entry that
+// grants it the given scopes. Only the entry goes into the server's config;
+// the key is handed to the client and not stored anywhere.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/auth"
+)
+
+func main() {
+	client := flag.String("client", "", "client ID")
+	// Reading private keys is only granted when asked for.
+	defaults := slices.DeleteFunc(slices.Clone(auth.Scopes), func(scope string) bool { return scope == auth.ScopeExportWallets })
+	scopes := flag.String("scopes", strings.Join(defaults, ","), "comma-separated scopes")
+	flag.Parse()
+
+	if *client == "" {
+		log.Fatal("-client is required")
+	}
+
+	key, err := auth.GenerateKey()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	entry := fmt.Sprintf("%s:%s:%s", *client, auth.HashKey(key), *scopes)
+	if _, err := auth.ParseKeys(entry); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("API key:        %s\n", key)
+	fmt.Printf("API_KEYS entry: %s\n", entry)
+}
@@ -0,0 +1,32 @@
+// Command fakemnee is a local stand-in for the MNEE cosigner and indexer. It
+// serves the HTTP API the MNEE SDK talks to from an in-memory ledger, so the
+// wrapper can run end-to-end with MNEE_ENV pointed at this server.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/fakemnee"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
+)
+
+func main() {
+	addr := flag.String("addr", ":9090", "listen address")
+	fixture := flag.String("fixture", "", "JSON fixture used to seed the ledger")
+	token := flag.String("token", "", "require this auth_token on every request (any token is accepted when empty)")
+	flag.Parse()
+
+	ledger := services.NewFakeClient()
+	if *fixture != "" {
+		if err := ledger.LoadFixture(*fixture); err != nil {
+			log.Fatalf("Failed to load fixture: %v", err)
+		}
+	}
+
+	log.Printf("Fake MNEE cosigner running on %s", *addr)
+	if err := http.ListenAndServe(*addr, fakemnee.NewServer(ledger, *token)); err != nil {
+		log.Fatal("Failed to start server:", err)
+	}
+}
@@ -1,13 +1,37 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/auth"
 	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/config"
 	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/handlers"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/hdwallet"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/health"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/idempotency"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/ledger"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/lifecycle"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/logging"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/metrics"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/middleware"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/ratelimit"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/reconciler"
 	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/tracing"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/wallets"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/webhooks"
 
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -29,35 +53,270 @@ import (
 func main() {
 	cfg := config.LoadConfig()
 
+	if err := logging.Init(os.Stderr, cfg.LogFormat, cfg.LogLevel); err != nil {
+		log.Fatal("Invalid LOG_FORMAT or LOG_LEVEL: ", err)
+	}
+	gin.DebugPrintFunc = func(format string, values ...any) {
+		slog.Debug(strings.TrimSpace(fmt.Sprintf(strings.TrimPrefix(format, "[WARNING] "), values...)))
+	}
+	gin.DebugPrintRouteFunc = func(method, path, handler string, _ int) {
+		slog.Debug("route", "method", method, "path", path, "handler", handler)
+	}
+
+	ledger.InitLedger(cfg)
 	services.InitMneeService(cfg)
+	wallets.InitWallets(cfg)
+	hdwallet.InitAccounts(cfg)
 
-	r := gin.Default()
+	// A nil *wallets.Store must not become a non-nil Sealer.
+	var callbackSecrets webhooks.Sealer
+	if wallets.Instance != nil {
+		callbackSecrets = wallets.Instance
+	}
+	webhooks.InitRelay(webhooks.Config{
+		PublicURL:      cfg.WebhookPublicURL,
+		CallbackSecret: cfg.WebhookCallbackSecret,
+		Subscribers:    cfg.WebhookSubscribers,
+		SigningSecret:  cfg.WebhookSigningSecret,
+		CallbackHosts:  cfg.WebhookCallbackHosts,
+		MaxAttempts:    cfg.WebhookMaxAttempts,
+		Timeout:        cfg.WebhookTimeout,
+		Tickets:        ledger.Instance,
+		Secrets:        callbackSecrets,
+	})
+
+	if cfg.ReadyCheckInterval <= 0 {
+		log.Fatal("READY_CHECK_INTERVAL must be positive")
+	}
+	health.Instance = health.NewChecker(cfg.ReadyCheckInterval, cfg.ReadyMaxAge)
+	checkCtx, stopChecks := context.WithCancel(context.Background())
+	defer stopChecks()
+	go health.Instance.Run(checkCtx)
+
+	stopTracing, err := tracing.Init(cfg.TracesExporter, cfg.TracesFile, cfg.ServiceName)
+	if err != nil {
+		log.Fatal("Invalid OTEL_TRACES_EXPORTER: ", err)
+	}
+
+	idempotencyStore, err := idempotency.NewMemoryStore(cfg.IdempotencyTTL, cfg.IdempotencyStorePath)
+	if err != nil {
+		log.Fatal("Failed to load idempotency store:", err)
+	}
+	idempotent := middleware.Idempotency(idempotencyStore)
+	inFlight := middleware.InFlight(lifecycle.InFlight)
+
+	if cfg.MaxQueryAddresses <= 0 {
+		log.Fatal("MAX_QUERY_ADDRESSES must be positive")
+	}
+	handlers.MaxQueryAddresses = cfg.MaxQueryAddresses
+	ratelimit.SourceAddresses = ratelimit.New(cfg.RateLimitAddressTransfers)
+	limitClient := middleware.RateLimitClient(ratelimit.New(cfg.RateLimitClientTransfers))
+	limitIP := middleware.RateLimitIP(ratelimit.New(cfg.RateLimitIPReads))
+
+	cacheConfig := middleware.CacheHeaders(cfg.CacheTTLConfig)
+	cacheBalances := middleware.CacheHeaders(cfg.CacheTTLBalances)
+	cacheUtxos := middleware.CacheHeaders(cfg.CacheTTLUtxos)
+
+	r := gin.New()
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatal("Invalid TRUSTED_PROXIES: ", err)
+	}
+	r.Use(middleware.RequestID(), middleware.AccessLog(), middleware.Recovery())
 	r.Use(cors.Default())
+	r.Use(middleware.Metrics())
+
+	r.GET("/healthz", handlers.Healthz)
+	r.GET("/readyz", handlers.Readyz)
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	// Routes registered from here on are traced; probes and scrapes are not.
+	r.Use(middleware.Tracing())
 
 	api := r.Group("/api")
+	api.POST(webhooks.CallbackPath, handlers.MneeCallback)
+
+	authed := api.Group("")
+	if cfg.AuthDisabled {
+		log.Printf("Authentication is disabled; every endpoint is open")
+	} else {
+		keys, err := auth.ParseKeys(cfg.ApiKeys)
+		if err != nil {
+			log.Fatal("Invalid API_KEYS: ", err)
+		}
+		if keys.Len() == 0 {
+			log.Fatal("API_KEYS is required unless AUTH_DISABLED=true")
+		}
+		authed.Use(middleware.Authenticate(keys))
+	}
+
+	tenants, err := services.ParseTenants(cfg.Tenants)
+	if err != nil {
+		log.Fatal("Invalid TENANTS: ", err)
+	}
+	if len(tenants) > 0 {
+		if cfg.AuthDisabled {
+			log.Fatal("TENANTS needs authentication; unset AUTH_DISABLED")
+		}
+		services.Tenants = services.NewTenantRegistry(tenants, cfg.MneeFixture)
+		authed.Use(middleware.Tenant(services.Tenants))
+		log.Printf("Serving %d tenants with their own MNEE API keys", len(tenants))
+	}
+
+	// Started once Tenants is set, so tickets are looked up with their
+	// tenant's API key.
+	if ledger.Instance != nil && cfg.ReconcileInterval > 0 {
+		reconciler.Instance = reconciler.New(reconciler.Config{
+			Interval:    cfg.ReconcileInterval,
+			MaxInterval: cfg.ReconcileMaxInterval,
+			StuckAfter:  cfg.ReconcileStuckAfter,
+			MaxAge:      cfg.ReconcileMaxAge,
+		}, ledger.Instance)
+		reconciler.Instance.Start()
+	}
+
+	balances := authed.Group("", middleware.RequireScope(auth.ScopeReadBalance), limitIP)
 	{
-		api.GET("/config", handlers.GetConfig)
+		balances.GET("/config", cacheConfig, handlers.GetConfig)
+
+		balances.GET("/balance/:address", cacheBalances, handlers.GetBalance)
+		balances.GET("/balance", cacheBalances, handlers.GetBalances)
+
+		balances.GET("/utxos/paginated", cacheUtxos, handlers.GetPaginatedUtxos)
+		balances.GET("/utxos/all", cacheUtxos, handlers.GetAllUtxos)
+
+		balances.GET("/fees/quote", handlers.GetFeeQuote)
+		balances.POST("/fees/quote", handlers.PostFeeQuote)
 
-		api.GET("/balance/:address", handlers.GetBalance)
-		api.GET("/balance", handlers.GetBalances)
+		balances.GET("/wallets", handlers.ListWallets)
+		balances.GET("/wallets/:id", handlers.GetWallet)
 
-		api.GET("/utxos/paginated", handlers.GetPaginatedUtxos)
-		api.GET("/utxos/all", handlers.GetAllUtxos)
+		balances.GET("/accounts", handlers.ListAccounts)
+		balances.GET("/accounts/:id", handlers.GetAccount)
+		balances.GET("/accounts/:id/balance", cacheBalances, handlers.GetAccountBalance)
+		balances.GET("/accounts/:id/utxos", cacheUtxos, handlers.GetAccountUtxos)
+	}
 
-		api.GET("/transaction", handlers.GetHistory)
-		api.GET("/transaction/status/:ticketId", handlers.PollTicket)
+	walletAdmin := authed.Group("", middleware.RequireScope(auth.ScopeWriteWallets))
+	{
+		walletAdmin.POST("/wallets", handlers.CreateWallet)
+		walletAdmin.DELETE("/wallets/:id", handlers.DeleteWallet)
 
-		api.POST("/transaction/transfer", handlers.TransferSync)
-		api.POST("/transaction/transfer-async", handlers.TransferAsync)
-		api.POST("/transaction/partial-sign", handlers.PartialSign)
-		api.POST("/transaction/submit-rawtx", handlers.SubmitRawTxSync)
-		api.POST("/transaction/submit-rawtx-async", handlers.SubmitRawTxAsync)
+		walletAdmin.POST("/accounts", handlers.CreateAccount)
+		walletAdmin.POST("/accounts/:id/scan", handlers.ScanAccount)
+		walletAdmin.DELETE("/accounts/:id", handlers.DeleteAccount)
+	}
+
+	// Exports return private keys, so they need a scope of their own on top
+	// of WALLET_EXPORT_ENABLED.
+	walletExport := authed.Group("", middleware.RequireScope(auth.ScopeExportWallets))
+	{
+		walletExport.POST("/wallets/:id/export", handlers.ExportWallet)
+	}
+
+	history := authed.Group("", middleware.RequireScope(auth.ScopeReadHistory), limitIP)
+	{
+		history.GET("/transaction", handlers.GetHistory)
+		history.GET("/transaction/status/:ticketId", inFlight, handlers.PollTicket)
+		history.GET("/transaction/status/:ticketId/stream", inFlight, handlers.StreamTicket)
+		history.GET("/transaction/ticket/:id", handlers.GetTicket)
+		history.GET("/accounts/:id/history", handlers.GetAccountHistory)
+		history.GET("/transfers", handlers.ListTransfers)
+	}
+
+	transfers := authed.Group("", middleware.RequireScope(auth.ScopeWriteTransfer), limitClient, inFlight)
+	{
+		transfers.POST("/transaction/transfer", idempotent, handlers.TransferSync)
+		transfers.POST("/transaction/transfer-async", idempotent, handlers.TransferAsync)
+		transfers.POST("/transaction/build", handlers.BuildTransaction)
+		transfers.POST("/transaction/partial-sign", handlers.PartialSign)
+	}
+
+	rawTxs := authed.Group("", middleware.RequireScope(auth.ScopeWriteRawTx), limitClient, inFlight)
+	{
+		rawTxs.POST("/transaction/submit-rawtx", idempotent, handlers.SubmitRawTxSync)
+		rawTxs.POST("/transaction/submit-rawtx-async", idempotent, handlers.SubmitRawTxAsync)
 	}
 
 	r.GET("/api-docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	log.Printf("MNEE API Wrapper running on port %s", cfg.Port)
-	if err := r.Run(":" + cfg.Port); err != nil {
-		log.Fatal("Failed to start server:", err)
+	// Slow clients must not hold connections open forever, nor stall the
+	// drain on shutdown. There is no overall read or write timeout, as ticket
+	// streams and synchronous transfers run for long.
+	server := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           r,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+
+	go func() {
+		log.Printf("MNEE API Wrapper running on port %s", cfg.Port)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("Failed to start server:", err)
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	<-ctx.Done()
+	stop()
+
+	shutdown(server, cfg, idempotencyStore, stopTracing)
+}
+
+// shutdown fails readiness, gives load balancers ShutdownDelay to stop
+// routing requests here, then waits up to ShutdownTimeout for requests in
+// flight and pending webhook deliveries. Transfers still running when the
+// timeout expires are logged with their ticket IDs, so their outcome can be
+// checked by hand. The reconciler stops before webhook deliveries are
+// awaited, the ledger is closed once nothing writes to it, and buffered spans
+// are flushed last.
+func shutdown(server *http.Server, cfg *config.Config, idempotencyStore *idempotency.MemoryStore, stopTracing func(context.Context) error) {
+	log.Printf("Shutting down; draining requests for up to %s", cfg.ShutdownTimeout)
+	lifecycle.StartDraining()
+	time.Sleep(cfg.ShutdownDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	logInFlight("Waiting for")
+	if err := server.Shutdown(ctx); err != nil {
+		logInFlight("Abandoning")
+		_ = server.Close()
+	}
+
+	if err := reconciler.Instance.Close(ctx); err != nil {
+		log.Printf("Abandoning the ticket lookup in progress: %v", err)
+	}
+
+	if err := webhooks.Instance.Close(ctx); err != nil {
+		log.Printf("Abandoning pending webhook deliveries: %v", err)
+	}
+
+	if err := ledger.Close(); err != nil {
+		log.Printf("Failed to close the ledger: %v", err)
+	}
+
+	if err := idempotencyStore.Close(); err != nil {
+		log.Printf("Failed to close the idempotency store: %v", err)
+	}
+
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFlush()
+	if err := stopTracing(flushCtx); err != nil {
+		log.Printf("Failed to flush traces: %v", err)
+	}
+
+	log.Printf("Server stopped")
+}
+
+func logInFlight(action string) {
+	for _, request := range lifecycle.InFlight.Snapshot() {
+		ticketID := request.TicketID
+		if ticketID == "" {
+			ticketID = "none yet"
+		}
+		log.Printf("%s %s %s (client %q, idempotency key %q, ticket %s, running %s)",
+			action, request.Method, request.Path, request.ClientID, request.IdempotencyKey, ticketID,
+			time.Since(request.StartedAt).Round(time.Millisecond))
 	}
 }
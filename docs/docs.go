@@ -15,34 +15,37 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/balance": {
+        "/accounts": {
             "get": {
-                "description": "Retrieves balances for a comma-separated list of addresses",
+                "description": "Lists the caller's HD accounts with their next receive and change addresses.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Balance"
-                ],
-                "summary": "Get balances for multiple addresses",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Comma-separated list of addresses",
-                        "name": "addresses",
-                        "in": "query",
-                        "required": true
-                    }
+                    "Accounts"
                 ],
+                "summary": "List HD Accounts",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.GetBalancesSuccessResponse"
+                            "$ref": "#/definitions/models.ListAccountsSuccessResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
                             "$ref": "#/definitions/models.GenericFailureResponse"
                         }
@@ -53,33 +56,41 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.GenericFailureResponse"
                         }
                     }
-                }
-            }
-        },
-        "/balance/{address}": {
-            "get": {
-                "description": "Retrieves the MNEE balance for a specific wallet address",
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Registers a BIP32 extended key as an account and scans it for used addresses. A master xprv is derived along m/44'/236'/0'; any other key is taken as the account node. Only the account xpub is stored.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Balance"
+                    "Accounts"
                 ],
-                "summary": "Get balance for a single address",
+                "summary": "Register HD Account",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Wallet Address",
-                        "name": "address",
-                        "in": "path",
-                        "required": true
+                        "description": "Account",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateAccountRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.GetBalanceSuccessResponse"
+                            "$ref": "#/definitions/models.AccountSuccessResponse"
                         }
                     },
                     "400": {
@@ -88,87 +99,102 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.GenericFailureResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/models.GenericFailureResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/config": {
-            "get": {
-                "description": "Returns current MNEE system configuration.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Config"
-                ],
-                "summary": "Get System Config",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/models.GetConfigSuccessResponse"
+                            "$ref": "#/definitions/models.GenericFailureResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/models.GenericFailureResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
                         "schema": {
                             "$ref": "#/definitions/models.GenericFailureResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
             }
         },
-        "/transaction": {
+        "/accounts/{id}": {
             "get": {
-                "description": "Retrieves transaction history for one or more addresses with pagination",
+                "description": "Returns one of the caller's HD accounts with every address the last scan covers.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "History"
+                    "Accounts"
                 ],
-                "summary": "Get transaction history for multiple addresses",
+                "summary": "Get HD Account",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Comma-separated list of Wallet Addresses",
-                        "name": "addresses",
-                        "in": "query",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Starting score (default 0)",
-                        "name": "fromScore",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Limit (default 10)",
-                        "name": "limit",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.GetHistorySuccessResponse"
+                            "$ref": "#/definitions/models.AccountSuccessResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
                             "$ref": "#/definitions/models.GenericFailureResponse"
                         }
@@ -179,89 +205,116 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.GenericFailureResponse"
                         }
                     }
-                }
-            }
-        },
-        "/transaction/partial-sign": {
-            "post": {
-                "description": "Builds and signs a transaction *only* with the provided WIFs. Returns hex.",
-                "consumes": [
-                    "application/json"
-                ],
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Forgets one of the caller's HD accounts. Funds on its addresses are not moved.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Transaction"
+                    "Accounts"
                 ],
-                "summary": "Partial Sign Transaction",
+                "summary": "Delete HD Account",
                 "parameters": [
                     {
-                        "description": "Transfer Parameters",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.TransferRequest"
-                        }
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.PartialSignSuccessResponse"
+                            "$ref": "#/definitions/models.SuccessResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/models.GenericFailureResponse"
                         }
                     },
-                    "422": {
-                        "description": "Unprocessable Entity",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/models.GenericFailureResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.GenericFailureResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
             }
         },
-        "/transaction/status/{ticketId}": {
+        "/accounts/{id}/balance": {
             "get": {
-                "description": "Polls the status of a transaction ticket until it is processed or times out.",
+                "description": "Returns the combined balance of every address the account's last scan covers, and the balance of each.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Transaction"
+                    "Accounts"
                 ],
-                "summary": "Poll Ticket Status",
+                "summary": "Get HD Account balance",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Ticket ID",
-                        "name": "ticketId",
+                        "description": "Account ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Scan the account first",
+                        "name": "rescan",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.GetTicketSuccessResponse"
+                            "$ref": "#/definitions/models.AccountBalanceSuccessResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
                             "$ref": "#/definitions/models.GenericFailureResponse"
                         }
@@ -271,39 +324,75 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.GenericFailureResponse"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
             }
         },
-        "/transaction/submit-rawtx": {
-            "post": {
-                "description": "Submits a pre-signed raw transaction hex and waits for the cosigner. Returns the final TxID.",
-                "consumes": [
-                    "application/json"
-                ],
+        "/accounts/{id}/history": {
+            "get": {
+                "description": "Retrieves the transaction history of every address the account's last scan covers, with pagination.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Transfer"
+                    "Accounts"
                 ],
-                "summary": "Submit Raw Transaction (Synchronous)",
+                "summary": "Get HD Account history",
                 "parameters": [
                     {
-                        "description": "Raw Hex",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.RawTxRequest"
-                        }
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Starting score (default 0)",
+                        "name": "fromScore",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Scan the account first",
+                        "name": "rescan",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.TransferSyncSuccessResponse"
+                            "$ref": "#/definitions/models.GetHistorySuccessResponse"
                         }
                     },
                     "400": {
@@ -312,8 +401,26 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.GenericFailureResponse"
                         }
                     },
-                    "422": {
-                        "description": "Unprocessable Entity",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
                             "$ref": "#/definitions/models.GenericFailureResponse"
                         }
@@ -323,101 +430,155 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.GenericFailureResponse"
                         }
-                    }
-                }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
             }
         },
-        "/transaction/submit-rawtx-async": {
+        "/accounts/{id}/scan": {
             "post": {
-                "description": "Submits a pre-signed raw transaction hex and returns a ticket ID immediately.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Scans the account's receive and change chains for addresses used since the last scan, stopping after gapLimit consecutive unused addresses.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Transfer"
+                    "Accounts"
                 ],
-                "summary": "Submit Raw Transaction (Asynchronous)",
+                "summary": "Scan HD Account",
                 "parameters": [
                     {
-                        "description": "Raw Hex",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.RawTxRequest"
-                        }
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.TransferAsyncSuccessResponse"
+                            "$ref": "#/definitions/models.AccountSuccessResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/models.GenericFailureResponse"
                         }
                     },
-                    "422": {
-                        "description": "Unprocessable Entity",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/models.GenericFailureResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
                         "schema": {
                             "$ref": "#/definitions/models.GenericFailureResponse"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
             }
         },
-        "/transaction/transfer": {
-            "post": {
-                "description": "Executes a transfer using multiple WIFs and waits for cosigner response. Returns final TxID.",
-                "consumes": [
-                    "application/json"
-                ],
+        "/accounts/{id}/utxos": {
+            "get": {
+                "description": "Retrieves the unspent transaction outputs of every address the account's last scan covers.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Transfer"
+                    "Accounts"
                 ],
-                "summary": "Synchronous Transfer",
+                "summary": "Get HD Account UTXOs",
                 "parameters": [
                     {
-                        "description": "Transfer Parameters",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.TransferRequest"
-                        }
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Scan the account first",
+                        "name": "rescan",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.TransferSyncSuccessResponse"
+                            "$ref": "#/definitions/models.GetUtxosSuccessResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/models.GenericFailureResponse"
                         }
                     },
-                    "422": {
-                        "description": "Unprocessable Entity",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
                             "$ref": "#/definitions/models.GenericFailureResponse"
                         }
@@ -427,39 +588,57 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.GenericFailureResponse"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
             }
         },
-        "/transaction/transfer-async": {
-            "post": {
-                "description": "Executes a transfer using multiple WIFs and returns a ticket ID immediately for polling.",
-                "consumes": [
-                    "application/json"
-                ],
+        "/balance": {
+            "get": {
+                "description": "Retrieves balances for a comma-separated list of addresses",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Transfer"
+                    "Balance"
                 ],
-                "summary": "Asynchronous Transfer",
+                "summary": "Get balances for multiple addresses",
                 "parameters": [
                     {
-                        "description": "Transfer Parameters",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.TransferRequest"
-                        }
+                        "type": "string",
+                        "description": "Comma-separated list of addresses",
+                        "name": "addresses",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.TransferAsyncSuccessResponse"
+                            "$ref": "#/definitions/models.GetBalancesSuccessResponse"
                         }
                     },
                     "400": {
@@ -468,8 +647,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.GenericFailureResponse"
                         }
                     },
-                    "422": {
-                        "description": "Unprocessable Entity",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
                             "$ref": "#/definitions/models.GenericFailureResponse"
                         }
@@ -479,26 +670,49 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.GenericFailureResponse"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
             }
         },
-        "/utxos/all": {
+        "/balance/{address}": {
             "get": {
-                "description": "Retrieves all unspent transaction outputs for one or more addresses",
+                "description": "Retrieves the MNEE balance for a specific wallet address",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "UTXO"
+                    "Balance"
                 ],
-                "summary": "Get all UTXOs for multiple addresses",
+                "summary": "Get balance for a single address",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Comma-separated list of Wallet Addresses",
-                        "name": "addresses",
-                        "in": "query",
+                        "description": "Wallet Address",
+                        "name": "address",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -506,7 +720,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.GetUtxosSuccessResponse"
+                            "$ref": "#/definitions/models.GetBalanceSuccessResponse"
                         }
                     },
                     "400": {
@@ -515,51 +729,71 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.GenericFailureResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/models.GenericFailureResponse"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
             }
         },
-        "/utxos/paginated": {
+        "/config": {
             "get": {
-                "description": "Retrieves paginated unspent transaction outputs for one or more addresses",
+                "description": "Returns current MNEE system configuration.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "UTXO"
-                ],
-                "summary": "Get paginated UTXOs for multiple addresses",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Comma-separated list of Wallet Addresses",
-                        "name": "addresses",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page number (default 1)",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page size (default 10)",
-                        "name": "size",
-                        "in": "query"
-                    }
+                    "Config"
                 ],
+                "summary": "Get System Config",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.GetUtxosSuccessResponse"
+                            "$ref": "#/definitions/models.GetConfigSuccessResponse"
                         }
                     },
                     "400": {
@@ -568,71 +802,2529 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.GenericFailureResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/models.GenericFailureResponse"
                         }
-                    }
-                }
-            }
-        }
-    },
-    "definitions": {
-        "handlers.RawTxRequest": {
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/fees/quote": {
+            "get": {
+                "description": "Resolves the cosigner fee tier for an amount and returns the fee and total debit. When addresses are given, also reports whether their combined balance covers the total.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fees"
+                ],
+                "summary": "Quote transfer fee",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Amount in display units (e.g. 0.29)",
+                        "name": "amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Amount in atomic units, instead of amount",
+                        "name": "atomicAmount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated list of source addresses",
+                        "name": "addresses",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FeeQuoteSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Resolves the cosigner fee tier for a set of recipients. Amounts sent back to one of the source addresses do not count towards the tier, as when the transfer is built.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fees"
+                ],
+                "summary": "Quote multi-recipient transfer fee",
+                "parameters": [
+                    {
+                        "description": "Quote Parameters",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FeeQuoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FeeQuoteSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/transaction": {
+            "get": {
+                "description": "Retrieves transaction history for one or more addresses with pagination",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "History"
+                ],
+                "summary": "Get transaction history for multiple addresses",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated list of Wallet Addresses",
+                        "name": "addresses",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Starting score (default 0)",
+                        "name": "fromScore",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.GetHistorySuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/transaction/build": {
+            "post": {
+                "description": "Selects UTXOs from the source addresses and builds an unsigned transfer including the MNEE fee and change outputs. No private keys are involved: each input carries its sighash preimage and digest for an external signer, whose unlocking script is ` + "`" + `\u003csignature+sighashFlags\u003e \u003ccompressed pubkey\u003e` + "`" + `. Submit the signed hex to /transaction/submit-rawtx.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transaction"
+                ],
+                "summary": "Build Unsigned Transaction",
+                "parameters": [
+                    {
+                        "description": "Build Parameters",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BuildTransactionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BuildTransactionSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "402": {
+                        "description": "Payment Required",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/transaction/partial-sign": {
+            "post": {
+                "description": "Builds and signs a transaction *only* with the provided WIFs or managed walletIds. Returns hex. With dryRun set, the inputs, outputs, fee and change of the signed transaction are returned as well.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transaction"
+                ],
+                "summary": "Partial Sign Transaction",
+                "parameters": [
+                    {
+                        "description": "Transfer Parameters",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Signed hex, or models.DryRunSuccessResponse with dryRun",
+                        "schema": {
+                            "$ref": "#/definitions/models.PartialSignSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "402": {
+                        "description": "Payment Required",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/transaction/status/{ticketId}": {
+            "get": {
+                "description": "Polls the status of a transaction ticket until it is processed or times out. Only the client that submitted the ticket can read it; other tickets are reported as not found.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transaction"
+                ],
+                "summary": "Poll Ticket Status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Ticket ID",
+                        "name": "ticketId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.GetTicketSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/transaction/status/{ticketId}/stream": {
+            "get": {
+                "description": "Streams Server-Sent Events for a transaction ticket. A ` + "`" + `status` + "`" + ` event carrying the ticket is sent on every status change, including new errors. The stream closes after the ticket reaches a final status. It also closes after an ` + "`" + `error` + "`" + ` event, after a ` + "`" + `timeout` + "`" + ` event once the timeout elapses, or when the server shuts down. A ticket the cosigner has not recorded yet is waited for; a ticket another client submitted is answered with 404.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Transaction"
+                ],
+                "summary": "Stream Ticket Status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Ticket ID",
+                        "name": "ticketId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Polling interval, e.g. 2s (minimum 500ms)",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Closes the stream after this long, e.g. 5m (maximum 30m)",
+                        "name": "timeout",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "event: status",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/transaction/submit-rawtx": {
+            "post": {
+                "description": "Submits a pre-signed raw transaction hex and waits for the cosigner. Returns the final TxID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transfer"
+                ],
+                "summary": "Submit Raw Transaction (Synchronous)",
+                "parameters": [
+                    {
+                        "description": "Raw Hex",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RawTxRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response when a request is retried with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransferSyncSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "402": {
+                        "description": "Payment Required",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/transaction/submit-rawtx-async": {
+            "post": {
+                "description": "Submits a pre-signed raw transaction hex and returns a ticket ID immediately. Ticket updates are posted to callbackUrl, signed with callbackSecret.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transfer"
+                ],
+                "summary": "Submit Raw Transaction (Asynchronous)",
+                "parameters": [
+                    {
+                        "description": "Raw Hex",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RawTxRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response when a request is retried with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransferAsyncSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "402": {
+                        "description": "Payment Required",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/transaction/ticket/{id}": {
+            "get": {
+                "description": "Returns the current state of a transaction ticket without waiting. Returns 404 while the cosigner has no record of the ticket, and for tickets another client submitted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transaction"
+                ],
+                "summary": "Get Ticket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Ticket ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.GetTicketSuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/transaction/transfer": {
+            "post": {
+                "description": "Executes a transfer using multiple WIFs, or managed walletIds, and waits for cosigner response. Returns final TxID. With dryRun set, the transfer is built and signed but not submitted, and the preview is returned instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transfer"
+                ],
+                "summary": "Synchronous Transfer",
+                "parameters": [
+                    {
+                        "description": "Transfer Parameters",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TransferRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response when a request is retried with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Submitted transfer, or models.DryRunSuccessResponse with dryRun",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransferSyncSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "402": {
+                        "description": "Payment Required",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/transaction/transfer-async": {
+            "post": {
+                "description": "Executes a transfer using multiple WIFs, or managed walletIds, and returns a ticket ID immediately for polling. Ticket updates are posted to callbackUrl, signed with callbackSecret. With dryRun set, the transfer is built and signed but not submitted, and the preview is returned instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transfer"
+                ],
+                "summary": "Asynchronous Transfer",
+                "parameters": [
+                    {
+                        "description": "Transfer Parameters",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TransferRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response when a request is retried with the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ticket, or models.DryRunSuccessResponse with dryRun",
+                        "schema": {
+                            "$ref": "#/definitions/models.TransferAsyncSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "402": {
+                        "description": "Payment Required",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/transfers": {
+            "get": {
+                "description": "Lists the transfers and raw transactions the caller submitted through this service, newest first, with their recipients, atomic amounts, fee, ticket, txid, errors and status history. Status is pending until the ticket is seen, then broadcasting, success or failed; unknown means the cosigner did not answer in time, and stuck that the ticket has not finished long after submission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transfer"
+                ],
+                "summary": "List Transfers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "pending, broadcasting, success, failed, unknown or stuck",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "transfer or rawtx",
+                        "name": "kind",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ticket ID",
+                        "name": "ticketId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transaction ID",
+                        "name": "txid",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Source or recipient address",
+                        "name": "address",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Submitted at or after, RFC 3339",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Submitted before, RFC 3339",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, maximum 100)",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ListTransfersSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/utxos/all": {
+            "get": {
+                "description": "Retrieves all unspent transaction outputs for one or more addresses",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "UTXO"
+                ],
+                "summary": "Get all UTXOs for multiple addresses",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated list of Wallet Addresses",
+                        "name": "addresses",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.GetUtxosSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/utxos/paginated": {
+            "get": {
+                "description": "Retrieves paginated unspent transaction outputs for one or more addresses",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "UTXO"
+                ],
+                "summary": "Get paginated UTXOs for multiple addresses",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated list of Wallet Addresses",
+                        "name": "addresses",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 10)",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.GetUtxosSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/wallets": {
+            "get": {
+                "description": "Lists the caller's managed wallets with their addresses and balances.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallets"
+                ],
+                "summary": "List Wallets",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ListWalletsSuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Imports a WIF as a managed wallet, or generates a new key when no WIF is given. The key is stored encrypted with the master key; transfers reference the wallet by ID. Only an explicit export returns it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallets"
+                ],
+                "summary": "Create Wallet",
+                "parameters": [
+                    {
+                        "description": "Wallet",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateWalletRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.WalletSuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/wallets/{id}": {
+            "get": {
+                "description": "Returns one of the caller's managed wallets with its address and balance.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallets"
+                ],
+                "summary": "Get Wallet",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WalletSuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Deletes one of the caller's managed wallets and its encrypted key. Funds on its address are not moved, so a wallet still holding MNEE, or a generated wallet whose key was never exported, is only deleted with force=true.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallets"
+                ],
+                "summary": "Delete Wallet",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Delete even if the address holds MNEE or the generated key was never exported",
+                        "name": "force",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/wallets/{id}/export": {
+            "post": {
+                "description": "Returns the WIF of one of the caller's managed wallets, as a backup before deleting it or to move the key elsewhere. The export time is recorded on the wallet. Needs the export:wallets scope, and answers 403 WALLET_EXPORT_DISABLED unless WALLET_EXPORT_ENABLED is set.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallets"
+                ],
+                "summary": "Export Wallet",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WalletExportSuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/webhooks/mnee": {
+            "post": {
+                "description": "Receives ticket callbacks from the cosigner when the webhook relay is enabled. The callback must carry the relay's callback secret. The ticket is then fetched from the cosigner, with the API key of the tenant that created it, and its status is relayed to subscribers only if it changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Cosigner Callback",
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.GenericFailureResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handlers.BuildTransactionRequest": {
+            "type": "object",
+            "required": [
+                "changeAddress",
+                "recipients",
+                "sourceAddresses"
+            ],
+            "properties": {
+                "changeAddress": {
+                    "type": "string",
+                    "example": "1G6CB3Ch4zFkPmuhZzEyChQmrQPfi86qk3"
+                },
+                "recipients": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/handlers.TransferRecipient"
+                    }
+                },
+                "sourceAddresses": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "1G6CB3Ch4zFkPmuhZzEyChQmrQPfi86qk3"
+                    ]
+                }
+            }
+        },
+        "handlers.CreateAccountRequest": {
+            "type": "object",
+            "required": [
+                "extendedKey"
+            ],
+            "properties": {
+                "extendedKey": {
+                    "type": "string",
+                    "example": "xpub6C..."
+                },
+                "gapLimit": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 1,
+                    "example": 20
+                },
+                "label": {
+                    "type": "string",
+                    "example": "treasury"
+                }
+            }
+        },
+        "handlers.CreateWalletRequest": {
+            "type": "object",
+            "properties": {
+                "label": {
+                    "type": "string",
+                    "example": "payouts"
+                },
+                "wif": {
+                    "type": "string",
+                    "example": "L1dRKo..."
+                }
+            }
+        },
+        "handlers.FeeQuoteRequest": {
+            "type": "object",
+            "required": [
+                "recipients"
+            ],
+            "properties": {
+                "recipients": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/handlers.TransferRecipient"
+                    }
+                },
+                "sourceAddresses": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "1G6CB3Ch4zFkPmuhZzEyChQmrQPfi86qk3"
+                    ]
+                }
+            }
+        },
+        "handlers.RawTxRequest": {
+            "type": "object",
+            "required": [
+                "rawTxHex"
+            ],
+            "properties": {
+                "callbackSecret": {
+                    "type": "string",
+                    "example": "s3cr3t"
+                },
+                "callbackUrl": {
+                    "type": "string",
+                    "example": "https://example.com/mnee/callback"
+                },
+                "rawTxHex": {
+                    "type": "string",
+                    "example": "01000000..."
+                }
+            }
+        },
+        "handlers.TransferRecipient": {
+            "type": "object",
+            "required": [
+                "address"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "example": "1G6CB3Ch4zFkPmuhZzEyChQmrQPfi86qk3"
+                },
+                "amount": {
+                    "type": "string",
+                    "example": "0.1"
+                },
+                "atomicAmount": {
+                    "type": "integer",
+                    "example": 10000
+                }
+            }
+        },
+        "handlers.TransferRequest": {
+            "type": "object",
+            "required": [
+                "request"
+            ],
+            "properties": {
+                "callbackSecret": {
+                    "type": "string",
+                    "example": "s3cr3t"
+                },
+                "callbackUrl": {
+                    "description": "CallbackUrl and CallbackSecret only apply to asynchronous transfers.",
+                    "type": "string",
+                    "example": "https://example.com/mnee/callback"
+                },
+                "dryRun": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "request": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.TransferRecipient"
+                    }
+                },
+                "walletIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "w_5f2c9a..."
+                    ]
+                },
+                "wifs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "L1dRKo...",
+                        "K2..."
+                    ]
+                }
+            }
+        },
+        "ledger.Kind": {
+            "type": "string",
+            "enum": [
+                "transfer",
+                "rawtx"
+            ],
+            "x-enum-varnames": [
+                "KindTransfer",
+                "KindRawTx"
+            ]
+        },
+        "ledger.Recipient": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "example": "1G6CB3Ch4zFkPmuhZzEyChQmrQPfi86qk3"
+                },
+                "atomicAmount": {
+                    "type": "integer",
+                    "example": 10000
+                }
+            }
+        },
+        "ledger.Status": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "broadcasting",
+                "success",
+                "failed",
+                "unknown",
+                "stuck"
+            ],
+            "x-enum-varnames": [
+                "StatusPending",
+                "StatusBroadcasting",
+                "StatusSuccess",
+                "StatusFailed",
+                "StatusUnknown",
+                "StatusStuck"
+            ]
+        },
+        "ledger.Transfer": {
+            "type": "object",
+            "properties": {
+                "async": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "atomicAmount": {
+                    "type": "integer",
+                    "example": 10000
+                },
+                "atomicFee": {
+                    "type": "integer",
+                    "example": 1000
+                },
+                "clientId": {
+                    "type": "string",
+                    "example": "payments"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "errorCode": {
+                    "type": "string",
+                    "example": "INSUFFICIENT_BALANCE"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string",
+                    "example": "tr_3b9f0c..."
+                },
+                "kind": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ledger.Kind"
+                        }
+                    ],
+                    "example": "transfer"
+                },
+                "recipients": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ledger.Recipient"
+                    }
+                },
+                "requestId": {
+                    "type": "string",
+                    "example": "4f1c2a..."
+                },
+                "sources": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ledger.Status"
+                        }
+                    ],
+                    "example": "success"
+                },
+                "ticketId": {
+                    "type": "string",
+                    "example": "a1b2c3..."
+                },
+                "transitions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ledger.Transition"
+                    }
+                },
+                "txid": {
+                    "type": "string",
+                    "example": "9e2f..."
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "ledger.Transition": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ledger.Status"
+                        }
+                    ],
+                    "example": "success"
+                }
+            }
+        },
+        "models.AccountAddress": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "example": "1G6CB3Ch4zFkPmuhZzEyChQmrQPfi86qk3"
+                },
+                "chain": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "index": {
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "models.AccountBalanceSuccessResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.AccountBalanceWrapper"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.AccountBalanceWrapper": {
+            "type": "object",
+            "properties": {
+                "addresses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.BalanceDataDTO"
+                    }
+                },
+                "atomicBalance": {
+                    "type": "integer",
+                    "example": 100000
+                },
+                "balance": {
+                    "type": "string",
+                    "example": "1"
+                }
+            }
+        },
+        "models.AccountSuccessResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.AccountSummary"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.AccountSummary": {
+            "type": "object",
+            "properties": {
+                "addresses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AccountAddress"
+                    }
+                },
+                "changeNext": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "gapLimit": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "id": {
+                    "type": "string",
+                    "example": "a_9d41e7..."
+                },
+                "label": {
+                    "type": "string",
+                    "example": "treasury"
+                },
+                "nextChangeAddress": {
+                    "$ref": "#/definitions/models.AccountAddress"
+                },
+                "nextReceiveAddress": {
+                    "$ref": "#/definitions/models.AccountAddress"
+                },
+                "receiveNext": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "scannedAt": {
+                    "type": "string"
+                },
+                "xpub": {
+                    "type": "string",
+                    "example": "xpub6C..."
+                }
+            }
+        },
+        "models.BuildTransactionSuccessResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.BuildTransactionWrapper"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.BuildTransactionWrapper": {
             "type": "object",
-            "required": [
-                "rawTxHex"
-            ],
             "properties": {
+                "atomicChange": {
+                    "type": "integer",
+                    "example": 70900
+                },
+                "atomicFee": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "change": {
+                    "type": "string",
+                    "example": "0.709"
+                },
+                "fee": {
+                    "type": "string",
+                    "example": "0.001"
+                },
+                "inputs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TransferInputSummary"
+                    }
+                },
+                "outputs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TransferOutputSummary"
+                    }
+                },
                 "rawTxHex": {
                     "type": "string",
                     "example": "01000000..."
+                },
+                "sighashFlags": {
+                    "type": "integer",
+                    "example": 193
                 }
             }
         },
-        "handlers.TransferRequest": {
+        "models.FeeQuoteSuccessResponse": {
             "type": "object",
-            "required": [
-                "request",
-                "wifs"
-            ],
             "properties": {
-                "request": {
+                "data": {
+                    "$ref": "#/definitions/models.FeeQuoteWrapper"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.FeeQuoteWrapper": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "0.29"
+                },
+                "atomicAmount": {
+                    "type": "integer",
+                    "example": 29000
+                },
+                "atomicBalance": {
+                    "type": "integer",
+                    "example": 100000
+                },
+                "atomicFee": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "atomicTotal": {
+                    "type": "integer",
+                    "example": 29100
+                },
+                "balance": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "fee": {
+                    "type": "string",
+                    "example": "0.001"
+                },
+                "recipients": {
                     "type": "array",
                     "items": {
-                        "type": "object",
-                        "required": [
-                            "address",
-                            "amount"
-                        ],
-                        "properties": {
-                            "address": {
-                                "type": "string",
-                                "example": "1G6CB3Ch4zFkPmuhZzEyChQmrQPfi86qk3"
-                            },
-                            "amount": {
-                                "type": "number",
-                                "example": 0.1
-                            }
-                        }
+                        "$ref": "#/definitions/models.RecipientAmount"
                     }
                 },
-                "wifs": {
+                "sourceAddresses": {
                     "type": "array",
                     "items": {
                         "type": "string"
-                    },
-                    "example": [
-                        "L1dRKo...",
-                        "K2..."
-                    ]
+                    }
+                },
+                "sufficient": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "tier": {
+                    "$ref": "#/definitions/types.Fee"
+                },
+                "total": {
+                    "type": "string",
+                    "example": "0.291"
                 }
             }
         },
         "models.GenericFailureResponse": {
             "type": "object",
             "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "INSUFFICIENT_BALANCE"
+                },
                 "message": {
                     "type": "string",
                     "example": "error description"
@@ -738,6 +3430,48 @@ const docTemplate = `{
                 }
             }
         },
+        "models.ListAccountsSuccessResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AccountSummary"
+                    }
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.ListTransfersSuccessResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.TransfersPage"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.ListWalletsSuccessResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WalletSummary"
+                    }
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
         "models.PartialSignSuccessResponse": {
             "type": "object",
             "properties": {
@@ -756,12 +3490,50 @@ const docTemplate = `{
                 "rawTxHex": {
                     "type": "string",
                     "example": "02000000..."
+                },
+                "recipients": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RecipientAmount"
+                    }
+                }
+            }
+        },
+        "models.RecipientAmount": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "example": "1G6CB3Ch4zFkPmuhZzEyChQmrQPfi86qk3"
+                },
+                "amount": {
+                    "type": "string",
+                    "example": "0.29"
+                },
+                "atomicAmount": {
+                    "type": "integer",
+                    "example": 29000
+                }
+            }
+        },
+        "models.SuccessResponse": {
+            "type": "object",
+            "properties": {
+                "success": {
+                    "type": "boolean",
+                    "example": true
                 }
             }
         },
         "models.TicketIdWrapper": {
             "type": "object",
             "properties": {
+                "recipients": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RecipientAmount"
+                    }
+                },
                 "ticketId": {
                     "type": "string",
                     "example": "KKJS-..."
@@ -780,11 +3552,162 @@ const docTemplate = `{
                 }
             }
         },
+        "models.TransferInputSummary": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "example": "1G6CB3Ch4zFkPmuhZzEyChQmrQPfi86qk3"
+                },
+                "amount": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "atomicAmount": {
+                    "type": "integer",
+                    "example": 100000
+                },
+                "lockingScript": {
+                    "type": "string",
+                    "example": "0063036f7264..."
+                },
+                "outpoint": {
+                    "type": "string",
+                    "example": "9a3c..._0"
+                },
+                "preimage": {
+                    "type": "string",
+                    "example": "01000000..."
+                },
+                "satoshis": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "sighash": {
+                    "type": "string",
+                    "example": "5f2c..."
+                }
+            }
+        },
+        "models.TransferOutputSummary": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "example": "1G6CB3Ch4zFkPmuhZzEyChQmrQPfi86qk3"
+                },
+                "amount": {
+                    "type": "string",
+                    "example": "0.29"
+                },
+                "atomicAmount": {
+                    "type": "integer",
+                    "example": 29000
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "recipient",
+                        "fee",
+                        "change"
+                    ],
+                    "example": "recipient"
+                },
+                "vout": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "models.TransferResultWrapper": {
+            "type": "object",
+            "properties": {
+                "recipients": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RecipientAmount"
+                    }
+                },
+                "txhex": {
+                    "type": "string"
+                },
+                "txid": {
+                    "type": "string"
+                }
+            }
+        },
         "models.TransferSyncSuccessResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/types.TransferResponseDTO"
+                    "$ref": "#/definitions/models.TransferResultWrapper"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.TransfersPage": {
+            "type": "object",
+            "properties": {
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "size": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "transfers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ledger.Transfer"
+                    }
+                }
+            }
+        },
+        "models.WalletExport": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "example": "1G6CB3Ch4zFkPmuhZzEyChQmrQPfi86qk3"
+                },
+                "exportedAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "w_5f2c9a..."
+                },
+                "wif": {
+                    "type": "string",
+                    "example": "L1dRKo..."
+                }
+            }
+        },
+        "models.WalletExportSuccessResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.WalletExport"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.WalletSuccessResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.WalletSummary"
                 },
                 "success": {
                     "type": "boolean",
@@ -792,6 +3715,41 @@ const docTemplate = `{
                 }
             }
         },
+        "models.WalletSummary": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "example": "1G6CB3Ch4zFkPmuhZzEyChQmrQPfi86qk3"
+                },
+                "atomicBalance": {
+                    "type": "integer",
+                    "example": 100000
+                },
+                "balance": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "exportedAt": {
+                    "type": "string"
+                },
+                "generated": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "id": {
+                    "type": "string",
+                    "example": "w_5f2c9a..."
+                },
+                "label": {
+                    "type": "string",
+                    "example": "payouts"
+                }
+            }
+        },
         "types.BalanceDataDTO": {
             "type": "object",
             "properties": {
@@ -944,12 +3902,6 @@ const docTemplate = `{
                 "action_requested": {
                     "type": "string"
                 },
-                "callback_secret": {
-                    "type": "string"
-                },
-                "callback_url": {
-                    "type": "string"
-                },
                 "createdAt": {
                     "type": "string"
                 },
@@ -1024,17 +3976,6 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
-        },
-        "types.TransferResponseDTO": {
-            "type": "object",
-            "properties": {
-                "txhex": {
-                    "type": "string"
-                },
-                "txid": {
-                    "type": "string"
-                }
-            }
         }
     },
     "securityDefinitions": {
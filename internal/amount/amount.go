@@ -0,0 +1,95 @@
+// Package amount converts MNEE amounts between display units and atomic units
+// without going through floating point.
+package amount
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidAmount = errors.New("invalid amount")
+
+var ErrExcessPrecision = errors.New("amount has more decimal places than the token supports")
+
+var ErrAmountOverflow = errors.New("amount is too large")
+
+var decimalPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+
+// Decimal is an amount in display units as sent by the client. It decodes from
+// a JSON string or a JSON number, keeping the literal text so that values such
+// as 0.29 are never rounded through float64.
+type Decimal string
+
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*d = Decimal(s)
+		return nil
+	}
+
+	if len(data) == 0 || (data[0] != '-' && (data[0] < '0' || data[0] > '9')) {
+		return errors.New("amount must be a decimal string or number")
+	}
+
+	*d = Decimal(data)
+	return nil
+}
+
+// Parse converts a display amount such as "0.29" to atomic units for a token
+// with the given number of decimals. Amounts with more significant decimal
+// places than decimals are rejected rather than truncated.
+func Parse(value string, decimals uint8) (uint64, error) {
+	value = strings.TrimSpace(value)
+	if !decimalPattern.MatchString(value) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+
+	whole, fraction, _ := strings.Cut(value, ".")
+	fraction = strings.TrimRight(fraction, "0")
+	if len(fraction) > int(decimals) {
+		return 0, fmt.Errorf("%w: %q allows at most %d", ErrExcessPrecision, value, decimals)
+	}
+
+	digits := whole + fraction + strings.Repeat("0", int(decimals)-len(fraction))
+	atomic, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+	if !atomic.IsUint64() {
+		return 0, fmt.Errorf("%w: %q", ErrAmountOverflow, value)
+	}
+
+	return atomic.Uint64(), nil
+}
+
+// Format renders atomic units in display units, e.g. 29000 with 5 decimals is "0.29".
+func Format(atomic uint64, decimals uint8) string {
+	digits := strconv.FormatUint(atomic, 10)
+	if decimals == 0 {
+		return digits
+	}
+
+	if len(digits) <= int(decimals) {
+		digits = strings.Repeat("0", int(decimals)-len(digits)+1) + digits
+	}
+
+	split := len(digits) - int(decimals)
+	whole, fraction := digits[:split], strings.TrimRight(digits[split:], "0")
+	if fraction == "" {
+		return whole
+	}
+
+	return whole + "." + fraction
+}
@@ -0,0 +1,89 @@
+package amount
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value    string
+		decimals uint8
+		want     uint64
+		err      error
+	}{
+		{value: "0.29", decimals: 5, want: 29000},
+		{value: "1", decimals: 5, want: 100000},
+		{value: "1.", decimals: 5, err: ErrInvalidAmount},
+		{value: " 0.1 ", decimals: 5, want: 10000},
+		{value: "0.00001", decimals: 5, want: 1},
+		{value: "0.100000", decimals: 5, want: 10000},
+		{value: "0.000001", decimals: 5, err: ErrExcessPrecision},
+		{value: "12", decimals: 0, want: 12},
+		{value: "1.5", decimals: 0, err: ErrExcessPrecision},
+		{value: "0", decimals: 5, want: 0},
+		{value: "-1", decimals: 5, err: ErrInvalidAmount},
+		{value: "1e5", decimals: 5, err: ErrInvalidAmount},
+		{value: "", decimals: 5, err: ErrInvalidAmount},
+		{value: ".5", decimals: 5, err: ErrInvalidAmount},
+		{value: "18446744073709551615", decimals: 0, want: 18446744073709551615},
+		{value: "18446744073709551616", decimals: 0, err: ErrAmountOverflow},
+		{value: "184467440737095.51616", decimals: 5, err: ErrAmountOverflow},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.value, tt.decimals)
+		if !errors.Is(err, tt.err) || got != tt.want {
+			t.Errorf("Parse(%q, %d) = %d, %v; want %d, %v", tt.value, tt.decimals, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		atomic   uint64
+		decimals uint8
+		want     string
+	}{
+		{atomic: 29000, decimals: 5, want: "0.29"},
+		{atomic: 100000, decimals: 5, want: "1"},
+		{atomic: 1, decimals: 5, want: "0.00001"},
+		{atomic: 0, decimals: 5, want: "0"},
+		{atomic: 123456789, decimals: 5, want: "1234.56789"},
+		{atomic: 12, decimals: 0, want: "12"},
+		{atomic: 18446744073709551615, decimals: 5, want: "184467440737095.51615"},
+	}
+
+	for _, tt := range tests {
+		if got := Format(tt.atomic, tt.decimals); got != tt.want {
+			t.Errorf("Format(%d, %d) = %q, want %q", tt.atomic, tt.decimals, got, tt.want)
+		}
+		if back, err := Parse(tt.want, tt.decimals); err != nil || back != tt.atomic {
+			t.Errorf("Parse(Format(%d)) = %d, %v", tt.atomic, back, err)
+		}
+	}
+}
+
+func TestDecimalUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		json    string
+		want    Decimal
+		wantErr bool
+	}{
+		{json: `"0.29"`, want: "0.29"},
+		{json: `0.29`, want: "0.29"},
+		{json: `null`, want: ""},
+		{json: `-1`, want: "-1"},
+		{json: `true`, wantErr: true},
+		{json: `{}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		var got Decimal
+		err := json.Unmarshal([]byte(tt.json), &got)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("Unmarshal(%s) = %q, %v; want %q", tt.json, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []byte
+		data     []byte
+	}{
+		{name: "new file", data: []byte("first")},
+		{name: "replaces", existing: []byte("a much longer old content"), data: []byte("new")},
+		{name: "empty", existing: []byte("old"), data: []byte{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "store.json")
+			if tt.existing != nil {
+				if err := os.WriteFile(path, tt.existing, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := Write(path, tt.data); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(tt.data) {
+				t.Fatalf("got %q, want %q", got, tt.data)
+			}
+
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if mode := info.Mode().Perm(); mode != 0o600 {
+				t.Fatalf("got mode %o, want 600", mode)
+			}
+
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 1 {
+				t.Fatalf("left %d files behind", len(entries)-1)
+			}
+		})
+	}
+}
+
+func TestWriteMissingDirectory(t *testing.T) {
+	if err := Write(filepath.Join(t.TempDir(), "missing", "store.json"), []byte("data")); err == nil {
+		t.Fatal("wrote into a missing directory")
+	}
+}
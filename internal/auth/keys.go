@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	ScopeReadBalance   = "read:balance"
+	ScopeReadHistory   = "read:history"
+	ScopeWriteTransfer = "write:transfer"
+	ScopeWriteRawTx    = "write:rawtx"
+	ScopeWriteWallets  = "write:wallets"
+	ScopeExportWallets = "export:wallets"
+)
+
+var Scopes = []string{ScopeReadBalance, ScopeReadHistory, ScopeWriteTransfer, ScopeWriteRawTx, ScopeWriteWallets, ScopeExportWallets}
+
+// Client is an API client identified by its key.
+type Client struct {
+	ID     string
+	Scopes []string
+}
+
+func (c *Client) HasScope(scope string) bool {
+	return slices.Contains(c.Scopes, scope)
+}
+
+type clientKey struct {
+	hash   []byte
+	client Client
+}
+
+// KeyStore holds client keys as SHA-256 hashes only; the keys themselves are
+// never configured or kept.
+type KeyStore struct {
+	keys []clientKey
+}
+
+// ParseKeys reads client keys from spec, a semicolon-separated list of
+// "<clientId>:<sha256 hex of key>:<scope>,<scope>" entries.
+func ParseKeys(spec string) (*KeyStore, error) {
+	store := &KeyStore{}
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid API key entry %q: want <clientId>:<sha256 hex>:<scopes>", entry)
+		}
+
+		hash, err := hex.DecodeString(parts[1])
+		if err != nil || len(hash) != sha256.Size {
+			return nil, fmt.Errorf("invalid key hash for client %q: want 64 hex characters", parts[0])
+		}
+
+		client := Client{ID: parts[0]}
+		for _, scope := range strings.Split(parts[2], ",") {
+			scope = strings.TrimSpace(scope)
+			if !slices.Contains(Scopes, scope) {
+				return nil, fmt.Errorf("unknown scope %q for client %q", scope, parts[0])
+			}
+			client.Scopes = append(client.Scopes, scope)
+		}
+
+		for _, existing := range store.keys {
+			if existing.client.ID == client.ID {
+				return nil, fmt.Errorf("duplicate API client %q", client.ID)
+			}
+			if bytes.Equal(existing.hash, hash) {
+				return nil, fmt.Errorf("clients %q and %q share the same key hash", existing.client.ID, client.ID)
+			}
+		}
+
+		store.keys = append(store.keys, clientKey{hash: hash, client: client})
+	}
+
+	return store, nil
+}
+
+func (s *KeyStore) Len() int {
+	return len(s.keys)
+}
+
+// Lookup returns the client whose key is key. Every configured hash is
+// compared in constant time, so timing does not reveal which one matched.
+func (s *KeyStore) Lookup(key string) (*Client, bool) {
+	hash := sha256.Sum256([]byte(key))
+
+	var found *Client
+	for i := range s.keys {
+		if subtle.ConstantTimeCompare(hash[:], s.keys[i].hash) == 1 {
+			found = &s.keys[i].client
+		}
+	}
+	return found, found != nil
+}
+
+// HashKey returns the hex SHA-256 of key, as configured in API_KEYS.
+func HashKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+// GenerateKey returns a new random client key.
+func GenerateKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "mnee_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseKeys(t *testing.T) {
+	alice, bob := HashKey("alice-key"), HashKey("bob-key")
+
+	tests := []struct {
+		name    string
+		spec    string
+		clients int
+		wantErr string
+	}{
+		{name: "empty", spec: ""},
+		{name: "one client", spec: "alice:" + alice + ":read:balance", clients: 1},
+		{name: "several clients", spec: " alice:" + alice + ":read:balance,write:transfer ; bob:" + bob + ":read:history;", clients: 2},
+		{name: "missing scopes", spec: "alice:" + alice, wantErr: "invalid API key entry"},
+		{name: "missing client", spec: ":" + alice + ":read:balance", wantErr: "invalid API key entry"},
+		{name: "hash not hex", spec: "alice:" + strings.Repeat("z", 64) + ":read:balance", wantErr: "invalid key hash"},
+		{name: "short hash", spec: "alice:" + alice[:32] + ":read:balance", wantErr: "invalid key hash"},
+		{name: "unknown scope", spec: "alice:" + alice + ":read:everything", wantErr: "unknown scope"},
+		{name: "no scope", spec: "alice:" + alice + ":", wantErr: "unknown scope"},
+		{name: "duplicate client", spec: "alice:" + alice + ":read:balance;alice:" + bob + ":read:balance", wantErr: "duplicate API client"},
+		{name: "shared hash", spec: "alice:" + alice + ":read:balance;bob:" + alice + ":read:balance", wantErr: "share the same key hash"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := ParseKeys(tt.spec)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if keys.Len() != tt.clients {
+				t.Fatalf("got %d clients, want %d", keys.Len(), tt.clients)
+			}
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	keys, err := ParseKeys("alice:" + HashKey("alice-key") + ":read:balance,write:transfer;bob:" + HashKey("bob-key") + ":read:history")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		key    string
+		client string
+		scopes []string
+	}{
+		{name: "alice", key: "alice-key", client: "alice", scopes: []string{ScopeReadBalance, ScopeWriteTransfer}},
+		{name: "bob", key: "bob-key", client: "bob", scopes: []string{ScopeReadHistory}},
+		{name: "unknown key", key: "mallory-key"},
+		{name: "empty key", key: ""},
+		// The configured hash is not itself a key.
+		{name: "hash as key", key: HashKey("alice-key")},
+		{name: "key with trailing space", key: "alice-key "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, ok := keys.Lookup(tt.key)
+			if ok != (tt.client != "") {
+				t.Fatalf("got found %v, want %v", ok, tt.client != "")
+			}
+			if !ok {
+				return
+			}
+			if client.ID != tt.client {
+				t.Fatalf("got client %q, want %q", client.ID, tt.client)
+			}
+			for _, scope := range Scopes {
+				want := false
+				for _, s := range tt.scopes {
+					want = want || s == scope
+				}
+				if client.HasScope(scope) != want {
+					t.Fatalf("HasScope(%s) = %v, want %v", scope, !want, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateKey(t *testing.T) {
+	first, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == second || !strings.HasPrefix(first, "mnee_") || len(first) != len("mnee_")+64 {
+		t.Fatalf("got keys %q and %q", first, second)
+	}
+	if len(HashKey(first)) != 64 {
+		t.Fatalf("got hash %q", HashKey(first))
+	}
+}
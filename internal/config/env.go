@@ -1,24 +1,158 @@
 package config
 
 import (
+	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Port       string
-	MneeEnv    string
-	MneeApiKey string
+	Port            string
+	LogFormat       string
+	LogLevel        string
+	ShutdownTimeout time.Duration
+	ShutdownDelay   time.Duration
+
+	ReadHeaderTimeout time.Duration
+	IdleTimeout       time.Duration
+
+	ReadyCheckInterval time.Duration
+	ReadyMaxAge        time.Duration
+
+	TracesExporter string
+	TracesFile     string
+	ServiceName    string
+
+	MneeEnv     string
+	MneeApiKey  string
+	MneeFixture string
+
+	SDKReadTimeout  time.Duration
+	SDKWriteTimeout time.Duration
+	SDKTimeouts     string
+	SDKRetries      int
+	SDKRetryBackoff time.Duration
+	BreakerFailures int
+	BreakerCooldown time.Duration
+
+	CacheBackend     string
+	CacheTTLConfig   time.Duration
+	CacheTTLBalances time.Duration
+	CacheTTLUtxos    time.Duration
+
+	ApiKeys      string
+	AuthDisabled bool
+	Tenants      string
+
+	TrustedProxies            []string
+	RateLimitClientTransfers  int
+	RateLimitAddressTransfers int
+	RateLimitIPReads          int
+	MaxQueryAddresses         int
+
+	WalletMasterKey     string
+	WalletStorePath     string
+	WalletExportEnabled bool
+
+	HDAccountStorePath string
+	HDGapLimit         int
+
+	IdempotencyTTL       time.Duration
+	IdempotencyStorePath string
+
+	LedgerBackend string
+	LedgerPath    string
+
+	ReconcileInterval    time.Duration
+	ReconcileMaxInterval time.Duration
+	ReconcileStuckAfter  time.Duration
+	ReconcileMaxAge      time.Duration
+
+	WebhookPublicURL      string
+	WebhookCallbackSecret string
+	WebhookSubscribers    []string
+	WebhookSigningSecret  string
+	WebhookCallbackHosts  []string
+	WebhookMaxAttempts    int
+	WebhookTimeout        time.Duration
 }
 
 func LoadConfig() *Config {
 	_ = godotenv.Load()
 
 	return &Config{
-		Port:       getEnv("PORT", "8080"),
-		MneeEnv:    getEnv("MNEE_ENV", "sandbox"),
-		MneeApiKey: getEnv("MNEE_API_KEY", ""),
+		Port:            getEnv("PORT", "8080"),
+		LogFormat:       getEnv("LOG_FORMAT", "json"),
+		LogLevel:        getEnv("LOG_LEVEL", "info"),
+		ShutdownTimeout: getDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
+		ShutdownDelay:   getDuration("SHUTDOWN_DELAY", 0),
+
+		ReadHeaderTimeout: getDuration("READ_HEADER_TIMEOUT", 10*time.Second),
+		IdleTimeout:       getDuration("IDLE_TIMEOUT", 2*time.Minute),
+
+		ReadyCheckInterval: getDuration("READY_CHECK_INTERVAL", 15*time.Second),
+		ReadyMaxAge:        getDuration("READY_MAX_AGE", time.Minute),
+
+		TracesExporter: getEnv("OTEL_TRACES_EXPORTER", "none"),
+		TracesFile:     getEnv("OTEL_TRACES_FILE", ""),
+		ServiceName:    getEnv("OTEL_SERVICE_NAME", "mnee-sdk-api"),
+
+		MneeEnv:     getEnv("MNEE_ENV", "sandbox"),
+		MneeApiKey:  getEnv("MNEE_API_KEY", ""),
+		MneeFixture: getEnv("MNEE_FIXTURE", ""),
+
+		SDKReadTimeout:  getDuration("SDK_READ_TIMEOUT", 10*time.Second),
+		SDKWriteTimeout: getDuration("SDK_WRITE_TIMEOUT", 60*time.Second),
+		SDKTimeouts:     getEnv("SDK_TIMEOUTS", ""),
+		SDKRetries:      getInt("SDK_RETRIES", 2),
+		SDKRetryBackoff: getDuration("SDK_RETRY_BACKOFF", 200*time.Millisecond),
+		BreakerFailures: getInt("BREAKER_FAILURES", 5),
+		BreakerCooldown: getDuration("BREAKER_COOLDOWN", 30*time.Second),
+
+		CacheBackend:     getEnv("CACHE_BACKEND", "memory"),
+		CacheTTLConfig:   getDuration("CACHE_TTL_CONFIG", 5*time.Minute),
+		CacheTTLBalances: getDuration("CACHE_TTL_BALANCES", 5*time.Second),
+		CacheTTLUtxos:    getDuration("CACHE_TTL_UTXOS", 5*time.Second),
+
+		ApiKeys:      getEnv("API_KEYS", ""),
+		AuthDisabled: getBool("AUTH_DISABLED", false),
+		Tenants:      getEnv("TENANTS", ""),
+
+		TrustedProxies:            getList("TRUSTED_PROXIES"),
+		RateLimitClientTransfers:  getInt("RATE_LIMIT_CLIENT_TRANSFERS", 60),
+		RateLimitAddressTransfers: getInt("RATE_LIMIT_ADDRESS_TRANSFERS", 20),
+		RateLimitIPReads:          getInt("RATE_LIMIT_IP_READS", 600),
+		MaxQueryAddresses:         getInt("MAX_QUERY_ADDRESSES", 50),
+
+		WalletMasterKey:     getEnv("WALLET_MASTER_KEY", ""),
+		WalletStorePath:     getEnv("WALLET_STORE_PATH", ""),
+		WalletExportEnabled: getBool("WALLET_EXPORT_ENABLED", false),
+
+		HDAccountStorePath: getEnv("HD_ACCOUNT_STORE_PATH", ""),
+		HDGapLimit:         getInt("HD_GAP_LIMIT", 20),
+
+		IdempotencyTTL:       getDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+		IdempotencyStorePath: getEnv("IDEMPOTENCY_STORE_PATH", ""),
+
+		LedgerBackend: getEnv("LEDGER_BACKEND", "sqlite"),
+		LedgerPath:    getEnv("LEDGER_PATH", "ledger.db"),
+
+		ReconcileInterval:    getDuration("RECONCILE_INTERVAL", 15*time.Second),
+		ReconcileMaxInterval: getDuration("RECONCILE_MAX_INTERVAL", 5*time.Minute),
+		ReconcileStuckAfter:  getDuration("RECONCILE_STUCK_AFTER", 30*time.Minute),
+		ReconcileMaxAge:      getDuration("RECONCILE_MAX_AGE", 7*24*time.Hour),
+
+		WebhookPublicURL:      getEnv("WEBHOOK_PUBLIC_URL", ""),
+		WebhookCallbackSecret: getEnv("WEBHOOK_CALLBACK_SECRET", ""),
+		WebhookSubscribers:    getList("WEBHOOK_SUBSCRIBERS"),
+		WebhookSigningSecret:  getEnv("WEBHOOK_SIGNING_SECRET", ""),
+		WebhookCallbackHosts:  getList("WEBHOOK_CALLBACK_HOSTS"),
+		WebhookMaxAttempts:    getInt("WEBHOOK_MAX_ATTEMPTS", 5),
+		WebhookTimeout:        getDuration("WEBHOOK_TIMEOUT", 10*time.Second),
 	}
 }
 
@@ -28,3 +162,53 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+func getDuration(key string, fallback time.Duration) time.Duration {
+	value, exists := os.LookupEnv(key)
+	if !exists || value == "" {
+		return fallback
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("Invalid %s %q: %v", key, value, err)
+	}
+	return duration
+}
+
+func getInt(key string, fallback int) int {
+	value, exists := os.LookupEnv(key)
+	if !exists || value == "" {
+		return fallback
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Fatalf("Invalid %s %q: %v", key, value, err)
+	}
+	return n
+}
+
+// getList splits a comma-separated variable, dropping empty entries.
+func getList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			values = append(values, trimmed)
+		}
+	}
+	return values
+}
+
+func getBool(key string, fallback bool) bool {
+	value, exists := os.LookupEnv(key)
+	if !exists || value == "" {
+		return fallback
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Fatalf("Invalid %s %q: %v", key, value, err)
+	}
+	return b
+}
@@ -0,0 +1,175 @@
+// Package fakemnee serves the HTTP API the MNEE SDK talks to, backed by an
+// in-memory services.FakeClient ledger.
+package fakemnee
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
+)
+
+type transferBody struct {
+	RawTx          string  `json:"rawtx"`
+	CallbackURL    *string `json:"callback_url,omitempty"`
+	CallbackSecret *string `json:"callback_secret,omitempty"`
+}
+
+// NewServer returns a handler exposing ledger under the SDK's routes. When
+// token is non-empty, requests must carry it as auth_token or receive 403.
+func NewServer(ledger *services.FakeClient, token string) http.Handler {
+	r := gin.Default()
+	r.Use(requireToken(token))
+
+	r.GET("/v1/config", func(c *gin.Context) {
+		config, err := ledger.GetConfig(c.Request.Context())
+		if err != nil {
+			fail(c, http.StatusInternalServerError, err)
+			return
+		}
+		c.JSON(http.StatusOK, config)
+	})
+
+	r.POST("/v2/balance", func(c *gin.Context) {
+		addresses, ok := bindAddresses(c)
+		if !ok {
+			return
+		}
+
+		balances, err := ledger.GetBalances(c.Request.Context(), addresses)
+		if err != nil {
+			fail(c, http.StatusInternalServerError, err)
+			return
+		}
+		c.JSON(http.StatusOK, balances)
+	})
+
+	r.POST("/v1/utxos", func(c *gin.Context) {
+		addresses, ok := bindAddresses(c)
+		if !ok {
+			return
+		}
+
+		txos, err := ledger.GetUnspentTxos(c.Request.Context(), addresses)
+		if err != nil {
+			fail(c, http.StatusInternalServerError, err)
+			return
+		}
+		c.JSON(http.StatusOK, txos)
+	})
+
+	r.POST("/v2/utxos", func(c *gin.Context) {
+		addresses, ok := bindAddresses(c)
+		if !ok {
+			return
+		}
+
+		page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+		size, _ := strconv.Atoi(c.DefaultQuery("size", "10"))
+		txos, err := ledger.GetPaginatedUnspentTxos(c.Request.Context(), addresses, page, size)
+		if err != nil {
+			fail(c, http.StatusInternalServerError, err)
+			return
+		}
+		c.JSON(http.StatusOK, txos)
+	})
+
+	r.POST("/v1/sync", func(c *gin.Context) {
+		addresses, ok := bindAddresses(c)
+		if !ok {
+			return
+		}
+
+		from, _ := strconv.Atoi(c.DefaultQuery("from", "0"))
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "0"))
+		history, err := ledger.GetSpecificTransactionHistory(c.Request.Context(), addresses, from, limit)
+		if err != nil {
+			fail(c, http.StatusInternalServerError, err)
+			return
+		}
+		c.JSON(http.StatusOK, history)
+	})
+
+	r.POST("/v1/transfer", func(c *gin.Context) {
+		rawTxHex, body, ok := bindTransfer(c)
+		if !ok {
+			return
+		}
+
+		resp, err := ledger.SubmitRawTxSync(c.Request.Context(), rawTxHex)
+		if err != nil {
+			fail(c, http.StatusBadRequest, err)
+			return
+		}
+
+		// The SDK reads the cosigned transaction back from the rawtx field.
+		c.JSON(http.StatusOK, gin.H{"rawtx": body.RawTx, "txid": *resp.Txid})
+	})
+
+	r.POST("/v2/transfer", func(c *gin.Context) {
+		rawTxHex, body, ok := bindTransfer(c)
+		if !ok {
+			return
+		}
+
+		ticketID, err := ledger.SubmitRawTxAsync(c.Request.Context(), rawTxHex, body.CallbackURL, body.CallbackSecret)
+		if err != nil {
+			fail(c, http.StatusBadRequest, err)
+			return
+		}
+		c.String(http.StatusOK, *ticketID)
+	})
+
+	r.GET("/v2/ticket", func(c *gin.Context) {
+		ticket, ok := ledger.Ticket(c.Query("ticketID"))
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"message": "record not found"})
+			return
+		}
+		c.JSON(http.StatusOK, ticket)
+	})
+
+	return r
+}
+
+func requireToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token != "" && c.Query("auth_token") != token {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "forbidden"})
+			return
+		}
+		c.Next()
+	}
+}
+
+func bindAddresses(c *gin.Context) ([]string, bool) {
+	var addresses []string
+	if err := c.ShouldBindJSON(&addresses); err != nil {
+		fail(c, http.StatusBadRequest, err)
+		return nil, false
+	}
+	return addresses, true
+}
+
+func bindTransfer(c *gin.Context) (string, *transferBody, bool) {
+	var body transferBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		fail(c, http.StatusBadRequest, err)
+		return "", nil, false
+	}
+
+	txBytes, err := base64.StdEncoding.DecodeString(body.RawTx)
+	if err != nil {
+		fail(c, http.StatusBadRequest, err)
+		return "", nil, false
+	}
+
+	return hex.EncodeToString(txBytes), &body, true
+}
+
+func fail(c *gin.Context, status int, err error) {
+	c.JSON(status, gin.H{"message": err.Error()})
+}
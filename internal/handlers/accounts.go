@@ -0,0 +1,406 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/amount"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/hdwallet"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/models" // Import models
+)
+
+type CreateAccountRequest struct {
+	ExtendedKey string `json:"extendedKey" binding:"required" example:"xpub6C..."`
+	Label       string `json:"label,omitempty" example:"treasury"`
+	GapLimit    int    `json:"gapLimit,omitempty" binding:"omitempty,min=1,max=1000" example:"20"`
+}
+
+// CreateAccount godoc
+// @Summary      Register HD Account
+// @Description  Registers a BIP32 extended key as an account and scans it for used addresses. A master xprv is derived along m/44'/236'/0'; any other key is taken as the account node. Only the account xpub is stored.
+// @Tags         Accounts
+// @Accept       json
+// @Produce      json
+// @Param        request body CreateAccountRequest true "Account"
+// @Success      201     {object} models.AccountSuccessResponse
+// @Failure      422     {object} models.GenericFailureResponse
+// @Failure      400     {object} models.GenericFailureResponse
+// @Failure      401     {object} models.GenericFailureResponse
+// @Failure      403     {object} models.GenericFailureResponse
+// @Failure      409     {object} models.GenericFailureResponse
+// @Failure      502     {object} models.GenericFailureResponse
+// @Failure      503     {object} models.GenericFailureResponse
+// @Failure      504     {object} models.GenericFailureResponse
+// @Security     ApiKeyAuth
+// @Router       /accounts [post]
+func CreateAccount(c *gin.Context) {
+	var req CreateAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, models.GenericFailureResponse{Success: false, Code: models.CodeInvalidRequestBody, Message: "Unprocessable Entity: " + err.Error()})
+		return
+	}
+
+	account, err := hdwallet.Instance.New(req.ExtendedKey, req.Label, req.GapLimit, clientID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	account, err = hdwallet.Scan(c.Request.Context(), mneeClient(c), *account)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if err := hdwallet.Instance.Add(*account); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	summary, err := accountSummary(*account, false)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    summary,
+	})
+}
+
+// ListAccounts godoc
+// @Summary      List HD Accounts
+// @Description  Lists the caller's HD accounts with their next receive and change addresses.
+// @Tags         Accounts
+// @Produce      json
+// @Success      200  {object}  models.ListAccountsSuccessResponse
+// @Failure      401  {object}  models.GenericFailureResponse
+// @Failure      403  {object}  models.GenericFailureResponse
+// @Failure      429  {object}  models.GenericFailureResponse
+// @Failure      500  {object}  models.GenericFailureResponse
+// @Security     ApiKeyAuth
+// @Router       /accounts [get]
+func ListAccounts(c *gin.Context) {
+	accounts := hdwallet.Instance.List(clientID(c))
+
+	summaries := make([]models.AccountSummary, 0, len(accounts))
+	for _, account := range accounts {
+		summary, err := accountSummary(account, false)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		summaries = append(summaries, *summary)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    summaries,
+	})
+}
+
+// GetAccount godoc
+// @Summary      Get HD Account
+// @Description  Returns one of the caller's HD accounts with every address the last scan covers.
+// @Tags         Accounts
+// @Produce      json
+// @Param        id   path      string  true  "Account ID"
+// @Success      200  {object}  models.AccountSuccessResponse
+// @Failure      401  {object}  models.GenericFailureResponse
+// @Failure      403  {object}  models.GenericFailureResponse
+// @Failure      404  {object}  models.GenericFailureResponse
+// @Failure      429  {object}  models.GenericFailureResponse
+// @Failure      500  {object}  models.GenericFailureResponse
+// @Security     ApiKeyAuth
+// @Router       /accounts/{id} [get]
+func GetAccount(c *gin.Context) {
+	account, err := hdwallet.Instance.Get(c.Param("id"), clientID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	summary, err := accountSummary(*account, true)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    summary,
+	})
+}
+
+// ScanAccount godoc
+// @Summary      Scan HD Account
+// @Description  Scans the account's receive and change chains for addresses used since the last scan, stopping after gapLimit consecutive unused addresses.
+// @Tags         Accounts
+// @Produce      json
+// @Param        id   path      string  true  "Account ID"
+// @Success      200  {object}  models.AccountSuccessResponse
+// @Failure      401  {object}  models.GenericFailureResponse
+// @Failure      403  {object}  models.GenericFailureResponse
+// @Failure      404  {object}  models.GenericFailureResponse
+// @Failure      502  {object}  models.GenericFailureResponse
+// @Failure      503  {object}  models.GenericFailureResponse
+// @Failure      504  {object}  models.GenericFailureResponse
+// @Security     ApiKeyAuth
+// @Router       /accounts/{id}/scan [post]
+func ScanAccount(c *gin.Context) {
+	account, err := hdwallet.Instance.Get(c.Param("id"), clientID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	account, err = rescanAccount(c, account)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	summary, err := accountSummary(*account, true)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    summary,
+	})
+}
+
+// DeleteAccount godoc
+// @Summary      Delete HD Account
+// @Description  Forgets one of the caller's HD accounts. Funds on its addresses are not moved.
+// @Tags         Accounts
+// @Produce      json
+// @Param        id   path      string  true  "Account ID"
+// @Success      200  {object}  models.SuccessResponse
+// @Failure      401  {object}  models.GenericFailureResponse
+// @Failure      403  {object}  models.GenericFailureResponse
+// @Failure      404  {object}  models.GenericFailureResponse
+// @Security     ApiKeyAuth
+// @Router       /accounts/{id} [delete]
+func DeleteAccount(c *gin.Context) {
+	if err := hdwallet.Instance.Delete(c.Param("id"), clientID(c)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// GetAccountBalance godoc
+// @Summary      Get HD Account balance
+// @Description  Returns the combined balance of every address the account's last scan covers, and the balance of each.
+// @Tags         Accounts
+// @Produce      json
+// @Param        id      path      string  true   "Account ID"
+// @Param        rescan  query     bool    false  "Scan the account first"
+// @Success      200     {object}  models.AccountBalanceSuccessResponse
+// @Failure      401     {object}  models.GenericFailureResponse
+// @Failure      403     {object}  models.GenericFailureResponse
+// @Failure      404     {object}  models.GenericFailureResponse
+// @Failure      429     {object}  models.GenericFailureResponse
+// @Failure      502     {object}  models.GenericFailureResponse
+// @Failure      503     {object}  models.GenericFailureResponse
+// @Failure      504     {object}  models.GenericFailureResponse
+// @Failure      500     {object}  models.GenericFailureResponse
+// @Security     ApiKeyAuth
+// @Router       /accounts/{id}/balance [get]
+func GetAccountBalance(c *gin.Context) {
+	addresses, ok := accountAddresses(c)
+	if !ok {
+		return
+	}
+
+	config, err := mneeClient(c).GetConfig(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	balances, err := mneeClient(c).GetBalances(c.Request.Context(), addresses)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var atomicBalance uint64
+	for _, balance := range balances {
+		atomicBalance += uint64(math.Round(balance.Amt))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"atomicBalance": atomicBalance,
+			"balance":       amount.Format(atomicBalance, config.Decimals),
+			"addresses":     balances,
+		},
+	})
+}
+
+// GetAccountUtxos godoc
+// @Summary      Get HD Account UTXOs
+// @Description  Retrieves the unspent transaction outputs of every address the account's last scan covers.
+// @Tags         Accounts
+// @Produce      json
+// @Param        id      path      string  true   "Account ID"
+// @Param        rescan  query     bool    false  "Scan the account first"
+// @Success      200     {object}  models.GetUtxosSuccessResponse
+// @Failure      401     {object}  models.GenericFailureResponse
+// @Failure      403     {object}  models.GenericFailureResponse
+// @Failure      404     {object}  models.GenericFailureResponse
+// @Failure      429     {object}  models.GenericFailureResponse
+// @Failure      502     {object}  models.GenericFailureResponse
+// @Failure      503     {object}  models.GenericFailureResponse
+// @Failure      504     {object}  models.GenericFailureResponse
+// @Failure      500     {object}  models.GenericFailureResponse
+// @Security     ApiKeyAuth
+// @Router       /accounts/{id}/utxos [get]
+func GetAccountUtxos(c *gin.Context) {
+	addresses, ok := accountAddresses(c)
+	if !ok {
+		return
+	}
+
+	txos, err := mneeClient(c).GetUnspentTxos(c.Request.Context(), addresses)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": txos})
+}
+
+// GetAccountHistory godoc
+// @Summary      Get HD Account history
+// @Description  Retrieves the transaction history of every address the account's last scan covers, with pagination.
+// @Tags         Accounts
+// @Produce      json
+// @Param        id         path      string  true   "Account ID"
+// @Param        fromScore  query     int     false  "Starting score (default 0)"
+// @Param        limit      query     int     false  "Limit (default 10)"
+// @Param        rescan     query     bool    false  "Scan the account first"
+// @Success      200        {object}  models.GetHistorySuccessResponse
+// @Failure      400        {object}  models.GenericFailureResponse
+// @Failure      401        {object}  models.GenericFailureResponse
+// @Failure      403        {object}  models.GenericFailureResponse
+// @Failure      404        {object}  models.GenericFailureResponse
+// @Failure      429        {object}  models.GenericFailureResponse
+// @Failure      502        {object}  models.GenericFailureResponse
+// @Failure      503        {object}  models.GenericFailureResponse
+// @Failure      504        {object}  models.GenericFailureResponse
+// @Failure      500        {object}  models.GenericFailureResponse
+// @Security     ApiKeyAuth
+// @Router       /accounts/{id}/history [get]
+func GetAccountHistory(c *gin.Context) {
+	fromScore, limit, ok := historyPage(c)
+	if !ok {
+		return
+	}
+
+	addresses, ok := accountAddresses(c)
+	if !ok {
+		return
+	}
+
+	history, err := mneeClient(c).GetSpecificTransactionHistory(c.Request.Context(), addresses, fromScore, limit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"history": history,
+		},
+	})
+}
+
+// accountAddresses loads the account in the path, rescanning it first when
+// asked to, and returns its addresses. It writes the error response itself.
+func accountAddresses(c *gin.Context) ([]string, bool) {
+	account, err := hdwallet.Instance.Get(c.Param("id"), clientID(c))
+	if err != nil {
+		respondError(c, err)
+		return nil, false
+	}
+
+	if c.Query("rescan") == "true" {
+		if account, err = rescanAccount(c, account); err != nil {
+			respondError(c, err)
+			return nil, false
+		}
+	}
+
+	derived, err := account.Addresses()
+	if err != nil {
+		respondError(c, err)
+		return nil, false
+	}
+
+	addresses := make([]string, 0, len(derived))
+	for _, address := range derived {
+		addresses = append(addresses, address.Address)
+	}
+	return addresses, true
+}
+
+func rescanAccount(c *gin.Context, account *hdwallet.Account) (*hdwallet.Account, error) {
+	scanned, err := hdwallet.Scan(c.Request.Context(), mneeClient(c), *account)
+	if err != nil {
+		return nil, err
+	}
+	if err := hdwallet.Instance.Update(*scanned); err != nil {
+		return nil, err
+	}
+	return scanned, nil
+}
+
+func accountSummary(account hdwallet.Account, withAddresses bool) (*models.AccountSummary, error) {
+	nextReceive, err := account.NextAddress(hdwallet.ChainReceive)
+	if err != nil {
+		return nil, err
+	}
+	nextChange, err := account.NextAddress(hdwallet.ChainChange)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.AccountSummary{
+		ID:                 account.ID,
+		Label:              account.Label,
+		Xpub:               account.Xpub,
+		GapLimit:           account.GapLimit,
+		ReceiveNext:        account.ReceiveNext,
+		ChangeNext:         account.ChangeNext,
+		NextReceiveAddress: accountAddress(*nextReceive),
+		NextChangeAddress:  accountAddress(*nextChange),
+		CreatedAt:          account.CreatedAt,
+		ScannedAt:          account.ScannedAt,
+	}
+
+	if withAddresses {
+		addresses, err := account.Addresses()
+		if err != nil {
+			return nil, err
+		}
+		for _, address := range addresses {
+			summary.Addresses = append(summary.Addresses, accountAddress(address))
+		}
+	}
+
+	return summary, nil
+}
+
+func accountAddress(address hdwallet.Address) models.AccountAddress {
+	return models.AccountAddress{Address: address.Address, Chain: address.Chain, Index: address.Index}
+}
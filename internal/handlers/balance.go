@@ -2,12 +2,10 @@ package handlers
 
 import (
 	"net/http"
-	"strings"
 
-	"github.com/bsv-blockchain/go-sdk/script"
 	"github.com/gin-gonic/gin"
 	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
-	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/models" // Import models
 )
 
 // GetBalance godoc
@@ -18,24 +16,31 @@ import (
 // @Param        address   path      string  true  "Wallet Address"
 // @Success      200       {object}  models.GetBalanceSuccessResponse
 // @Failure      400       {object}  models.GenericFailureResponse
+// @Failure      401       {object}  models.GenericFailureResponse
+// @Failure      403       {object}  models.GenericFailureResponse
+// @Failure      429       {object}  models.GenericFailureResponse
+// @Failure      502       {object}  models.GenericFailureResponse
+// @Failure      503       {object}  models.GenericFailureResponse
+// @Failure      504       {object}  models.GenericFailureResponse
 // @Failure      500       {object}  models.GenericFailureResponse
+// @Security     ApiKeyAuth
 // @Router       /balance/{address} [get]
 func GetBalance(c *gin.Context) {
 	_address := c.Param("address")
 	if _address == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Address is required"})
+		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeValidationFailed, Message: "Address is required"})
 		return
 	}
 
-	address, err := script.NewAddressFromString(_address)
+	address, err := parseAddress(c, _address)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid wallet address: " + _address})
+		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeInvalidAddress, Message: invalidAddress("Invalid wallet address", _address, err)})
 		return
 	}
 
-	balances, err := services.Instance.GetBalances(c.Request.Context(), []string{address.AddressString})
+	balances, err := mneeClient(c).GetBalances(c.Request.Context(), []string{address.AddressString})
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -55,37 +60,24 @@ func GetBalance(c *gin.Context) {
 // @Param        addresses  query     string  true  "Comma-separated list of addresses"
 // @Success      200        {object}  models.GetBalancesSuccessResponse
 // @Failure      400        {object}  models.GenericFailureResponse
+// @Failure      401        {object}  models.GenericFailureResponse
+// @Failure      403        {object}  models.GenericFailureResponse
+// @Failure      429        {object}  models.GenericFailureResponse
+// @Failure      502        {object}  models.GenericFailureResponse
+// @Failure      503        {object}  models.GenericFailureResponse
+// @Failure      504        {object}  models.GenericFailureResponse
 // @Failure      500        {object}  models.GenericFailureResponse
+// @Security     ApiKeyAuth
 // @Router       /balance [get]
 func GetBalances(c *gin.Context) {
-	addrStr := c.Query("addresses")
-	if addrStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "addresses query parameter is required"})
+	addresses, ok := queryAddresses(c)
+	if !ok {
 		return
 	}
 
-	rawAddresses := strings.Split(addrStr, ",")
-	var addresses []string
-	for _, addr := range rawAddresses {
-		trimmed := strings.TrimSpace(addr)
-		if trimmed != "" {
-			address, err := script.NewAddressFromString(trimmed)
-			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Invalid wallet address: " + trimmed})
-				return
-			}
-			addresses = append(addresses, address.AddressString)
-		}
-	}
-
-	if len(addresses) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "No valid addresses provided"})
-		return
-	}
-
-	balances, err := services.Instance.GetBalances(c.Request.Context(), addresses)
+	balances, err := mneeClient(c).GetBalances(c.Request.Context(), addresses)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		respondError(c, err)
 		return
 	}
 
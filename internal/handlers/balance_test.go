@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/models"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
+)
+
+func TestGetBalance(t *testing.T) {
+	_, funded := newKey(t)
+	_, empty := newKey(t)
+
+	tests := []struct {
+		name    string
+		address string
+		fail    error
+		status  int
+		code    string
+		amount  float64
+	}{
+		{name: "funded", address: funded, status: http.StatusOK, amount: 250000},
+		{name: "empty", address: empty, status: http.StatusOK, amount: 0},
+		{name: "invalid address", address: "not-an-address", status: http.StatusBadRequest, code: models.CodeInvalidAddress},
+		{name: "cosigner down", address: funded, fail: errors.New("status received from mnee-cosigner -> 503"), status: http.StatusBadGateway, code: models.CodeUpstreamUnavailable},
+		{name: "cosigner rejects", address: funded, fail: errors.New("bad request"), status: http.StatusBadRequest, code: models.CodeUpstreamRejected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := services.NewFakeClient()
+			if err := client.Fund(funded, 250000); err != nil {
+				t.Fatal(err)
+			}
+			client.FailWith("GetBalances", tt.fail)
+
+			status, response := serve(t, client, http.MethodGet, "/balance/:address", "/balance/"+tt.address, GetBalance, nil)
+			if status != tt.status || response.Code != tt.code {
+				t.Fatalf("got %d %q (%s), want %d %q", status, response.Code, response.Message, tt.status, tt.code)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var balance mnee.BalanceDataDTO
+			if err := json.Unmarshal(response.Data, &balance); err != nil {
+				t.Fatal(err)
+			}
+			if balance.Address == nil || *balance.Address != tt.address || balance.Amt != tt.amount {
+				t.Fatalf("got balance %+v, want %v for %s", balance, tt.amount, tt.address)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/amount"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/models" // Import models
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
+)
+
+type BuildTransactionRequest struct {
+	SourceAddresses []string            `json:"sourceAddresses" binding:"required,min=1" example:"1G6CB3Ch4zFkPmuhZzEyChQmrQPfi86qk3"`
+	Recipients      []TransferRecipient `json:"recipients" binding:"required,min=1,dive"`
+	ChangeAddress   string              `json:"changeAddress" binding:"required" example:"1G6CB3Ch4zFkPmuhZzEyChQmrQPfi86qk3"`
+}
+
+// BuildTransaction godoc
+// @Summary      Build Unsigned Transaction
+// @Description  Selects UTXOs from the source addresses and builds an unsigned transfer including the MNEE fee and change outputs. No private keys are involved: each input carries its sighash preimage and digest for an external signer, whose unlocking script is `<signature+sighashFlags> <compressed pubkey>`. Submit the signed hex to /transaction/submit-rawtx.
+// @Tags         Transaction
+// @Accept       json
+// @Produce      json
+// @Param        request body BuildTransactionRequest true "Build Parameters"
+// @Success      200     {object} models.BuildTransactionSuccessResponse
+// @Failure      422     {object} models.GenericFailureResponse
+// @Failure      400     {object} models.GenericFailureResponse
+// @Failure      401     {object} models.GenericFailureResponse
+// @Failure      402     {object} models.GenericFailureResponse
+// @Failure      403     {object} models.GenericFailureResponse
+// @Failure      429     {object} models.GenericFailureResponse
+// @Failure      502     {object} models.GenericFailureResponse
+// @Failure      503     {object} models.GenericFailureResponse
+// @Failure      504     {object} models.GenericFailureResponse
+// @Failure      500     {object} models.GenericFailureResponse
+// @Security     ApiKeyAuth
+// @Router       /transaction/build [post]
+func BuildTransaction(c *gin.Context) {
+	var req BuildTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, models.GenericFailureResponse{Success: false, Code: models.CodeInvalidRequestBody, Message: "Unprocessable Entity: " + err.Error()})
+		return
+	}
+
+	var sources []string
+	for _, addr := range req.SourceAddresses {
+		address, err := parseAddress(c, addr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeInvalidAddress, Message: invalidAddress("Invalid source address", addr, err)})
+			return
+		}
+		sources = append(sources, address.AddressString)
+	}
+
+	changeAddress, err := parseAddress(c, req.ChangeAddress)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeInvalidAddress, Message: invalidAddress("Invalid change address", req.ChangeAddress, err)})
+		return
+	}
+
+	config, err := mneeClient(c).GetConfig(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	dtos, _, ok := prepareRecipients(c, req.Recipients, config.Decimals)
+	if !ok {
+		return
+	}
+
+	plan, err := services.BuildUnsignedTransfer(c.Request.Context(), mneeClient(c), sources, dtos, changeAddress.AddressString)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": models.BuildTransactionWrapper{
+			RawTxHex:     plan.Tx.Hex(),
+			SighashFlags: uint32(services.TransferSighashFlags),
+			Inputs:       inputSummaries(plan.Inputs, config.Decimals),
+			Outputs:      outputSummaries(plan.Outputs, config.Decimals),
+			AtomicFee:    plan.Fee,
+			Fee:          amount.Format(plan.Fee, config.Decimals),
+			AtomicChange: plan.Change,
+			Change:       amount.Format(plan.Change, config.Decimals),
+		},
+	})
+}
+
+func inputSummaries(inputs []services.TransferInput, decimals uint8) []models.TransferInputSummary {
+	summaries := make([]models.TransferInputSummary, 0, len(inputs))
+	for _, in := range inputs {
+		summaries = append(summaries, models.TransferInputSummary{
+			Outpoint:      in.Outpoint,
+			Address:       in.Address,
+			AtomicAmount:  in.AtomicAmount,
+			Amount:        amount.Format(in.AtomicAmount, decimals),
+			Satoshis:      in.Satoshis,
+			LockingScript: in.LockingScript,
+			Preimage:      in.Preimage,
+			Sighash:       in.Sighash,
+		})
+	}
+	return summaries
+}
+
+func outputSummaries(outputs []services.TransferOutput, decimals uint8) []models.TransferOutputSummary {
+	summaries := make([]models.TransferOutputSummary, 0, len(outputs))
+	for _, out := range outputs {
+		summaries = append(summaries, models.TransferOutputSummary{
+			Vout:         out.Vout,
+			Address:      out.Address,
+			AtomicAmount: out.AtomicAmount,
+			Amount:       amount.Format(out.AtomicAmount, decimals),
+			Kind:         out.Kind,
+		})
+	}
+	return summaries
+}
@@ -4,7 +4,6 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
 )
 
 // GetConfig godoc
@@ -13,13 +12,20 @@ import (
 // @Tags         Config
 // @Produce      json
 // @Success      200       {object}  models.GetConfigSuccessResponse
-// @Success      400       {object}  models.GenericFailureResponse
+// @Failure      400       {object}  models.GenericFailureResponse
+// @Failure      401       {object}  models.GenericFailureResponse
+// @Failure      403       {object}  models.GenericFailureResponse
+// @Failure      429       {object}  models.GenericFailureResponse
+// @Failure      502       {object}  models.GenericFailureResponse
+// @Failure      503       {object}  models.GenericFailureResponse
+// @Failure      504       {object}  models.GenericFailureResponse
 // @Failure      500       {object}  models.GenericFailureResponse
+// @Security     ApiKeyAuth
 // @Router       /config [get]
 func GetConfig(c *gin.Context) {
-	config, err := services.Instance.GetConfig(c.Request.Context())
+	config, err := mneeClient(c).GetConfig(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/gin-gonic/gin"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/middleware"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/models" // Import models
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
+)
+
+// clientID is the authenticated client's ID, or empty when authentication is
+// disabled.
+func clientID(c *gin.Context) string {
+	if client := middleware.CurrentClient(c); client != nil {
+		return client.ID
+	}
+	return ""
+}
+
+// mneeClient is the client the request runs against: the caller's tenant's,
+// or the default one.
+func mneeClient(c *gin.Context) services.MneeClient {
+	return services.ClientFrom(c.Request.Context())
+}
+
+// parseAddress parses address and checks it against the network of the
+// client the request runs against.
+func parseAddress(c *gin.Context, address string) (*script.Address, error) {
+	return services.ParseAddress(mneeClient(c).Network(), address)
+}
+
+// invalidAddress is the error message for an address parseAddress rejected.
+func invalidAddress(message string, address string, err error) string {
+	if errors.Is(err, services.ErrWrongNetwork) {
+		return message + ": " + address + " is not a " + string(services.NetworkMainnet) + " address"
+	}
+	return message + ": " + address
+}
+
+// MaxQueryAddresses caps the addresses a request may list in its addresses
+// query.
+var MaxQueryAddresses = 50
+
+// queryAddresses parses the required, comma-separated addresses query. On
+// failure it writes the error response and returns false.
+func queryAddresses(c *gin.Context) ([]string, bool) {
+	addrStr := c.Query("addresses")
+	if addrStr == "" {
+		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeValidationFailed, Message: "addresses query parameter is required"})
+		return nil, false
+	}
+
+	addresses, ok := parseAddresses(c, addrStr)
+	if !ok {
+		return nil, false
+	}
+
+	if len(addresses) == 0 {
+		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeInvalidAddress, Message: "No valid addresses provided"})
+		return nil, false
+	}
+	return addresses, true
+}
+
+// parseAddresses parses a comma-separated list of at most MaxQueryAddresses
+// addresses, skipping empty entries. On failure it writes the error response
+// and returns false.
+func parseAddresses(c *gin.Context, list string) ([]string, bool) {
+	var addresses []string
+	for _, addr := range strings.Split(list, ",") {
+		trimmed := strings.TrimSpace(addr)
+		if trimmed == "" {
+			continue
+		}
+		if len(addresses) == MaxQueryAddresses {
+			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeTooManyAddresses, Message: "At most " + strconv.Itoa(MaxQueryAddresses) + " addresses are accepted"})
+			return nil, false
+		}
+
+		address, err := parseAddress(c, trimmed)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeInvalidAddress, Message: invalidAddress("Invalid wallet address", trimmed, err)})
+			return nil, false
+		}
+		addresses = append(addresses, address.AddressString)
+	}
+	return addresses, true
+}
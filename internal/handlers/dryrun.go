@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/amount"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/middleware"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/models" // Import models
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
+)
+
+// respondDryRun builds and signs the transfer without submitting it and
+// writes the preview. Its Idempotency-Key stays free for the submission.
+func respondDryRun(c *gin.Context, wifs []string, dtos []mnee.TransferMneeDTO, recipients []models.RecipientAmount) {
+	middleware.SkipIdempotency(c)
+
+	config, err := mneeClient(c).GetConfig(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	plan, err := services.PreviewTransfer(c.Request.Context(), mneeClient(c), wifs, dtos)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": models.DryRunWrapper{
+			DryRun:       true,
+			Txid:         plan.Tx.TxID().String(),
+			RawTxHex:     plan.Tx.Hex(),
+			Recipients:   recipients,
+			Inputs:       inputSummaries(plan.Inputs, config.Decimals),
+			Outputs:      outputSummaries(plan.Outputs, config.Decimals),
+			AtomicFee:    plan.Fee,
+			Fee:          amount.Format(plan.Fee, config.Decimals),
+			AtomicChange: plan.Change,
+			Change:       amount.Format(plan.Change, config.Decimals),
+		},
+	})
+}
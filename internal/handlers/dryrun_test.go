@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/models"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
+)
+
+func TestDryRun(t *testing.T) {
+	wif, sender := newKey(t)
+	_, recipient := newKey(t)
+	atomic := func(n uint64) *uint64 { return &n }
+
+	handlers := []struct {
+		name    string
+		handler gin.HandlerFunc
+	}{
+		{name: "sync", handler: TransferSync},
+		{name: "async", handler: TransferAsync},
+		{name: "partial sign", handler: PartialSign},
+	}
+
+	tests := []struct {
+		name       string
+		recipients []TransferRecipient
+		status     int
+		code       string
+		outputs    []string
+		fee        uint64
+		change     uint64
+	}{
+		{
+			name:       "with change",
+			recipients: []TransferRecipient{{Address: recipient, AtomicAmount: atomic(10000)}},
+			status:     http.StatusOK,
+			outputs:    []string{services.OutputRecipient, services.OutputFee, services.OutputChange},
+			fee:        100,
+			change:     89900,
+		},
+		{
+			name:       "exact amount",
+			recipients: []TransferRecipient{{Address: recipient, AtomicAmount: atomic(99900)}},
+			status:     http.StatusOK,
+			outputs:    []string{services.OutputRecipient, services.OutputFee},
+			fee:        100,
+		},
+		{
+			name:       "fee not covered",
+			recipients: []TransferRecipient{{Address: recipient, AtomicAmount: atomic(100000)}},
+			status:     http.StatusPaymentRequired,
+			code:       models.CodeInsufficientBalance,
+		},
+		{
+			name:       "invalid recipient",
+			recipients: []TransferRecipient{{Address: "not-an-address", AtomicAmount: atomic(10000)}},
+			status:     http.StatusBadRequest,
+			code:       models.CodeInvalidAddress,
+		},
+	}
+
+	for _, h := range handlers {
+		for _, tt := range tests {
+			t.Run(h.name+"/"+tt.name, func(t *testing.T) {
+				client := services.NewFakeClient()
+				if err := client.Fund(sender, 100000); err != nil {
+					t.Fatal(err)
+				}
+
+				request := TransferRequest{Wifs: []string{wif}, Request: tt.recipients, DryRun: true}
+				status, response := serve(t, client, http.MethodPost, "/transfer", "/transfer", h.handler, request)
+				if status != tt.status || response.Code != tt.code {
+					t.Fatalf("got %d %q (%s), want %d %q", status, response.Code, response.Message, tt.status, tt.code)
+				}
+
+				// Nothing is submitted, whatever the outcome.
+				balances, err := client.GetBalances(t.Context(), []string{sender, recipient})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if balances[0].Amt != 100000 || balances[1].Amt != 0 {
+					t.Fatalf("dry run moved funds: sender holds %v, recipient %v", balances[0].Amt, balances[1].Amt)
+				}
+				if tt.status != http.StatusOK {
+					return
+				}
+
+				var preview models.DryRunWrapper
+				if err := json.Unmarshal(response.Data, &preview); err != nil {
+					t.Fatal(err)
+				}
+				if !preview.DryRun || preview.Txid == "" || preview.RawTxHex == "" {
+					t.Fatalf("got preview %+v, want a signed transaction", preview)
+				}
+				if preview.AtomicFee != tt.fee || preview.AtomicChange != tt.change {
+					t.Fatalf("got fee %d, change %d; want %d, %d", preview.AtomicFee, preview.AtomicChange, tt.fee, tt.change)
+				}
+				if len(preview.Inputs) != 1 || preview.Inputs[0].Address != sender || preview.Inputs[0].AtomicAmount != 100000 {
+					t.Fatalf("got inputs %+v, want the sender's UTXO", preview.Inputs)
+				}
+				if len(preview.Outputs) != len(tt.outputs) {
+					t.Fatalf("got outputs %+v, want kinds %v", preview.Outputs, tt.outputs)
+				}
+				for i, output := range preview.Outputs {
+					if output.Kind != tt.outputs[i] {
+						t.Fatalf("output %d is %s, want %s", i, output.Kind, tt.outputs[i])
+					}
+				}
+				if preview.Outputs[0].Address != recipient || len(preview.Recipients) != 1 {
+					t.Fatalf("got recipient output %+v and recipients %+v", preview.Outputs[0], preview.Recipients)
+				}
+			})
+		}
+	}
+}
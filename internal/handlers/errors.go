@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/hdwallet"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/jsonstore"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/ledger"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/logging"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/models"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/types"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/wallets"
+)
+
+// statusClientClosedRequest is the de facto status for requests whose client
+// went away before a response could be written.
+const statusClientClosedRequest = 499
+
+var sentinelErrors = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{types.ErrInsufficientMneeBalance, http.StatusPaymentRequired, models.CodeInsufficientBalance},
+	{types.ErrForbidden, http.StatusForbidden, models.CodeCosignerForbidden},
+	{types.ErrTransferAmountGreaterThan0, http.StatusBadRequest, models.CodeInvalidAmount},
+	{types.ErrInvalidPublicKeyHash, http.StatusBadRequest, models.CodeInvalidAddress},
+	{types.ErrInvalidConfig, http.StatusBadGateway, models.CodeInvalidConfig},
+	{types.ErrReceivedEmptyTicketID, http.StatusBadGateway, models.CodeEmptyTicketID},
+	{types.ErrInvalidEnvironment, http.StatusInternalServerError, models.CodeInternal},
+	{services.ErrAmountOverflow, http.StatusBadRequest, models.CodeInvalidAmount},
+	{services.ErrTicketNotFound, http.StatusNotFound, models.CodeTicketNotFound},
+	{services.ErrCircuitOpen, http.StatusServiceUnavailable, models.CodeCircuitOpen},
+	{wallets.ErrNotFound, http.StatusNotFound, models.CodeWalletNotFound},
+	{wallets.ErrAlreadyExists, http.StatusConflict, models.CodeWalletExists},
+	{wallets.ErrDisabled, http.StatusServiceUnavailable, models.CodeWalletsDisabled},
+	{wallets.ErrNotEmpty, http.StatusConflict, models.CodeWalletNotEmpty},
+	{wallets.ErrNotExported, http.StatusConflict, models.CodeWalletNotExported},
+	{wallets.ErrExportDisabled, http.StatusForbidden, models.CodeWalletExportDisabled},
+	{hdwallet.ErrNotFound, http.StatusNotFound, models.CodeAccountNotFound},
+	{hdwallet.ErrAlreadyExists, http.StatusConflict, models.CodeAccountExists},
+	{hdwallet.ErrInvalidExtendedKey, http.StatusBadRequest, models.CodeInvalidExtendedKey},
+	{jsonstore.ErrNotSaved, http.StatusInternalServerError, models.CodeInternal},
+	{ledger.ErrDisabled, http.StatusServiceUnavailable, models.CodeLedgerDisabled},
+}
+
+// translateError maps an error returned by the MNEE SDK to an HTTP status and
+// a stable error code. A cosigner that could not answer, e.g. with a 5xx or a
+// proxy's error page, is a 502. Other unrecognised errors are messages relayed
+// from the cosigner, which rejects invalid requests, so they stay 400.
+func translateError(err error) (int, string) {
+	for _, sentinel := range sentinelErrors {
+		if errors.Is(err, sentinel.err) {
+			return sentinel.status, sentinel.code
+		}
+	}
+
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout, models.CodeUpstreamTimeout
+	case errors.Is(err, context.Canceled):
+		return statusClientClosedRequest, models.CodeRequestCanceled
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return http.StatusGatewayTimeout, models.CodeUpstreamTimeout
+	case errors.As(err, &netErr):
+		return http.StatusBadGateway, models.CodeUpstreamUnavailable
+	case errors.Is(err, hex.ErrLength) || errors.As(err, new(hex.InvalidByteError)):
+		return http.StatusBadRequest, models.CodeInvalidRawTx
+	case services.CosignerDown(err):
+		return http.StatusBadGateway, models.CodeUpstreamUnavailable
+	}
+
+	return http.StatusBadRequest, models.CodeUpstreamRejected
+}
+
+// serverErrorMessages replace the messages of 5xx errors, which can hold
+// cosigner responses, URLs or file paths the caller has no use for.
+var serverErrorMessages = map[string]string{
+	models.CodeInvalidConfig:       "The cosigner returned an invalid configuration",
+	models.CodeEmptyTicketID:       "The cosigner returned no ticket ID",
+	models.CodeUpstreamUnavailable: "The cosigner is unavailable",
+	models.CodeUpstreamTimeout:     "The cosigner did not answer in time",
+	models.CodeCircuitOpen:         "The cosigner failed repeatedly; retry after the cooldown",
+	models.CodeWalletsDisabled:     "Managed wallets are disabled",
+	models.CodeLedgerDisabled:      "The transfer ledger is disabled",
+	models.CodeInternal:            "Internal server error",
+}
+
+// errorMessage returns the message to send for err. Client errors carry
+// err's own message; server errors a generic one, with err logged instead.
+func errorMessage(c *gin.Context, status int, code string, err error) string {
+	if status < http.StatusInternalServerError {
+		return err.Error()
+	}
+
+	logging.FromContext(c.Request.Context()).Error("request failed", "code", code, "error", err)
+	if message, ok := serverErrorMessages[code]; ok {
+		return message
+	}
+	return http.StatusText(status)
+}
+
+// respondError writes err as a GenericFailureResponse with its translated status and code.
+func respondError(c *gin.Context, err error) {
+	status, code := translateError(err)
+	c.JSON(status, models.GenericFailureResponse{Success: false, Code: code, Message: errorMessage(c, status, code, err)})
+}
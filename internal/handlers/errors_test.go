@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/hdwallet"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/jsonstore"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/ledger"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/logging"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/models"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/types"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/wallets"
+)
+
+func TestTranslateError(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{types.ErrInsufficientMneeBalance, http.StatusPaymentRequired, models.CodeInsufficientBalance},
+		{types.ErrForbidden, http.StatusForbidden, models.CodeCosignerForbidden},
+		{types.ErrTransferAmountGreaterThan0, http.StatusBadRequest, models.CodeInvalidAmount},
+		{types.ErrInvalidPublicKeyHash, http.StatusBadRequest, models.CodeInvalidAddress},
+		{types.ErrInvalidConfig, http.StatusBadGateway, models.CodeInvalidConfig},
+		{types.ErrReceivedEmptyTicketID, http.StatusBadGateway, models.CodeEmptyTicketID},
+		{types.ErrInvalidEnvironment, http.StatusInternalServerError, models.CodeInternal},
+		{services.ErrAmountOverflow, http.StatusBadRequest, models.CodeInvalidAmount},
+		{services.ErrTicketNotFound, http.StatusNotFound, models.CodeTicketNotFound},
+		{services.ErrCircuitOpen, http.StatusServiceUnavailable, models.CodeCircuitOpen},
+		{wallets.ErrNotFound, http.StatusNotFound, models.CodeWalletNotFound},
+		{wallets.ErrAlreadyExists, http.StatusConflict, models.CodeWalletExists},
+		{wallets.ErrDisabled, http.StatusServiceUnavailable, models.CodeWalletsDisabled},
+		{wallets.ErrNotEmpty, http.StatusConflict, models.CodeWalletNotEmpty},
+		{wallets.ErrNotExported, http.StatusConflict, models.CodeWalletNotExported},
+		{wallets.ErrExportDisabled, http.StatusForbidden, models.CodeWalletExportDisabled},
+		{hdwallet.ErrNotFound, http.StatusNotFound, models.CodeAccountNotFound},
+		{hdwallet.ErrAlreadyExists, http.StatusConflict, models.CodeAccountExists},
+		{hdwallet.ErrInvalidExtendedKey, http.StatusBadRequest, models.CodeInvalidExtendedKey},
+		{jsonstore.ErrNotSaved, http.StatusInternalServerError, models.CodeInternal},
+		{ledger.ErrDisabled, http.StatusServiceUnavailable, models.CodeLedgerDisabled},
+		{context.DeadlineExceeded, http.StatusGatewayTimeout, models.CodeUpstreamTimeout},
+		{context.Canceled, statusClientClosedRequest, models.CodeRequestCanceled},
+		{&net.DNSError{Err: "timeout", IsTimeout: true}, http.StatusGatewayTimeout, models.CodeUpstreamTimeout},
+		{&net.OpError{Op: "dial", Err: errors.New("connection refused")}, http.StatusBadGateway, models.CodeUpstreamUnavailable},
+		{hex.ErrLength, http.StatusBadRequest, models.CodeInvalidRawTx},
+		{hex.InvalidByteError('x'), http.StatusBadRequest, models.CodeInvalidRawTx},
+		{&json.SyntaxError{}, http.StatusBadGateway, models.CodeUpstreamUnavailable},
+		{errors.New("insufficient UTXOs for this transfer"), http.StatusBadRequest, models.CodeUpstreamRejected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code+"/"+tt.err.Error(), func(t *testing.T) {
+			// Sentinels are usually wrapped by the time they reach a handler.
+			for _, err := range []error{tt.err, fmt.Errorf("transfer: %w", tt.err)} {
+				status, code := translateError(err)
+				if status != tt.status || code != tt.code {
+					t.Fatalf("translateError(%v) = %d, %s; want %d, %s", err, status, code, tt.status, tt.code)
+				}
+			}
+		})
+	}
+
+	// Every sentinel the handlers map is covered above.
+	for _, sentinel := range sentinelErrors {
+		covered := false
+		for _, tt := range tests {
+			covered = covered || tt.err == sentinel.err
+		}
+		if !covered {
+			t.Errorf("sentinel %q has no test case", sentinel.err)
+		}
+	}
+}
+
+func TestRespondError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		err     error
+		status  int
+		message string
+		logged  bool
+	}{
+		{name: "client error keeps its message", err: types.ErrInsufficientMneeBalance, status: http.StatusPaymentRequired, message: types.ErrInsufficientMneeBalance.Error()},
+		{name: "cosigner rejection keeps its message", err: errors.New("invalid recipient"), status: http.StatusBadRequest, message: "invalid recipient"},
+		{name: "internal error", err: fmt.Errorf("%w: open /var/lib/mnee/wallets.json: permission denied", jsonstore.ErrNotSaved), status: http.StatusInternalServerError, message: "Internal server error", logged: true},
+		{name: "cosigner unavailable", err: &net.OpError{Op: "dial", Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 7), Port: 443}, Err: errors.New("connection refused")}, status: http.StatusBadGateway, message: "The cosigner is unavailable", logged: true},
+		{name: "cosigner error page", err: fmt.Errorf("cosigner returned 503: <html>upstream 10.0.0.7</html>: %w", &json.SyntaxError{}), status: http.StatusBadGateway, message: "The cosigner is unavailable", logged: true},
+		{name: "circuit open", err: services.ErrCircuitOpen, status: http.StatusServiceUnavailable, message: "The cosigner failed repeatedly; retry after the cooldown", logged: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := slog.Default()
+			t.Cleanup(func() { slog.SetDefault(previous) })
+			var logs bytes.Buffer
+			if err := logging.Init(&logs, "json", "info"); err != nil {
+				t.Fatal(err)
+			}
+
+			status, response := serve(t, services.NewFakeClient(), http.MethodGet, "/fail", "/fail", func(c *gin.Context) {
+				respondError(c, tt.err)
+			}, nil)
+			if status != tt.status || response.Message != tt.message {
+				t.Fatalf("got %d %q, want %d %q", status, response.Message, tt.status, tt.message)
+			}
+
+			if logged := strings.Contains(logs.String(), `"msg":"request failed"`); logged != tt.logged {
+				t.Fatalf("logged %v, want %v: %s", logged, tt.logged, logs.String())
+			}
+			if tt.logged && !strings.Contains(logs.String(), `"error":`) {
+				t.Fatalf("log %s does not hold the error", logs.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"math"
+	"math/bits"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/amount"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/models" // Import models
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/types"
+)
+
+type FeeQuoteRequest struct {
+	Recipients      []TransferRecipient `json:"recipients" binding:"required,min=1,dive"`
+	SourceAddresses []string            `json:"sourceAddresses,omitempty" example:"1G6CB3Ch4zFkPmuhZzEyChQmrQPfi86qk3"`
+}
+
+// GetFeeQuote godoc
+// @Summary      Quote transfer fee
+// @Description  Resolves the cosigner fee tier for an amount and returns the fee and total debit. When addresses are given, also reports whether their combined balance covers the total.
+// @Tags         Fees
+// @Produce      json
+// @Param        amount        query     string  false "Amount in display units (e.g. 0.29)"
+// @Param        atomicAmount  query     int     false "Amount in atomic units, instead of amount"
+// @Param        addresses     query     string  false "Comma-separated list of source addresses"
+// @Success      200           {object}  models.FeeQuoteSuccessResponse
+// @Failure      400           {object}  models.GenericFailureResponse
+// @Failure      401           {object}  models.GenericFailureResponse
+// @Failure      403           {object}  models.GenericFailureResponse
+// @Failure      429           {object}  models.GenericFailureResponse
+// @Failure      502           {object}  models.GenericFailureResponse
+// @Failure      503           {object}  models.GenericFailureResponse
+// @Failure      504           {object}  models.GenericFailureResponse
+// @Failure      500           {object}  models.GenericFailureResponse
+// @Security     ApiKeyAuth
+// @Router       /fees/quote [get]
+func GetFeeQuote(c *gin.Context) {
+	config, err := mneeClient(c).GetConfig(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	recipient := TransferRecipient{Amount: amount.Decimal(c.Query("amount"))}
+	if atomicQuery := c.Query("atomicAmount"); atomicQuery != "" {
+		atomicAmount, err := amount.Parse(atomicQuery, 0)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeInvalidAmount, Message: "atomicAmount must be a non-negative integer"})
+			return
+		}
+		recipient.AtomicAmount = &atomicAmount
+	}
+
+	atomicAmount, err := parseRecipientAmount(recipient, config.Decimals)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeInvalidAmount, Message: err.Error()})
+		return
+	}
+	if atomicAmount == 0 {
+		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeInvalidAmount, Message: "Amount must be greater than 0"})
+		return
+	}
+
+	sources, ok := parseAddresses(c, c.Query("addresses"))
+	if !ok {
+		return
+	}
+
+	quote, err := quoteFee(c, config, []mnee.TransferMneeDTO{{Amount: atomicAmount}}, sources)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": quote})
+}
+
+// PostFeeQuote godoc
+// @Summary      Quote multi-recipient transfer fee
+// @Description  Resolves the cosigner fee tier for a set of recipients. Amounts sent back to one of the source addresses do not count towards the tier, as when the transfer is built.
+// @Tags         Fees
+// @Accept       json
+// @Produce      json
+// @Param        request body FeeQuoteRequest true "Quote Parameters"
+// @Success      200     {object} models.FeeQuoteSuccessResponse
+// @Failure      422     {object} models.GenericFailureResponse
+// @Failure      400     {object} models.GenericFailureResponse
+// @Failure      401     {object} models.GenericFailureResponse
+// @Failure      403     {object} models.GenericFailureResponse
+// @Failure      429     {object} models.GenericFailureResponse
+// @Failure      502     {object} models.GenericFailureResponse
+// @Failure      503     {object} models.GenericFailureResponse
+// @Failure      504     {object} models.GenericFailureResponse
+// @Failure      500     {object} models.GenericFailureResponse
+// @Security     ApiKeyAuth
+// @Router       /fees/quote [post]
+func PostFeeQuote(c *gin.Context) {
+	var req FeeQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, models.GenericFailureResponse{Success: false, Code: models.CodeInvalidRequestBody, Message: "Unprocessable Entity: " + err.Error()})
+		return
+	}
+
+	var sources []string
+	for _, addr := range req.SourceAddresses {
+		address, err := parseAddress(c, addr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeInvalidAddress, Message: invalidAddress("Invalid source address", addr, err)})
+			return
+		}
+		sources = append(sources, address.AddressString)
+	}
+
+	config, err := mneeClient(c).GetConfig(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	dtos, recipients, ok := prepareRecipients(c, req.Recipients, config.Decimals)
+	if !ok {
+		return
+	}
+
+	quote, err := quoteFee(c, config, dtos, sources)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	quote.Recipients = recipients
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": quote})
+}
+
+// quoteFee resolves the fee tier the way transfers are built: on the amount
+// sent to addresses other than sources.
+func quoteFee(c *gin.Context, config *mnee.SystemConfig, dtos []mnee.TransferMneeDTO, sources []string) (*models.FeeQuoteWrapper, error) {
+	total, err := services.TotalAmount(dtos)
+	if err != nil {
+		return nil, err
+	}
+	chargeable, err := services.ChargeableAmount(dtos, sources)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &models.FeeQuoteWrapper{
+		AtomicAmount: total,
+		Amount:       amount.Format(total, config.Decimals),
+	}
+
+	if tier, ok := services.ResolveFee(config.Fees, chargeable); ok {
+		quote.AtomicFee = tier.Fee
+		quote.Tier = &types.Fee{MinAmt: tier.MinAmt, MaxAmt: tier.MaxAmt, Fee: tier.Fee}
+	}
+	quote.Fee = amount.Format(quote.AtomicFee, config.Decimals)
+	var carry uint64
+	if quote.AtomicTotal, carry = bits.Add64(total, quote.AtomicFee, 0); carry != 0 {
+		return nil, services.ErrAmountOverflow
+	}
+	quote.Total = amount.Format(quote.AtomicTotal, config.Decimals)
+
+	if len(sources) == 0 {
+		return quote, nil
+	}
+
+	balances, err := mneeClient(c).GetBalances(c.Request.Context(), sources)
+	if err != nil {
+		return nil, err
+	}
+
+	var atomicBalance uint64
+	for _, balance := range balances {
+		atomicBalance += uint64(math.Round(balance.Amt))
+	}
+	balance := amount.Format(atomicBalance, config.Decimals)
+	sufficient := atomicBalance >= quote.AtomicTotal
+
+	quote.SourceAddresses = sources
+	quote.AtomicBalance = &atomicBalance
+	quote.Balance = &balance
+	quote.Sufficient = &sufficient
+
+	return quote, nil
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"testing"
+
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/models"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
+)
+
+func TestGetFeeQuote(t *testing.T) {
+	_, funded := newKey(t)
+	_, empty := newKey(t)
+	covered, uncovered := true, false
+
+	tests := []struct {
+		name       string
+		query      string
+		fail       error
+		status     int
+		code       string
+		fee        uint64
+		total      uint64
+		sufficient *bool
+	}{
+		{name: "display amount", query: "amount=0.29", status: http.StatusOK, fee: 100, total: 29100},
+		{name: "atomic amount", query: "atomicAmount=29000", status: http.StatusOK, fee: 100, total: 29100},
+		{name: "upper tier", query: "atomicAmount=2000000", status: http.StatusOK, fee: 1000, total: 2001000},
+		{name: "tier boundary", query: "atomicAmount=1000000", status: http.StatusOK, fee: 100, total: 1000100},
+		{name: "covered by addresses", query: "amount=0.29&addresses=" + funded, status: http.StatusOK, fee: 100, total: 29100, sufficient: &covered},
+		{name: "not covered by addresses", query: "amount=0.29&addresses=" + empty, status: http.StatusOK, fee: 100, total: 29100, sufficient: &uncovered},
+		{name: "missing amount", query: "", status: http.StatusBadRequest, code: models.CodeInvalidAmount},
+		{name: "zero amount", query: "amount=0", status: http.StatusBadRequest, code: models.CodeInvalidAmount},
+		{name: "too many decimals", query: "amount=0.000001", status: http.StatusBadRequest, code: models.CodeInvalidAmount},
+		{name: "negative atomic amount", query: "atomicAmount=-1", status: http.StatusBadRequest, code: models.CodeInvalidAmount},
+		{name: "both amounts", query: "amount=0.29&atomicAmount=29000", status: http.StatusBadRequest, code: models.CodeInvalidAmount},
+		{name: "invalid address", query: "amount=0.29&addresses=not-an-address", status: http.StatusBadRequest, code: models.CodeInvalidAddress},
+		{name: "cosigner down", query: "amount=0.29", fail: errors.New("status received from mnee-cosigner -> 503"), status: http.StatusBadGateway, code: models.CodeUpstreamUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := services.NewFakeClient()
+			if err := client.Fund(funded, 250000); err != nil {
+				t.Fatal(err)
+			}
+			client.FailWith("GetConfig", tt.fail)
+
+			status, response := serve(t, client, http.MethodGet, "/fees/quote", "/fees/quote?"+tt.query, GetFeeQuote, nil)
+			if status != tt.status || response.Code != tt.code {
+				t.Fatalf("got %d %q (%s), want %d %q", status, response.Code, response.Message, tt.status, tt.code)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			quote := decodeFeeQuote(t, response)
+			if quote.AtomicFee != tt.fee || quote.AtomicTotal != tt.total {
+				t.Fatalf("got fee %d, total %d; want %d, %d", quote.AtomicFee, quote.AtomicTotal, tt.fee, tt.total)
+			}
+			if quote.Tier == nil || quote.Tier.Fee != tt.fee {
+				t.Fatalf("got tier %+v, want the tier with fee %d", quote.Tier, tt.fee)
+			}
+			switch {
+			case tt.sufficient == nil && quote.Sufficient != nil:
+				t.Fatalf("got sufficient %v without addresses", *quote.Sufficient)
+			case tt.sufficient != nil && (quote.Sufficient == nil || *quote.Sufficient != *tt.sufficient):
+				t.Fatalf("got sufficient %v, want %v", quote.Sufficient, *tt.sufficient)
+			}
+		})
+	}
+}
+
+func TestPostFeeQuote(t *testing.T) {
+	_, source := newKey(t)
+	_, recipient := newKey(t)
+
+	atomic := func(value uint64) *uint64 { return &value }
+
+	tests := []struct {
+		name    string
+		request FeeQuoteRequest
+		status  int
+		code    string
+		fee     uint64
+		total   uint64
+	}{
+		{
+			name:    "single recipient",
+			request: FeeQuoteRequest{Recipients: []TransferRecipient{{Address: recipient, Amount: "0.29"}}},
+			status:  http.StatusOK, fee: 100, total: 29100,
+		},
+		{
+			name: "recipients add up to the upper tier",
+			request: FeeQuoteRequest{Recipients: []TransferRecipient{
+				{Address: recipient, AtomicAmount: atomic(900000)},
+				{Address: source, AtomicAmount: atomic(500000)},
+			}},
+			status: http.StatusOK, fee: 1000, total: 1401000,
+		},
+		{
+			name: "change back to a source is not charged",
+			request: FeeQuoteRequest{
+				Recipients: []TransferRecipient{
+					{Address: recipient, AtomicAmount: atomic(900000)},
+					{Address: source, AtomicAmount: atomic(500000)},
+				},
+				SourceAddresses: []string{source},
+			},
+			status: http.StatusOK, fee: 100, total: 1400100,
+		},
+		{
+			name: "overflow",
+			request: FeeQuoteRequest{Recipients: []TransferRecipient{
+				{Address: recipient, AtomicAmount: atomic(math.MaxUint64)},
+				{Address: recipient, AtomicAmount: atomic(1)},
+			}},
+			status: http.StatusBadRequest, code: models.CodeInvalidAmount,
+		},
+		{
+			name:    "no recipients",
+			request: FeeQuoteRequest{},
+			status:  http.StatusUnprocessableEntity, code: models.CodeInvalidRequestBody,
+		},
+		{
+			name:    "invalid recipient",
+			request: FeeQuoteRequest{Recipients: []TransferRecipient{{Address: "not-an-address", Amount: "0.29"}}},
+			status:  http.StatusBadRequest, code: models.CodeInvalidAddress,
+		},
+		{
+			name: "invalid source",
+			request: FeeQuoteRequest{
+				Recipients:      []TransferRecipient{{Address: recipient, Amount: "0.29"}},
+				SourceAddresses: []string{"not-an-address"},
+			},
+			status: http.StatusBadRequest, code: models.CodeInvalidAddress,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := services.NewFakeClient()
+			if err := client.Fund(source, 2000000); err != nil {
+				t.Fatal(err)
+			}
+
+			status, response := serve(t, client, http.MethodPost, "/fees/quote", "/fees/quote", PostFeeQuote, tt.request)
+			if status != tt.status || response.Code != tt.code {
+				t.Fatalf("got %d %q (%s), want %d %q", status, response.Code, response.Message, tt.status, tt.code)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			quote := decodeFeeQuote(t, response)
+			if quote.AtomicFee != tt.fee || quote.AtomicTotal != tt.total {
+				t.Fatalf("got fee %d, total %d; want %d, %d", quote.AtomicFee, quote.AtomicTotal, tt.fee, tt.total)
+			}
+			if len(quote.Recipients) != len(tt.request.Recipients) {
+				t.Fatalf("got %d recipients, want %d", len(quote.Recipients), len(tt.request.Recipients))
+			}
+			if len(tt.request.SourceAddresses) > 0 && (quote.Sufficient == nil || !*quote.Sufficient) {
+				t.Fatalf("got sufficient %v, want true", quote.Sufficient)
+			}
+		})
+	}
+}
+
+func decodeFeeQuote(t *testing.T, response testResponse) models.FeeQuoteWrapper {
+	t.Helper()
+
+	var quote models.FeeQuoteWrapper
+	if err := json.Unmarshal(response.Data, &quote); err != nil {
+		t.Fatal(err)
+	}
+	return quote
+}
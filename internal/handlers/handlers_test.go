@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	primitives "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/gin-gonic/gin"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/auth"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/middleware"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
+)
+
+type testResponse struct {
+	Success bool            `json:"success"`
+	Code    string          `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// serve runs one request through handler, mounted at route, against client.
+func serve(t *testing.T, client services.MneeClient, method, route, path string, handler gin.HandlerFunc, body any) (int, testResponse) {
+	t.Helper()
+	return serveAs(t, client, "", method, route, path, handler, body)
+}
+
+// serveAs is serve with the request authenticated as clientID, whose API key
+// is its ID; an empty clientID leaves the request unauthenticated.
+func serveAs(t *testing.T, client services.MneeClient, clientID, method, route, path string, handler gin.HandlerFunc, body any) (int, testResponse) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	if clientID != "" {
+		keys, err := auth.ParseKeys(clientID + ":" + auth.HashKey(clientID) + ":" + strings.Join(auth.Scopes, ","))
+		if err != nil {
+			t.Fatal(err)
+		}
+		router.Use(middleware.Authenticate(keys))
+	}
+	router.Handle(method, route, func(c *gin.Context) {
+		c.Request = c.Request.WithContext(services.WithClient(c.Request.Context(), client))
+		c.Next()
+	}, handler)
+
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	request := httptest.NewRequest(method, path, bytes.NewReader(data))
+	request.Header.Set("Content-Type", "application/json")
+	if clientID != "" {
+		request.Header.Set("Authorization", "Bearer "+clientID)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	var response testResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decoding %s: %v", recorder.Body.String(), err)
+	}
+	return recorder.Code, response
+}
+
+// newKey returns a fresh WIF and its mainnet address.
+func newKey(t *testing.T) (string, string) {
+	t.Helper()
+
+	privateKey, err := primitives.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	address, err := script.NewAddressFromPublicKey(privateKey.PubKey(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return privateKey.Wif(), address.AddressString
+}
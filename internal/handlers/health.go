@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/health"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/lifecycle"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/models" // Import models
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
+)
+
+// Healthz reports that the process is up. It checks nothing else, so a
+// cosigner outage does not get the container restarted, but shows the state
+// of the cosigners' circuit breakers.
+func Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"success": true, "data": gin.H{"breakers": services.Breakers()}})
+}
+
+// Readyz reports whether the server takes traffic: it is not shutting down
+// and the cosigner answered a GetConfig call recently. The cosigner check
+// runs in the background, so the probe answers immediately with its latency
+// and last error. It fails as soon as shutdown starts, so load balancers stop
+// routing requests here while it drains. Readyz and Healthz are served
+// outside /api and need no API key.
+func Readyz(c *gin.Context) {
+	respondReadiness(c, lifecycle.IsDraining(), health.Instance.Status())
+}
+
+func respondReadiness(c *gin.Context, draining bool, cosigner health.Status) {
+	data := gin.H{
+		"ready":    !draining && cosigner.OK,
+		"draining": draining,
+		"cosigner": cosigner,
+		"breakers": services.Breakers(),
+	}
+
+	switch {
+	case draining:
+		c.JSON(http.StatusServiceUnavailable, gin.H{"success": false, "code": models.CodeShuttingDown, "message": "Server is shutting down", "data": data})
+	case !cosigner.OK:
+		c.JSON(http.StatusServiceUnavailable, gin.H{"success": false, "code": models.CodeNotReady, "message": "Cosigner has not answered recently", "data": data})
+	default:
+		c.JSON(http.StatusOK, gin.H{"success": true, "data": data})
+	}
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/health"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/models"
+)
+
+func TestReadiness(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		draining bool
+		cosigner health.Status
+		status   int
+		code     string
+	}{
+		{name: "ready", cosigner: health.Status{OK: true, LastSuccessAt: &now}, status: http.StatusOK},
+		{name: "cosigner down", cosigner: health.Status{LastError: "connection refused"}, status: http.StatusServiceUnavailable, code: models.CodeNotReady},
+		{name: "draining", draining: true, cosigner: health.Status{OK: true, LastSuccessAt: &now}, status: http.StatusServiceUnavailable, code: models.CodeShuttingDown},
+		{name: "draining with cosigner down", draining: true, status: http.StatusServiceUnavailable, code: models.CodeShuttingDown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/readyz", func(c *gin.Context) {
+				respondReadiness(c, tt.draining, tt.cosigner)
+			})
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			var response struct {
+				Code string `json:"code"`
+				Data struct {
+					Ready    bool          `json:"ready"`
+					Draining bool          `json:"draining"`
+					Cosigner health.Status `json:"cosigner"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			if recorder.Code != tt.status || response.Code != tt.code {
+				t.Fatalf("got %d %q, want %d %q", recorder.Code, response.Code, tt.status, tt.code)
+			}
+			if response.Data.Ready != (tt.status == http.StatusOK) || response.Data.Draining != tt.draining {
+				t.Fatalf("got data %+v", response.Data)
+			}
+			if response.Data.Cosigner.LastError != tt.cosigner.LastError {
+				t.Fatalf("got cosigner %+v, want %+v", response.Data.Cosigner, tt.cosigner)
+			}
+		})
+	}
+}
+
+func TestReadyz(t *testing.T) {
+	previous := health.Instance
+	t.Cleanup(func() { health.Instance = previous })
+
+	// A checker that never ran has not seen the cosigner answer.
+	health.Instance = health.NewChecker(time.Hour, time.Minute)
+	status, response := serve(t, nil, http.MethodGet, "/readyz", "/readyz", Readyz, nil)
+	if status != http.StatusServiceUnavailable || response.Code != models.CodeNotReady {
+		t.Fatalf("got %d %q, want 503 %q", status, response.Code, models.CodeNotReady)
+	}
+}
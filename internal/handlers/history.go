@@ -3,12 +3,9 @@ package handlers
 import (
 	"net/http"
 	"strconv"
-	"strings"
 
-	"github.com/bsv-blockchain/go-sdk/script"
 	"github.com/gin-gonic/gin"
 	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/models" // Import models
-	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
 )
 
 // GetHistory godoc
@@ -21,34 +18,43 @@ import (
 // @Param        limit     query     int     false "Limit (default 10)"
 // @Success      200       {object}  models.GetHistorySuccessResponse
 // @Failure      400       {object}  models.GenericFailureResponse
+// @Failure      401       {object}  models.GenericFailureResponse
+// @Failure      403       {object}  models.GenericFailureResponse
+// @Failure      429       {object}  models.GenericFailureResponse
+// @Failure      502       {object}  models.GenericFailureResponse
+// @Failure      503       {object}  models.GenericFailureResponse
+// @Failure      504       {object}  models.GenericFailureResponse
 // @Failure      500       {object}  models.GenericFailureResponse
+// @Security     ApiKeyAuth
 // @Router       /transaction [get]
 func GetHistory(c *gin.Context) {
-	addrStr := c.Query("addresses")
-	if addrStr == "" {
-		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Message: "addresses query parameter is required"})
+	addresses, ok := queryAddresses(c)
+	if !ok {
 		return
 	}
 
-	rawAddresses := strings.Split(addrStr, ",")
-	var addresses []string
-	for _, addr := range rawAddresses {
-		trimmed := strings.TrimSpace(addr)
-		if trimmed != "" {
-			address, err := script.NewAddressFromString(trimmed)
-			if err != nil {
-				c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Message: "Invalid wallet address: " + trimmed})
-				return
-			}
-			addresses = append(addresses, address.AddressString)
-		}
+	fromScore, limit, ok := historyPage(c)
+	if !ok {
+		return
 	}
 
-	if len(addresses) == 0 {
-		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Message: "No valid addresses provided"})
+	history, err := mneeClient(c).GetSpecificTransactionHistory(c.Request.Context(), addresses, fromScore, limit)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"history": history,
+		},
+	})
+}
+
+// historyPage parses the fromScore and limit query parameters, writing a 400
+// response when they are invalid.
+func historyPage(c *gin.Context) (int, int, bool) {
 	var fromScore int
 	fromQuery := c.Query("fromScore")
 	if fromQuery == "" {
@@ -57,12 +63,12 @@ func GetHistory(c *gin.Context) {
 		var err error
 		fromScore, err = strconv.Atoi(fromQuery)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Message: "fromScore must be a valid integer"})
-			return
+			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeValidationFailed, Message: "fromScore must be a valid integer"})
+			return 0, 0, false
 		}
 		if fromScore < 0 {
-			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Message: "fromScore must not be negative"})
-			return
+			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeValidationFailed, Message: "fromScore must not be negative"})
+			return 0, 0, false
 		}
 	}
 
@@ -74,25 +80,14 @@ func GetHistory(c *gin.Context) {
 		var err error
 		limit, err = strconv.Atoi(limitQuery)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Message: "limit must be a valid integer"})
-			return
+			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeValidationFailed, Message: "limit must be a valid integer"})
+			return 0, 0, false
 		}
 		if limit < 0 {
-			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Message: "limit must not be negative"})
-			return
+			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeValidationFailed, Message: "limit must not be negative"})
+			return 0, 0, false
 		}
 	}
 
-	history, err := services.Instance.GetSpecificTransactionHistory(c.Request.Context(), addresses, fromScore, limit)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Message: err.Error()})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data": gin.H{
-			"history": history,
-		},
-	})
+	return fromScore, limit, true
 }
@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/ledger"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/logging"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/middleware"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/models" // Import models
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
+)
+
+const (
+	defaultTransfersPageSize = 20
+	maxTransfersPageSize     = 100
+)
+
+// ListTransfers godoc
+// @Summary      List Transfers
+// @Description  Lists the transfers and raw transactions the caller submitted through this service, newest first, with their recipients, atomic amounts, fee, ticket, txid, errors and status history. Status is pending until the ticket is seen, then broadcasting, success or failed; unknown means the cosigner did not answer in time, and stuck that the ticket has not finished long after submission.
+// @Tags         Transfer
+// @Produce      json
+// @Param        status    query     string  false  "pending, broadcasting, success, failed, unknown or stuck"
+// @Param        kind      query     string  false  "transfer or rawtx"
+// @Param        ticketId  query     string  false  "Ticket ID"
+// @Param        txid      query     string  false  "Transaction ID"
+// @Param        address   query     string  false  "Source or recipient address"
+// @Param        since     query     string  false  "Submitted at or after, RFC 3339"
+// @Param        until     query     string  false  "Submitted before, RFC 3339"
+// @Param        page      query     int     false  "Page number (default 1)"
+// @Param        size      query     int     false  "Page size (default 20, maximum 100)"
+// @Success      200       {object}  models.ListTransfersSuccessResponse
+// @Failure      400       {object}  models.GenericFailureResponse
+// @Failure      401       {object}  models.GenericFailureResponse
+// @Failure      403       {object}  models.GenericFailureResponse
+// @Failure      429       {object}  models.GenericFailureResponse
+// @Failure      503       {object}  models.GenericFailureResponse
+// @Failure      500       {object}  models.GenericFailureResponse
+// @Security     ApiKeyAuth
+// @Router       /transfers [get]
+func ListTransfers(c *gin.Context) {
+	if ledger.Instance == nil {
+		respondError(c, ledger.ErrDisabled)
+		return
+	}
+
+	filter := ledger.Filter{
+		ClientID: clientID(c),
+		TicketID: c.Query("ticketId"),
+		Txid:     c.Query("txid"),
+	}
+
+	if value := c.Query("status"); value != "" {
+		status, ok := ledger.ParseStatus(value)
+		if !ok {
+			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeValidationFailed, Message: "status must be pending, broadcasting, success, failed, unknown or stuck"})
+			return
+		}
+		filter.Status = status
+	}
+
+	if value := c.Query("kind"); value != "" {
+		kind, ok := ledger.ParseKind(value)
+		if !ok {
+			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeValidationFailed, Message: "kind must be transfer or rawtx"})
+			return
+		}
+		filter.Kind = kind
+	}
+
+	if value := c.Query("address"); value != "" {
+		address, err := parseAddress(c, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeInvalidAddress, Message: invalidAddress("Invalid wallet address", value, err)})
+			return
+		}
+		filter.Address = address.AddressString
+	}
+
+	var ok bool
+	if filter.Since, ok = timeQuery(c, "since"); !ok {
+		return
+	}
+	if filter.Until, ok = timeQuery(c, "until"); !ok {
+		return
+	}
+
+	page, ok := intQuery(c, "page", 1, 1, 1_000_000)
+	if !ok {
+		return
+	}
+	size, ok := intQuery(c, "size", defaultTransfersPageSize, 1, maxTransfersPageSize)
+	if !ok {
+		return
+	}
+	filter.Offset = (page - 1) * size
+	filter.Limit = size
+
+	transfers, total, err := ledger.Instance.List(c.Request.Context(), filter)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": models.TransfersPage{
+			Transfers: transfers,
+			Page:      page,
+			Size:      size,
+			Total:     total,
+		},
+	})
+}
+
+// recordTransfer adds a transfer submitted to the cosigner to the ledger.
+func recordTransfer(c *gin.Context, async bool, wifs []string, dtos []mnee.TransferMneeDTO, response *mnee.TransferResponseDTO, ticketID *string, err error) {
+	if ledger.Instance == nil {
+		return
+	}
+
+	transfer := ledger.Transfer{Kind: ledger.KindTransfer, Async: async, Sources: services.WifAddresses(wifs)}
+	for _, dto := range dtos {
+		transfer.Recipients = append(transfer.Recipients, ledger.Recipient{Address: dto.Address, AtomicAmount: dto.Amount})
+		transfer.AtomicAmount += dto.Amount
+	}
+	recordSubmission(c, transfer, response, ticketID, err)
+}
+
+// recordRawTx adds a raw transaction submitted to the cosigner to the
+// ledger. Its signers, recipients and fee are read from the transaction;
+// outputs back to a signer are change and not counted.
+func recordRawTx(c *gin.Context, async bool, rawTxHex string, response *mnee.TransferResponseDTO, ticketID *string, err error) {
+	if ledger.Instance == nil {
+		return
+	}
+
+	transfer := ledger.Transfer{Kind: ledger.KindRawTx, Async: async}
+	config, configErr := mneeClient(c).GetConfig(context.WithoutCancel(c.Request.Context()))
+	if configErr == nil && config.FeeAddress != nil {
+		if summary, err := services.SummarizeTx(rawTxHex, *config.FeeAddress); err == nil {
+			transfer.Sources = summary.Sources
+			for _, dto := range summary.Recipients {
+				transfer.Recipients = append(transfer.Recipients, ledger.Recipient{Address: dto.Address, AtomicAmount: dto.Amount})
+				transfer.AtomicAmount += dto.Amount
+			}
+			transfer.AtomicFee = &summary.Fee
+		}
+	}
+	recordSubmission(c, transfer, response, ticketID, err)
+}
+
+// recordSubmission completes transfer with the outcome of its submission
+// and records it. A ledger that cannot be written is logged, not reported:
+// the submission itself already happened.
+func recordSubmission(c *gin.Context, transfer ledger.Transfer, response *mnee.TransferResponseDTO, ticketID *string, err error) {
+	ctx := context.WithoutCancel(c.Request.Context())
+
+	now := time.Now().UTC()
+	transfer.ID = ledger.NewID()
+	transfer.ClientID = clientID(c)
+	transfer.RequestID = c.Writer.Header().Get(middleware.RequestIDHeader)
+	transfer.CreatedAt = now
+	transfer.UpdatedAt = now
+
+	switch {
+	case err != nil:
+		_, code := translateError(err)
+		transfer.Status = ledger.StatusFailed
+		if code == models.CodeUpstreamTimeout || code == models.CodeRequestCanceled {
+			transfer.Status = ledger.StatusUnknown
+		}
+		transfer.ErrorCode = code
+		// Listed to every client, so the API key in SDK errors must not leak.
+		transfer.Errors = []string{logging.Redact(err.Error())}
+	case ticketID != nil:
+		transfer.Status = ledger.StatusPending
+		transfer.TicketID = *ticketID
+	case response != nil:
+		transfer.Status = ledger.StatusSuccess
+		if response.Txid != nil {
+			transfer.Txid = *response.Txid
+		}
+		if response.Txhex != nil && transfer.AtomicFee == nil {
+			if fee, ok := services.TxFee(ctx, mneeClient(c), *response.Txhex); ok {
+				transfer.AtomicFee = &fee
+			}
+		}
+	}
+
+	if err := ledger.Instance.Record(ctx, transfer); err != nil {
+		logging.FromContext(ctx).Error("Failed to record transfer in the ledger", "ticket_id", transfer.TicketID, "txid", transfer.Txid, "error", err)
+	}
+}
+
+// intQuery parses the integer query parameter name, writing a 400 and
+// returning false when it is malformed or outside [min, max].
+func intQuery(c *gin.Context, name string, fallback, min, max int) (int, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return fallback, true
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min || n > max {
+		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeValidationFailed, Message: name + " must be an integer between " + strconv.Itoa(min) + " and " + strconv.Itoa(max)})
+		return 0, false
+	}
+	return n, true
+}
+
+// boolQuery parses the optional boolean query parameter name, writing a 400
+// and returning false when it is malformed.
+func boolQuery(c *gin.Context, name string) (bool, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return false, true
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeValidationFailed, Message: name + " must be true or false"})
+		return false, false
+	}
+	return b, true
+}
+
+// timeQuery parses the optional RFC 3339 query parameter name, writing a 400
+// and returning false when it is malformed.
+func timeQuery(c *gin.Context, name string) (time.Time, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return time.Time{}, true
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeValidationFailed, Message: name + " must be an RFC 3339 time, e.g. 2025-01-31T00:00:00Z"})
+		return time.Time{}, false
+	}
+	return t, true
+}
@@ -1,39 +1,229 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/ledger"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/lifecycle"
 	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/models" // Import models
 	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/types"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/webhooks"
 )
 
 // PollTicket godoc
 // @Summary      Poll Ticket Status
-// @Description  Polls the status of a transaction ticket until it is processed or times out.
+// @Description  Polls the status of a transaction ticket until it is processed or times out. Only the client that submitted the ticket can read it; other tickets are reported as not found.
 // @Tags         Transaction
 // @Produce      json
 // @Param        ticketId  path      string  true  "Ticket ID"
 // @Success      200       {object}  models.GetTicketSuccessResponse
 // @Failure      400       {object}  models.GenericFailureResponse
+// @Failure      401       {object}  models.GenericFailureResponse
+// @Failure      403       {object}  models.GenericFailureResponse
+// @Failure      404       {object}  models.GenericFailureResponse
+// @Failure      429       {object}  models.GenericFailureResponse
+// @Failure      502       {object}  models.GenericFailureResponse
+// @Failure      503       {object}  models.GenericFailureResponse
+// @Failure      504       {object}  models.GenericFailureResponse
 // @Failure      500       {object}  models.GenericFailureResponse
+// @Security     ApiKeyAuth
 // @Router       /transaction/status/{ticketId} [get]
 func PollTicket(c *gin.Context) {
 	ticketID := c.Param("ticketId")
 	if ticketID == "" {
-		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Message: "ticketId is required"})
+		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeValidationFailed, Message: "ticketId is required"})
 		return
 	}
 
-	ticket, err := services.Instance.PollTicket(c.Request.Context(), ticketID, 2*time.Second)
+	if !checkTicketOwner(c, ticketID) {
+		return
+	}
+
+	ticket, err := mneeClient(c).PollTicket(c.Request.Context(), ticketID, 2*time.Second)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    types.NewTicket(ticket),
+	})
+}
+
+// GetTicket godoc
+// @Summary      Get Ticket
+// @Description  Returns the current state of a transaction ticket without waiting. Returns 404 while the cosigner has no record of the ticket, and for tickets another client submitted.
+// @Tags         Transaction
+// @Produce      json
+// @Param        id  path      string  true  "Ticket ID"
+// @Success      200 {object}  models.GetTicketSuccessResponse
+// @Failure      401 {object}  models.GenericFailureResponse
+// @Failure      403 {object}  models.GenericFailureResponse
+// @Failure      404 {object}  models.GenericFailureResponse
+// @Failure      429 {object}  models.GenericFailureResponse
+// @Failure      502 {object}  models.GenericFailureResponse
+// @Failure      503 {object}  models.GenericFailureResponse
+// @Failure      504 {object}  models.GenericFailureResponse
+// @Failure      500 {object}  models.GenericFailureResponse
+// @Security     ApiKeyAuth
+// @Router       /transaction/ticket/{id} [get]
+func GetTicket(c *gin.Context) {
+	ticketID := c.Param("id")
+	if !checkTicketOwner(c, ticketID) {
+		return
+	}
+
+	ticket, err := mneeClient(c).GetTicket(c.Request.Context(), ticketID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Message: err.Error()})
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"data":    ticket,
+		"data":    types.NewTicket(ticket),
 	})
 }
+
+// checkTicketOwner answers 404 for a ticket the caller did not create, as
+// recorded by the webhook relay or the ledger, so tickets cannot be read
+// across clients. With authentication disabled every ticket is readable. On
+// failure it writes the error response and returns false.
+func checkTicketOwner(c *gin.Context, ticketID string) bool {
+	caller := clientID(c)
+	if caller == "" || webhooks.Instance.Owner(ticketID) == caller {
+		return true
+	}
+
+	if ledger.Instance != nil {
+		_, total, err := ledger.Instance.List(c.Request.Context(), ledger.Filter{ClientID: caller, TicketID: ticketID, Limit: 1})
+		if err != nil {
+			respondError(c, err)
+			return false
+		}
+		if total > 0 {
+			return true
+		}
+	}
+
+	respondError(c, services.ErrTicketNotFound)
+	return false
+}
+
+const (
+	defaultStreamInterval = 2 * time.Second
+	minStreamInterval     = 500 * time.Millisecond
+	defaultStreamTimeout  = 5 * time.Minute
+	maxStreamTimeout      = 30 * time.Minute
+	streamHeartbeat       = 15 * time.Second
+)
+
+// StreamTicket godoc
+// @Summary      Stream Ticket Status
+// @Description  Streams Server-Sent Events for a transaction ticket. A `status` event carrying the ticket is sent on every status change, including new errors. The stream closes after the ticket reaches a final status. It also closes after an `error` event, after a `timeout` event once the timeout elapses, or when the server shuts down. A ticket the cosigner has not recorded yet is waited for; a ticket another client submitted is answered with 404.
+// @Tags         Transaction
+// @Produce      text/event-stream
+// @Param        ticketId  path      string  true   "Ticket ID"
+// @Param        interval  query     string  false  "Polling interval, e.g. 2s (minimum 500ms)"
+// @Param        timeout   query     string  false  "Closes the stream after this long, e.g. 5m (maximum 30m)"
+// @Success      200       {string}  string  "event: status"
+// @Failure      400       {object}  models.GenericFailureResponse
+// @Failure      401       {object}  models.GenericFailureResponse
+// @Failure      404       {object}  models.GenericFailureResponse
+// @Failure      429       {object}  models.GenericFailureResponse
+// @Security     ApiKeyAuth
+// @Router       /transaction/status/{ticketId}/stream [get]
+func StreamTicket(c *gin.Context) {
+	ticketID := c.Param("ticketId")
+	if !checkTicketOwner(c, ticketID) {
+		return
+	}
+
+	interval, ok := durationQuery(c, "interval", defaultStreamInterval, minStreamInterval, time.Hour)
+	if !ok {
+		return
+	}
+	timeout, ok := durationQuery(c, "timeout", defaultStreamTimeout, time.Second, maxStreamTimeout)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+	defer cancel()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	var last string
+	lastWrite := time.Now()
+	for {
+		ticket, err := mneeClient(c).GetTicket(ctx, ticketID)
+		switch {
+		case err == nil:
+			state := string(ticket.Status) + "|" + strings.Join(ticket.Errors, "|")
+			if state != last {
+				last = state
+				c.SSEvent("status", types.NewTicket(ticket))
+				c.Writer.Flush()
+				lastWrite = time.Now()
+			}
+			if services.TicketFinished(ticket) {
+				return
+			}
+		case errors.Is(err, services.ErrTicketNotFound):
+		case ctx.Err() != nil:
+		default:
+			status, code := translateError(err)
+			c.SSEvent("error", models.GenericFailureResponse{Success: false, Code: code, Message: errorMessage(c, status, code, err)})
+			c.Writer.Flush()
+			return
+		}
+
+		if time.Since(lastWrite) >= streamHeartbeat {
+			_, _ = c.Writer.WriteString(": keep-alive\n\n")
+			c.Writer.Flush()
+			lastWrite = time.Now()
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-lifecycle.Draining():
+			// The server is shutting down; the client reconnects to another
+			// instance.
+			return
+		case <-ctx.Done():
+			if c.Request.Context().Err() == nil {
+				c.SSEvent("timeout", gin.H{"ticketId": ticketID})
+				c.Writer.Flush()
+			}
+			return
+		}
+	}
+}
+
+// durationQuery parses the duration query parameter name, writing a 400 and
+// returning false when it is malformed or outside [min, max].
+func durationQuery(c *gin.Context, name string, fallback, min, max time.Duration) (time.Duration, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return fallback, true
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < min || duration > max {
+		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeValidationFailed, Message: name + " must be a duration between " + min.String() + " and " + max.String()})
+		return 0, false
+	}
+	return duration, true
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/ledger"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/models"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/webhooks"
+)
+
+func TestPollTicket(t *testing.T) {
+	ticketID := "68eed7b9-0c1c-4a8e-9b6e-2f2f6d3c1a10"
+	txid := "7fbe3f6a"
+
+	tests := []struct {
+		name   string
+		fail   error
+		status int
+		code   string
+	}{
+		{name: "finished", status: http.StatusOK},
+		{name: "deadline", fail: context.DeadlineExceeded, status: http.StatusGatewayTimeout, code: models.CodeUpstreamTimeout},
+		{name: "not found", fail: services.ErrTicketNotFound, status: http.StatusNotFound, code: models.CodeTicketNotFound},
+		{name: "circuit open", fail: services.ErrCircuitOpen, status: http.StatusServiceUnavailable, code: models.CodeCircuitOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := services.NewFakeClient()
+			if err := client.PutTicket(mnee.Ticket{ID: &ticketID, TxID: &txid, Status: mnee.SUCCESS, Errors: []string{}}); err != nil {
+				t.Fatal(err)
+			}
+			client.FailWith("PollTicket", tt.fail)
+
+			status, response := serve(t, client, http.MethodGet, "/ticket/:ticketId", "/ticket/"+ticketID, PollTicket, nil)
+			if status != tt.status || response.Code != tt.code {
+				t.Fatalf("got %d %q (%s), want %d %q", status, response.Code, response.Message, tt.status, tt.code)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var ticket mnee.Ticket
+			if err := json.Unmarshal(response.Data, &ticket); err != nil {
+				t.Fatal(err)
+			}
+			if ticket.Status != mnee.SUCCESS || ticket.TxID == nil || *ticket.TxID != txid {
+				t.Fatalf("got ticket %+v", ticket)
+			}
+		})
+	}
+}
+
+func TestTicketOwner(t *testing.T) {
+	previousLedger, previousRelay := ledger.Instance, webhooks.Instance
+	t.Cleanup(func() { ledger.Instance, webhooks.Instance = previousLedger, previousRelay })
+
+	store, err := ledger.OpenSQLite(filepath.Join(t.TempDir(), "ledger.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	ledger.Instance = store
+	webhooks.Instance = webhooks.NewRelay(webhooks.Config{PublicURL: "https://relay.example.com", CallbackSecret: "relay-secret"})
+
+	client := services.NewFakeClient()
+	callbackURL, callbackSecret := "https://relay.example.com/api/webhooks/mnee", "relay-secret"
+	for _, id := range []string{"in-ledger", "in-relay", "unrecorded"} {
+		ticketID := id
+		if err := client.PutTicket(mnee.Ticket{ID: &ticketID, Status: mnee.BROADCASTING, CallbackURL: &callbackURL, CallbackSecret: &callbackSecret, Errors: []string{}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	transfer := ledger.Transfer{ID: ledger.NewID(), ClientID: "alice", Kind: ledger.KindTransfer, Async: true, Status: ledger.StatusPending, TicketID: "in-ledger", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := store.Record(t.Context(), transfer); err != nil {
+		t.Fatal(err)
+	}
+	webhooks.Instance.Track("in-relay", "alice", nil, nil)
+
+	tests := []struct {
+		name     string
+		clientID string
+		ticketID string
+		status   int
+	}{
+		{name: "owner, from the ledger", clientID: "alice", ticketID: "in-ledger", status: http.StatusOK},
+		{name: "owner, from the relay", clientID: "alice", ticketID: "in-relay", status: http.StatusOK},
+		{name: "another client, from the ledger", clientID: "bob", ticketID: "in-ledger", status: http.StatusNotFound},
+		{name: "another client, from the relay", clientID: "bob", ticketID: "in-relay", status: http.StatusNotFound},
+		{name: "unrecorded ticket", clientID: "alice", ticketID: "unrecorded", status: http.StatusNotFound},
+		{name: "authentication disabled", ticketID: "unrecorded", status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, response := serveAs(t, client, tt.clientID, http.MethodGet, "/ticket/:id", "/ticket/"+tt.ticketID, GetTicket, nil)
+			if status != tt.status {
+				t.Fatalf("got %d %q (%s), want %d", status, response.Code, response.Message, tt.status)
+			}
+			if status != http.StatusOK {
+				if response.Code != models.CodeTicketNotFound {
+					t.Fatalf("got code %q, want %q", response.Code, models.CodeTicketNotFound)
+				}
+				return
+			}
+
+			var fields map[string]any
+			if err := json.Unmarshal(response.Data, &fields); err != nil {
+				t.Fatal(err)
+			}
+			if fields["id"] != tt.ticketID {
+				t.Fatalf("got ticket %v, want %s", fields["id"], tt.ticketID)
+			}
+			for _, field := range []string{"callback_url", "callback_secret"} {
+				if _, ok := fields[field]; ok {
+					t.Fatalf("the response carries %s", field)
+				}
+			}
+		})
+	}
+}
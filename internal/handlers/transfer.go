@@ -1,127 +1,167 @@
 package handlers
 
 import (
+	"errors"
+	"math/bits"
 	"net/http"
+	"slices"
+	"strconv"
 
+	primitives "github.com/bsv-blockchain/go-sdk/primitives/ec"
 	"github.com/bsv-blockchain/go-sdk/script"
 	"github.com/gin-gonic/gin"
 	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/amount"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/middleware"
 	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/models" // Import models
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/ratelimit"
 	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/wallets"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/webhooks"
 )
 
+// TransferRecipient carries exactly one of Amount, in display units, or
+// AtomicAmount, in the token's smallest unit.
+type TransferRecipient struct {
+	Address      string         `json:"address" binding:"required" example:"1G6CB3Ch4zFkPmuhZzEyChQmrQPfi86qk3"`
+	Amount       amount.Decimal `json:"amount,omitempty" swaggertype:"string" example:"0.1"`
+	AtomicAmount *uint64        `json:"atomicAmount,omitempty" example:"10000"`
+}
+
+// TransferRequest signs with either Wifs or the keys of managed WalletIds.
 type TransferRequest struct {
-	Request []struct {
-		Address string  `json:"address" binding:"required" example:"1G6CB3Ch4zFkPmuhZzEyChQmrQPfi86qk3"`
-		Amount  float64 `json:"amount" binding:"required" example:"0.1"`
-	} `json:"request" binding:"required"`
-	Wifs []string `json:"wifs" binding:"required" example:"L1dRKo...,K2..."`
+	Request   []TransferRecipient `json:"request" binding:"required,dive"`
+	Wifs      []string            `json:"wifs,omitempty" example:"L1dRKo...,K2..."`
+	WalletIds []string            `json:"walletIds,omitempty" example:"w_5f2c9a..."`
+	DryRun    bool                `json:"dryRun,omitempty" example:"false"`
+
+	// CallbackUrl and CallbackSecret only apply to asynchronous transfers.
+	CallbackUrl    *string `json:"callbackUrl,omitempty" binding:"omitempty,http_url" example:"https://example.com/mnee/callback"`
+	CallbackSecret *string `json:"callbackSecret,omitempty" example:"s3cr3t"`
 }
 
 type RawTxRequest struct {
-	RawTxHex string `json:"rawTxHex" binding:"required" example:"01000000..."`
+	RawTxHex       string  `json:"rawTxHex" binding:"required" example:"01000000..."`
+	CallbackUrl    *string `json:"callbackUrl,omitempty" binding:"omitempty,http_url" example:"https://example.com/mnee/callback"`
+	CallbackSecret *string `json:"callbackSecret,omitempty" example:"s3cr3t"`
 }
 
 // TransferSync godoc
 // @Summary      Synchronous Transfer
-// @Description  Executes a transfer using multiple WIFs and waits for cosigner response. Returns final TxID.
+// @Description  Executes a transfer using multiple WIFs, or managed walletIds, and waits for cosigner response. Returns final TxID. With dryRun set, the transfer is built and signed but not submitted, and the preview is returned instead.
 // @Tags         Transfer
 // @Accept       json
 // @Produce      json
 // @Param        request body TransferRequest true "Transfer Parameters"
-// @Success      200     {object} models.TransferSyncSuccessResponse
+// @Param        Idempotency-Key header string false "Replays the first response when a request is retried with the same key"
+// @Success      200     {object} models.TransferSyncSuccessResponse "Submitted transfer, or models.DryRunSuccessResponse with dryRun"
 // @Failure      422     {object} models.GenericFailureResponse
 // @Failure      400     {object} models.GenericFailureResponse
+// @Failure      401     {object} models.GenericFailureResponse
+// @Failure      409     {object} models.GenericFailureResponse
+// @Failure      413     {object} models.GenericFailureResponse
+// @Failure      402     {object} models.GenericFailureResponse
+// @Failure      403     {object} models.GenericFailureResponse
+// @Failure      429     {object} models.GenericFailureResponse
+// @Failure      502     {object} models.GenericFailureResponse
+// @Failure      503     {object} models.GenericFailureResponse
+// @Failure      504     {object} models.GenericFailureResponse
 // @Failure      500     {object} models.GenericFailureResponse
+// @Security     ApiKeyAuth
 // @Router       /transaction/transfer [post]
 func TransferSync(c *gin.Context) {
 	var req TransferRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusUnprocessableEntity, models.GenericFailureResponse{Success: false, Message: "Unprocessable Entity: " + err.Error()})
+		c.JSON(http.StatusUnprocessableEntity, models.GenericFailureResponse{Success: false, Code: models.CodeInvalidRequestBody, Message: "Unprocessable Entity: " + err.Error()})
 		return
 	}
 
-	var dtos []mnee.TransferMneeDTO
-	for _, r := range req.Request {
-		address, err := script.NewAddressFromString(r.Address)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Message: "Invalid wallet address in request: " + r.Address})
-			return
-		}
-
-		if r.Amount <= 0 {
-			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Message: "Amount must be greater than 0"})
-			return
-		}
+	dtos, recipients, ok := prepareTransfer(c, &req)
+	if !ok {
+		return
+	}
 
-		atomicAmount := uint64(r.Amount * 100000)
-		dtos = append(dtos, mnee.TransferMneeDTO{
-			Address: address.AddressString,
-			Amount:  atomicAmount,
-		})
+	if req.DryRun {
+		respondDryRun(c, req.Wifs, dtos, recipients)
+		return
 	}
 
-	resp, err := services.Instance.SynchronousTransfer(c.Request.Context(), req.Wifs, dtos, false, nil)
+	resp, err := mneeClient(c).SynchronousTransfer(c.Request.Context(), req.Wifs, dtos, false, nil)
+	recordTransfer(c, false, req.Wifs, dtos, resp, nil, err)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Message: err.Error()})
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"data":    resp,
+		"data": models.TransferResultWrapper{
+			Txid:       resp.Txid,
+			Txhex:      resp.Txhex,
+			Recipients: recipients,
+		},
 	})
 }
 
 // TransferAsync godoc
 // @Summary      Asynchronous Transfer
-// @Description  Executes a transfer using multiple WIFs and returns a ticket ID immediately for polling.
+// @Description  Executes a transfer using multiple WIFs, or managed walletIds, and returns a ticket ID immediately for polling. Ticket updates are posted to callbackUrl, signed with callbackSecret. With dryRun set, the transfer is built and signed but not submitted, and the preview is returned instead.
 // @Tags         Transfer
 // @Accept       json
 // @Produce      json
 // @Param        request body TransferRequest true "Transfer Parameters"
-// @Success      200     {object} models.TransferAsyncSuccessResponse
+// @Param        Idempotency-Key header string false "Replays the first response when a request is retried with the same key"
+// @Success      200     {object} models.TransferAsyncSuccessResponse "Ticket, or models.DryRunSuccessResponse with dryRun"
 // @Failure      422     {object} models.GenericFailureResponse
 // @Failure      400     {object} models.GenericFailureResponse
+// @Failure      401     {object} models.GenericFailureResponse
+// @Failure      409     {object} models.GenericFailureResponse
+// @Failure      413     {object} models.GenericFailureResponse
+// @Failure      402     {object} models.GenericFailureResponse
+// @Failure      403     {object} models.GenericFailureResponse
+// @Failure      429     {object} models.GenericFailureResponse
+// @Failure      502     {object} models.GenericFailureResponse
+// @Failure      503     {object} models.GenericFailureResponse
+// @Failure      504     {object} models.GenericFailureResponse
 // @Failure      500     {object} models.GenericFailureResponse
+// @Security     ApiKeyAuth
 // @Router       /transaction/transfer-async [post]
 func TransferAsync(c *gin.Context) {
 	var req TransferRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusUnprocessableEntity, models.GenericFailureResponse{Success: false, Message: "Unprocessable Entity: " + err.Error()})
+		c.JSON(http.StatusUnprocessableEntity, models.GenericFailureResponse{Success: false, Code: models.CodeInvalidRequestBody, Message: "Unprocessable Entity: " + err.Error()})
 		return
 	}
 
-	var dtos []mnee.TransferMneeDTO
-	for _, r := range req.Request {
-		address, err := script.NewAddressFromString(r.Address)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Message: "Invalid wallet address in request: " + r.Address})
-			return
-		}
+	if !checkCallbackURL(c, req.CallbackUrl) {
+		return
+	}
 
-		if r.Amount <= 0 {
-			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Message: "Amount must be greater than 0"})
-			return
-		}
+	dtos, recipients, ok := prepareTransfer(c, &req)
+	if !ok {
+		return
+	}
 
-		atomicAmount := uint64(r.Amount * 100000)
-		dtos = append(dtos, mnee.TransferMneeDTO{
-			Address: address.AddressString,
-			Amount:  atomicAmount,
-		})
+	if req.DryRun {
+		respondDryRun(c, req.Wifs, dtos, recipients)
+		return
 	}
 
-	ticketID, err := services.Instance.AsynchronousTransfer(c.Request.Context(), req.Wifs, dtos, false, nil, nil, nil)
+	callbackURL, callbackSecret := webhooks.Instance.Callback(req.CallbackUrl, req.CallbackSecret)
+	ticketID, err := mneeClient(c).AsynchronousTransfer(c.Request.Context(), req.Wifs, dtos, false, nil, callbackURL, callbackSecret)
+	recordTransfer(c, true, req.Wifs, dtos, nil, ticketID, err)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Message: err.Error()})
+		respondError(c, err)
 		return
 	}
+	middleware.SetInFlightTicket(c, *ticketID)
+	webhooks.Instance.Track(*ticketID, clientID(c), req.CallbackUrl, req.CallbackSecret)
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"data": gin.H{
-			"ticketId": *ticketID,
+		"data": models.TicketIdWrapper{
+			TicketId:   *ticketID,
+			Recipients: recipients,
 		},
 	})
 }
@@ -133,26 +173,42 @@ func TransferAsync(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        request body RawTxRequest true "Raw Hex"
+// @Param        Idempotency-Key header string false "Replays the first response when a request is retried with the same key"
 // @Success      200     {object} models.TransferSyncSuccessResponse
 // @Failure      422     {object} models.GenericFailureResponse
 // @Failure      400     {object} models.GenericFailureResponse
+// @Failure      401     {object} models.GenericFailureResponse
+// @Failure      409     {object} models.GenericFailureResponse
+// @Failure      413     {object} models.GenericFailureResponse
+// @Failure      402     {object} models.GenericFailureResponse
+// @Failure      403     {object} models.GenericFailureResponse
+// @Failure      429     {object} models.GenericFailureResponse
+// @Failure      502     {object} models.GenericFailureResponse
+// @Failure      503     {object} models.GenericFailureResponse
+// @Failure      504     {object} models.GenericFailureResponse
 // @Failure      500     {object} models.GenericFailureResponse
+// @Security     ApiKeyAuth
 // @Router       /transaction/submit-rawtx [post]
 func SubmitRawTxSync(c *gin.Context) {
 	var req RawTxRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusUnprocessableEntity, models.GenericFailureResponse{Success: false, Message: "Unprocessable Entity: " + err.Error()})
+		c.JSON(http.StatusUnprocessableEntity, models.GenericFailureResponse{Success: false, Code: models.CodeInvalidRequestBody, Message: "Unprocessable Entity: " + err.Error()})
 		return
 	}
 
 	if req.RawTxHex == "" {
-		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Message: "rawTxHex cannot be empty"})
+		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeInvalidRawTx, Message: "rawTxHex cannot be empty"})
+		return
+	}
+
+	if !limitRawTxSources(c, req.RawTxHex) {
 		return
 	}
 
-	resp, err := services.Instance.SubmitRawTxSync(c.Request.Context(), req.RawTxHex)
+	resp, err := mneeClient(c).SubmitRawTxSync(c.Request.Context(), req.RawTxHex)
+	recordRawTx(c, false, req.RawTxHex, resp, nil, err)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Message: err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -164,33 +220,56 @@ func SubmitRawTxSync(c *gin.Context) {
 
 // SubmitRawTxAsync godoc
 // @Summary      Submit Raw Transaction (Asynchronous)
-// @Description  Submits a pre-signed raw transaction hex and returns a ticket ID immediately.
+// @Description  Submits a pre-signed raw transaction hex and returns a ticket ID immediately. Ticket updates are posted to callbackUrl, signed with callbackSecret.
 // @Tags         Transfer
 // @Accept       json
 // @Produce      json
 // @Param        request body RawTxRequest true "Raw Hex"
+// @Param        Idempotency-Key header string false "Replays the first response when a request is retried with the same key"
 // @Success      200     {object} models.TransferAsyncSuccessResponse
 // @Failure      422     {object} models.GenericFailureResponse
 // @Failure      400     {object} models.GenericFailureResponse
+// @Failure      401     {object} models.GenericFailureResponse
+// @Failure      409     {object} models.GenericFailureResponse
+// @Failure      413     {object} models.GenericFailureResponse
+// @Failure      402     {object} models.GenericFailureResponse
+// @Failure      403     {object} models.GenericFailureResponse
+// @Failure      429     {object} models.GenericFailureResponse
+// @Failure      502     {object} models.GenericFailureResponse
+// @Failure      503     {object} models.GenericFailureResponse
+// @Failure      504     {object} models.GenericFailureResponse
 // @Failure      500     {object} models.GenericFailureResponse
+// @Security     ApiKeyAuth
 // @Router       /transaction/submit-rawtx-async [post]
 func SubmitRawTxAsync(c *gin.Context) {
 	var req RawTxRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusUnprocessableEntity, models.GenericFailureResponse{Success: false, Message: "Unprocessable Entity: " + err.Error()})
+		c.JSON(http.StatusUnprocessableEntity, models.GenericFailureResponse{Success: false, Code: models.CodeInvalidRequestBody, Message: "Unprocessable Entity: " + err.Error()})
 		return
 	}
 
 	if req.RawTxHex == "" {
-		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Message: "rawTxHex cannot be empty"})
+		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeInvalidRawTx, Message: "rawTxHex cannot be empty"})
+		return
+	}
+
+	if !checkCallbackURL(c, req.CallbackUrl) {
+		return
+	}
+
+	if !limitRawTxSources(c, req.RawTxHex) {
 		return
 	}
 
-	ticketID, err := services.Instance.SubmitRawTxAsync(c.Request.Context(), req.RawTxHex, nil, nil)
+	callbackURL, callbackSecret := webhooks.Instance.Callback(req.CallbackUrl, req.CallbackSecret)
+	ticketID, err := mneeClient(c).SubmitRawTxAsync(c.Request.Context(), req.RawTxHex, callbackURL, callbackSecret)
+	recordRawTx(c, true, req.RawTxHex, nil, ticketID, err)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Message: err.Error()})
+		respondError(c, err)
 		return
 	}
+	middleware.SetInFlightTicket(c, *ticketID)
+	webhooks.Instance.Track(*ticketID, clientID(c), req.CallbackUrl, req.CallbackSecret)
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -199,3 +278,148 @@ func SubmitRawTxAsync(c *gin.Context) {
 		},
 	})
 }
+
+// prepareTransfer validates the WIFs and recipients of req, converting each
+// amount using the decimals reported by the cosigner. Managed wallets are
+// resolved into req.Wifs, and once everything is valid the source addresses
+// are rate limited. On failure it writes the error response and returns false.
+func prepareTransfer(c *gin.Context, req *TransferRequest) ([]mnee.TransferMneeDTO, []models.RecipientAmount, bool) {
+	switch {
+	case len(req.Wifs) > 0 && len(req.WalletIds) > 0:
+		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeValidationFailed, Message: "Provide either wifs or walletIds, not both"})
+		return nil, nil, false
+	case len(req.WalletIds) > 0:
+		if wallets.Instance == nil {
+			respondError(c, wallets.ErrDisabled)
+			return nil, nil, false
+		}
+
+		wifs, err := wallets.Instance.Wifs(req.WalletIds, clientID(c))
+		if err != nil {
+			respondError(c, err)
+			return nil, nil, false
+		}
+		req.Wifs = wifs
+	case len(req.Wifs) == 0:
+		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeValidationFailed, Message: "wifs or walletIds is required"})
+		return nil, nil, false
+	}
+
+	sources := make([]string, 0, len(req.Wifs))
+	for i, wif := range req.Wifs {
+		privateKey, err := primitives.PrivateKeyFromWif(wif)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeInvalidWif, Message: "Invalid WIF at index " + strconv.Itoa(i)})
+			return nil, nil, false
+		}
+		address, err := script.NewAddressFromPublicKey(privateKey.PubKey(), true)
+		if err != nil {
+			respondError(c, err)
+			return nil, nil, false
+		}
+		if !slices.Contains(sources, address.AddressString) {
+			sources = append(sources, address.AddressString)
+		}
+	}
+
+	config, err := mneeClient(c).GetConfig(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return nil, nil, false
+	}
+
+	dtos, recipients, ok := prepareRecipients(c, req.Request, config.Decimals)
+	if !ok {
+		return nil, nil, false
+	}
+
+	// Only requests that are about to be built and signed take a token, so a
+	// malformed request cannot lock an address out of transfers.
+	if !limitSources(c, sources...) {
+		return nil, nil, false
+	}
+	return dtos, recipients, true
+}
+
+// limitRawTxSources rate limits the addresses that signed rawTxHex, as
+// prepareTransfer does for the source addresses of a transfer. It runs after
+// every other check of the request, right before submission. On failure it
+// writes the error response and returns false.
+func limitRawTxSources(c *gin.Context, rawTxHex string) bool {
+	summary, err := services.SummarizeTx(rawTxHex, "")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeInvalidRawTx, Message: "Invalid raw transaction: " + err.Error()})
+		return false
+	}
+
+	return limitSources(c, summary.Sources...)
+}
+
+// limitSources takes a token for each of the source addresses of a request
+// that passed validation. On failure it writes the error response and returns
+// false.
+func limitSources(c *gin.Context, sources ...string) bool {
+	if ok, wait := ratelimit.SourceAddresses.Allow(sources...); !ok {
+		middleware.AbortRateLimited(c, wait, "Too many transfers from these source addresses")
+		return false
+	}
+	return true
+}
+
+// prepareRecipients validates recipient addresses and converts their amounts
+// to atomic units, rejecting amounts whose sum does not fit in a uint64. On
+// failure it writes the error response and returns false.
+func prepareRecipients(c *gin.Context, request []TransferRecipient, decimals uint8) ([]mnee.TransferMneeDTO, []models.RecipientAmount, bool) {
+	var dtos []mnee.TransferMneeDTO
+	var recipients []models.RecipientAmount
+	var total uint64
+	for _, r := range request {
+		address, err := parseAddress(c, r.Address)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeInvalidAddress, Message: invalidAddress("Invalid wallet address in request", r.Address, err)})
+			return nil, nil, false
+		}
+
+		atomicAmount, err := parseRecipientAmount(r, decimals)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeInvalidAmount, Message: err.Error()})
+			return nil, nil, false
+		}
+
+		if atomicAmount == 0 {
+			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeInvalidAmount, Message: "Amount must be greater than 0"})
+			return nil, nil, false
+		}
+
+		var carry uint64
+		if total, carry = bits.Add64(total, atomicAmount, 0); carry != 0 {
+			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeInvalidAmount, Message: "Total amount is too large"})
+			return nil, nil, false
+		}
+
+		dtos = append(dtos, mnee.TransferMneeDTO{
+			Address: address.AddressString,
+			Amount:  atomicAmount,
+		})
+		recipients = append(recipients, models.RecipientAmount{
+			Address:      address.AddressString,
+			AtomicAmount: atomicAmount,
+			Amount:       amount.Format(atomicAmount, decimals),
+		})
+	}
+
+	return dtos, recipients, true
+}
+
+func parseRecipientAmount(r TransferRecipient, decimals uint8) (uint64, error) {
+	switch {
+	case r.AtomicAmount != nil && r.Amount != "":
+		return 0, errors.New("provide either amount or atomicAmount, not both")
+	case r.AtomicAmount != nil:
+		return *r.AtomicAmount, nil
+	case r.Amount == "":
+		return 0, errors.New("amount or atomicAmount is required")
+	default:
+		return amount.Parse(string(r.Amount), decimals)
+	}
+}
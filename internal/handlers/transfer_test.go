@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/models"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/ratelimit"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
+)
+
+func TestTransferSync(t *testing.T) {
+	wif, sender := newKey(t)
+	_, recipient := newKey(t)
+	atomic := func(n uint64) *uint64 { return &n }
+
+	tests := []struct {
+		name      string
+		request   TransferRequest
+		status    int
+		code      string
+		delivered float64
+	}{
+		{
+			name:      "display amount",
+			request:   TransferRequest{Wifs: []string{wif}, Request: []TransferRecipient{{Address: recipient, Amount: "0.1"}}},
+			status:    http.StatusOK,
+			delivered: 10000,
+		},
+		{
+			name:      "atomic amount",
+			request:   TransferRequest{Wifs: []string{wif}, Request: []TransferRecipient{{Address: recipient, AtomicAmount: atomic(25000)}}},
+			status:    http.StatusOK,
+			delivered: 25000,
+		},
+		{
+			name:    "insufficient balance",
+			request: TransferRequest{Wifs: []string{wif}, Request: []TransferRecipient{{Address: recipient, AtomicAmount: atomic(500000)}}},
+			status:  http.StatusPaymentRequired,
+			code:    models.CodeInsufficientBalance,
+		},
+		{
+			name:    "no keys",
+			request: TransferRequest{Request: []TransferRecipient{{Address: recipient, Amount: "0.1"}}},
+			status:  http.StatusBadRequest,
+			code:    models.CodeValidationFailed,
+		},
+		{
+			name:    "invalid WIF",
+			request: TransferRequest{Wifs: []string{"not-a-wif"}, Request: []TransferRecipient{{Address: recipient, Amount: "0.1"}}},
+			status:  http.StatusBadRequest,
+			code:    models.CodeInvalidWif,
+		},
+		{
+			name:    "invalid recipient",
+			request: TransferRequest{Wifs: []string{wif}, Request: []TransferRecipient{{Address: "not-an-address", Amount: "0.1"}}},
+			status:  http.StatusBadRequest,
+			code:    models.CodeInvalidAddress,
+		},
+		{
+			name:    "zero amount",
+			request: TransferRequest{Wifs: []string{wif}, Request: []TransferRecipient{{Address: recipient, AtomicAmount: atomic(0)}}},
+			status:  http.StatusBadRequest,
+			code:    models.CodeInvalidAmount,
+		},
+		{
+			name:    "excess precision",
+			request: TransferRequest{Wifs: []string{wif}, Request: []TransferRecipient{{Address: recipient, Amount: "0.000001"}}},
+			status:  http.StatusBadRequest,
+			code:    models.CodeInvalidAmount,
+		},
+		{
+			name:    "both amounts",
+			request: TransferRequest{Wifs: []string{wif}, Request: []TransferRecipient{{Address: recipient, Amount: "0.1", AtomicAmount: atomic(10000)}}},
+			status:  http.StatusBadRequest,
+			code:    models.CodeInvalidAmount,
+		},
+		{
+			name: "total overflows",
+			request: TransferRequest{Wifs: []string{wif}, Request: []TransferRecipient{
+				{Address: recipient, AtomicAmount: atomic(math.MaxUint64)},
+				{Address: sender, AtomicAmount: atomic(1)},
+			}},
+			status: http.StatusBadRequest,
+			code:   models.CodeInvalidAmount,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := services.NewFakeClient()
+			if err := client.Fund(sender, 100000); err != nil {
+				t.Fatal(err)
+			}
+
+			status, response := serve(t, client, http.MethodPost, "/transfer", "/transfer", TransferSync, tt.request)
+			if status != tt.status || response.Code != tt.code {
+				t.Fatalf("got %d %q (%s), want %d %q", status, response.Code, response.Message, tt.status, tt.code)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var result models.TransferResultWrapper
+			if err := json.Unmarshal(response.Data, &result); err != nil {
+				t.Fatal(err)
+			}
+			if result.Txid == nil || *result.Txid == "" {
+				t.Fatal("missing txid")
+			}
+
+			balances, err := client.GetBalances(t.Context(), []string{recipient})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if balances[0].Amt != tt.delivered {
+				t.Fatalf("recipient holds %v, want %v", balances[0].Amt, tt.delivered)
+			}
+		})
+	}
+}
+
+func TestSourceAddressRateLimit(t *testing.T) {
+	previous := ratelimit.SourceAddresses
+	t.Cleanup(func() { ratelimit.SourceAddresses = previous })
+
+	wif, sender := newKey(t)
+	_, recipient := newKey(t)
+
+	rawTx := func(t *testing.T, client *services.FakeClient) any {
+		dtos := []mnee.TransferMneeDTO{{Address: recipient, Amount: 1000}}
+		rawTx, err := client.PartialSign(t.Context(), []string{wif}, dtos, false, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return RawTxRequest{RawTxHex: *rawTx}
+	}
+	transfer := func(*testing.T, *services.FakeClient) any {
+		return TransferRequest{Wifs: []string{wif}, Request: []TransferRecipient{{Address: recipient, Amount: "0.01"}}}
+	}
+
+	type invalidRequest struct {
+		body   any
+		status int
+		code   string
+	}
+	tests := []struct {
+		name    string
+		handler gin.HandlerFunc
+		body    func(*testing.T, *services.FakeClient) any
+		invalid []invalidRequest
+	}{
+		{
+			name: "transfer", handler: TransferSync, body: transfer,
+			invalid: []invalidRequest{
+				{body: TransferRequest{Wifs: []string{wif}, Request: []TransferRecipient{{Address: "not-an-address", Amount: "0.01"}}}, status: http.StatusBadRequest, code: models.CodeInvalidAddress},
+				{body: TransferRequest{Wifs: []string{wif}, Request: []TransferRecipient{{Address: recipient, Amount: "0"}}}, status: http.StatusBadRequest, code: models.CodeInvalidAmount},
+				{body: TransferRequest{Wifs: []string{wif}, Request: []TransferRecipient{{Address: recipient, Amount: "0.000001"}}}, status: http.StatusBadRequest, code: models.CodeInvalidAmount},
+			},
+		},
+		{
+			name: "raw transaction", handler: SubmitRawTxSync, body: rawTx,
+			invalid: []invalidRequest{
+				{body: RawTxRequest{RawTxHex: "zz"}, status: http.StatusBadRequest, code: models.CodeInvalidRawTx},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ratelimit.SourceAddresses = ratelimit.New(1)
+			client := services.NewFakeClient()
+			if err := client.Fund(sender, 100000); err != nil {
+				t.Fatal(err)
+			}
+			body := tt.body(t, client)
+
+			// Rejected requests do not take the address's only token.
+			for _, invalid := range tt.invalid {
+				status, response := serve(t, client, http.MethodPost, "/submit", "/submit", tt.handler, invalid.body)
+				if status != invalid.status || response.Code != invalid.code {
+					t.Fatalf("invalid request: got %d %q, want %d %q", status, response.Code, invalid.status, invalid.code)
+				}
+			}
+
+			if status, response := serve(t, client, http.MethodPost, "/submit", "/submit", tt.handler, body); status != http.StatusOK {
+				t.Fatalf("first submission: got %d %q (%s)", status, response.Code, response.Message)
+			}
+			status, response := serve(t, client, http.MethodPost, "/submit", "/submit", tt.handler, body)
+			if status != http.StatusTooManyRequests || response.Code != models.CodeRateLimited {
+				t.Fatalf("second submission: got %d %q, want 429 %q", status, response.Code, models.CodeRateLimited)
+			}
+		})
+	}
+}
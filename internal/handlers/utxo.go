@@ -3,12 +3,9 @@ package handlers
 import (
 	"net/http"
 	"strconv"
-	"strings"
 
-	"github.com/bsv-blockchain/go-sdk/script"
 	"github.com/gin-gonic/gin"
 	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/models" // Import models
-	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
 )
 
 // GetAllUtxos godoc
@@ -19,37 +16,24 @@ import (
 // @Param        addresses query     string  true  "Comma-separated list of Wallet Addresses"
 // @Success      200       {object}  models.GetUtxosSuccessResponse
 // @Failure      400       {object}  models.GenericFailureResponse
+// @Failure      401       {object}  models.GenericFailureResponse
+// @Failure      403       {object}  models.GenericFailureResponse
+// @Failure      429       {object}  models.GenericFailureResponse
+// @Failure      502       {object}  models.GenericFailureResponse
+// @Failure      503       {object}  models.GenericFailureResponse
+// @Failure      504       {object}  models.GenericFailureResponse
 // @Failure      500       {object}  models.GenericFailureResponse
+// @Security     ApiKeyAuth
 // @Router       /utxos/all [get]
 func GetAllUtxos(c *gin.Context) {
-	addrStr := c.Query("addresses")
-	if addrStr == "" {
-		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Message: "addresses query parameter is required"})
+	addresses, ok := queryAddresses(c)
+	if !ok {
 		return
 	}
 
-	rawAddresses := strings.Split(addrStr, ",")
-	var addresses []string
-	for _, addr := range rawAddresses {
-		trimmed := strings.TrimSpace(addr)
-		if trimmed != "" {
-			address, err := script.NewAddressFromString(trimmed)
-			if err != nil {
-				c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Message: "Invalid wallet address: " + trimmed})
-				return
-			}
-			addresses = append(addresses, address.AddressString)
-		}
-	}
-
-	if len(addresses) == 0 {
-		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Message: "No valid addresses provided"})
-		return
-	}
-
-	txos, err := services.Instance.GetUnspentTxos(c.Request.Context(), addresses)
+	txos, err := mneeClient(c).GetUnspentTxos(c.Request.Context(), addresses)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Message: err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -66,31 +50,18 @@ func GetAllUtxos(c *gin.Context) {
 // @Param        size      query     int     false "Page size (default 10)"
 // @Success      200       {object}  models.GetUtxosSuccessResponse
 // @Failure      400       {object}  models.GenericFailureResponse
+// @Failure      401       {object}  models.GenericFailureResponse
+// @Failure      403       {object}  models.GenericFailureResponse
+// @Failure      429       {object}  models.GenericFailureResponse
+// @Failure      502       {object}  models.GenericFailureResponse
+// @Failure      503       {object}  models.GenericFailureResponse
+// @Failure      504       {object}  models.GenericFailureResponse
 // @Failure      500       {object}  models.GenericFailureResponse
+// @Security     ApiKeyAuth
 // @Router       /utxos/paginated [get]
 func GetPaginatedUtxos(c *gin.Context) {
-	addrStr := c.Query("addresses")
-	if addrStr == "" {
-		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Message: "addresses query parameter is required"})
-		return
-	}
-
-	rawAddresses := strings.Split(addrStr, ",")
-	var addresses []string
-	for _, addr := range rawAddresses {
-		trimmed := strings.TrimSpace(addr)
-		if trimmed != "" {
-			address, err := script.NewAddressFromString(trimmed)
-			if err != nil {
-				c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Message: "Invalid wallet address: " + trimmed})
-				return
-			}
-			addresses = append(addresses, address.AddressString)
-		}
-	}
-
-	if len(addresses) == 0 {
-		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Message: "No valid addresses provided"})
+	addresses, ok := queryAddresses(c)
+	if !ok {
 		return
 	}
 
@@ -102,11 +73,11 @@ func GetPaginatedUtxos(c *gin.Context) {
 		var err error
 		page, err = strconv.Atoi(pageQuery)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Message: "page must be a valid integer"})
+			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeValidationFailed, Message: "page must be a valid integer"})
 			return
 		}
 		if page < 1 {
-			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Message: "page must be greater than 0"})
+			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeValidationFailed, Message: "page must be greater than 0"})
 			return
 		}
 	}
@@ -119,18 +90,18 @@ func GetPaginatedUtxos(c *gin.Context) {
 		var err error
 		size, err = strconv.Atoi(sizeQuery)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Message: "size must be a valid integer"})
+			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeValidationFailed, Message: "size must be a valid integer"})
 			return
 		}
 		if size < 1 {
-			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Message: "size must be greater than 0"})
+			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeValidationFailed, Message: "size must be greater than 0"})
 			return
 		}
 	}
 
-	txos, err := services.Instance.GetPaginatedUnspentTxos(c.Request.Context(), addresses, page, size)
+	txos, err := mneeClient(c).GetPaginatedUnspentTxos(c.Request.Context(), addresses, page, size)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Message: err.Error()})
+		respondError(c, err)
 		return
 	}
 
@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+
+	primitives "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/gin-gonic/gin"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/amount"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/models" // Import models
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/wallets"
+)
+
+type CreateWalletRequest struct {
+	Label string `json:"label,omitempty" example:"payouts"`
+	Wif   string `json:"wif,omitempty" example:"L1dRKo..."`
+}
+
+// CreateWallet godoc
+// @Summary      Create Wallet
+// @Description  Imports a WIF as a managed wallet, or generates a new key when no WIF is given. The key is stored encrypted with the master key; transfers reference the wallet by ID. Only an explicit export returns it.
+// @Tags         Wallets
+// @Accept       json
+// @Produce      json
+// @Param        request body CreateWalletRequest true "Wallet"
+// @Success      201     {object} models.WalletSuccessResponse
+// @Failure      422     {object} models.GenericFailureResponse
+// @Failure      400     {object} models.GenericFailureResponse
+// @Failure      401     {object} models.GenericFailureResponse
+// @Failure      403     {object} models.GenericFailureResponse
+// @Failure      409     {object} models.GenericFailureResponse
+// @Failure      500     {object} models.GenericFailureResponse
+// @Failure      503     {object} models.GenericFailureResponse
+// @Security     ApiKeyAuth
+// @Router       /wallets [post]
+func CreateWallet(c *gin.Context) {
+	if wallets.Instance == nil {
+		respondError(c, wallets.ErrDisabled)
+		return
+	}
+
+	var req CreateWalletRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, models.GenericFailureResponse{Success: false, Code: models.CodeInvalidRequestBody, Message: "Unprocessable Entity: " + err.Error()})
+		return
+	}
+
+	var wallet *wallets.Wallet
+	var err error
+	if req.Wif != "" {
+		if _, err := primitives.PrivateKeyFromWif(req.Wif); err != nil {
+			c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeInvalidWif, Message: "Invalid WIF"})
+			return
+		}
+		wallet, err = wallets.Instance.Import(req.Wif, req.Label, clientID(c))
+	} else {
+		wallet, err = wallets.Instance.Generate(req.Label, clientID(c))
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    walletSummary(*wallet),
+	})
+}
+
+// ListWallets godoc
+// @Summary      List Wallets
+// @Description  Lists the caller's managed wallets with their addresses and balances.
+// @Tags         Wallets
+// @Produce      json
+// @Success      200  {object}  models.ListWalletsSuccessResponse
+// @Failure      401  {object}  models.GenericFailureResponse
+// @Failure      403  {object}  models.GenericFailureResponse
+// @Failure      429  {object}  models.GenericFailureResponse
+// @Failure      502  {object}  models.GenericFailureResponse
+// @Failure      503  {object}  models.GenericFailureResponse
+// @Failure      504  {object}  models.GenericFailureResponse
+// @Security     ApiKeyAuth
+// @Router       /wallets [get]
+func ListWallets(c *gin.Context) {
+	if wallets.Instance == nil {
+		respondError(c, wallets.ErrDisabled)
+		return
+	}
+
+	summaries, err := walletsWithBalances(c, wallets.Instance.List(clientID(c)))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    summaries,
+	})
+}
+
+// GetWallet godoc
+// @Summary      Get Wallet
+// @Description  Returns one of the caller's managed wallets with its address and balance.
+// @Tags         Wallets
+// @Produce      json
+// @Param        id   path      string  true  "Wallet ID"
+// @Success      200  {object}  models.WalletSuccessResponse
+// @Failure      401  {object}  models.GenericFailureResponse
+// @Failure      403  {object}  models.GenericFailureResponse
+// @Failure      404  {object}  models.GenericFailureResponse
+// @Failure      429  {object}  models.GenericFailureResponse
+// @Failure      502  {object}  models.GenericFailureResponse
+// @Failure      503  {object}  models.GenericFailureResponse
+// @Failure      504  {object}  models.GenericFailureResponse
+// @Security     ApiKeyAuth
+// @Router       /wallets/{id} [get]
+func GetWallet(c *gin.Context) {
+	if wallets.Instance == nil {
+		respondError(c, wallets.ErrDisabled)
+		return
+	}
+
+	wallet, err := wallets.Instance.Get(c.Param("id"), clientID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	summaries, err := walletsWithBalances(c, []wallets.Wallet{*wallet})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    summaries[0],
+	})
+}
+
+// ExportWallet godoc
+// @Summary      Export Wallet
+// @Description  Returns the WIF of one of the caller's managed wallets, as a backup before deleting it or to move the key elsewhere. The export time is recorded on the wallet. Needs the export:wallets scope, and answers 403 WALLET_EXPORT_DISABLED unless WALLET_EXPORT_ENABLED is set.
+// @Tags         Wallets
+// @Produce      json
+// @Param        id   path      string  true  "Wallet ID"
+// @Success      200  {object}  models.WalletExportSuccessResponse
+// @Failure      401  {object}  models.GenericFailureResponse
+// @Failure      403  {object}  models.GenericFailureResponse
+// @Failure      404  {object}  models.GenericFailureResponse
+// @Failure      500  {object}  models.GenericFailureResponse
+// @Failure      503  {object}  models.GenericFailureResponse
+// @Security     ApiKeyAuth
+// @Router       /wallets/{id}/export [post]
+func ExportWallet(c *gin.Context) {
+	if wallets.Instance == nil {
+		respondError(c, wallets.ErrDisabled)
+		return
+	}
+
+	wallet, wif, err := wallets.Instance.Export(c.Param("id"), clientID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": models.WalletExport{
+			ID:         wallet.ID,
+			Address:    wallet.Address,
+			Wif:        wif,
+			ExportedAt: *wallet.ExportedAt,
+		},
+	})
+}
+
+// DeleteWallet godoc
+// @Summary      Delete Wallet
+// @Description  Deletes one of the caller's managed wallets and its encrypted key. Funds on its address are not moved, so a wallet still holding MNEE, or a generated wallet whose key was never exported, is only deleted with force=true.
+// @Tags         Wallets
+// @Produce      json
+// @Param        id     path      string  true   "Wallet ID"
+// @Param        force  query     bool    false  "Delete even if the address holds MNEE or the generated key was never exported"
+// @Success      200  {object}  models.SuccessResponse
+// @Failure      400  {object}  models.GenericFailureResponse
+// @Failure      401  {object}  models.GenericFailureResponse
+// @Failure      403  {object}  models.GenericFailureResponse
+// @Failure      404  {object}  models.GenericFailureResponse
+// @Failure      409  {object}  models.GenericFailureResponse
+// @Failure      500  {object}  models.GenericFailureResponse
+// @Failure      502  {object}  models.GenericFailureResponse
+// @Failure      503  {object}  models.GenericFailureResponse
+// @Failure      504  {object}  models.GenericFailureResponse
+// @Security     ApiKeyAuth
+// @Router       /wallets/{id} [delete]
+func DeleteWallet(c *gin.Context) {
+	if wallets.Instance == nil {
+		respondError(c, wallets.ErrDisabled)
+		return
+	}
+
+	force, ok := boolQuery(c, "force")
+	if !ok {
+		return
+	}
+
+	wallet, err := wallets.Instance.Get(c.Param("id"), clientID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if !force {
+		if wallet.Generated && wallet.ExportedAt == nil {
+			respondError(c, wallets.ErrNotExported)
+			return
+		}
+
+		// Cached balances could hide a deposit made moments ago.
+		balances, err := mneeClient(c).GetBalances(services.WithoutCache(c.Request.Context()), []string{wallet.Address})
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		for _, balance := range balances {
+			if math.Round(balance.Amt) > 0 {
+				respondError(c, wallets.ErrNotEmpty)
+				return
+			}
+		}
+	}
+
+	if err := wallets.Instance.Delete(wallet.ID, clientID(c)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// walletsWithBalances looks up the balances of all wallets in one call.
+func walletsWithBalances(c *gin.Context, list []wallets.Wallet) ([]models.WalletSummary, error) {
+	summaries := make([]models.WalletSummary, 0, len(list))
+	if len(list) == 0 {
+		return summaries, nil
+	}
+
+	config, err := mneeClient(c).GetConfig(c.Request.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := make([]string, 0, len(list))
+	for _, wallet := range list {
+		addresses = append(addresses, wallet.Address)
+	}
+
+	balances, err := mneeClient(c).GetBalances(c.Request.Context(), addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	atomicBalances := make(map[string]uint64, len(balances))
+	for _, balance := range balances {
+		if balance.Address != nil {
+			atomicBalances[*balance.Address] += uint64(math.Round(balance.Amt))
+		}
+	}
+
+	for _, wallet := range list {
+		summary := walletSummary(wallet)
+		atomicBalance := atomicBalances[wallet.Address]
+		balance := amount.Format(atomicBalance, config.Decimals)
+		summary.AtomicBalance = &atomicBalance
+		summary.Balance = &balance
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+func walletSummary(wallet wallets.Wallet) models.WalletSummary {
+	return models.WalletSummary{
+		ID:         wallet.ID,
+		Label:      wallet.Label,
+		Address:    wallet.Address,
+		CreatedAt:  wallet.CreatedAt,
+		Generated:  wallet.Generated,
+		ExportedAt: wallet.ExportedAt,
+	}
+}
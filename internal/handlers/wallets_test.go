@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/models"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/wallets"
+)
+
+func TestDeleteWallet(t *testing.T) {
+	tests := []struct {
+		name      string
+		generated bool
+		exported  bool
+		funded    bool
+		query     string
+		status    int
+		code      string
+	}{
+		{name: "empty import", status: http.StatusOK},
+		{name: "funded", funded: true, status: http.StatusConflict, code: models.CodeWalletNotEmpty},
+		{name: "funded with force", funded: true, query: "?force=true", status: http.StatusOK},
+		{name: "generated, not exported", generated: true, status: http.StatusConflict, code: models.CodeWalletNotExported},
+		{name: "generated and exported", generated: true, exported: true, status: http.StatusOK},
+		{name: "generated with force", generated: true, query: "?force=true", status: http.StatusOK},
+		{name: "invalid force", query: "?force=maybe", status: http.StatusBadRequest, code: models.CodeValidationFailed},
+	}
+
+	previous := wallets.Instance
+	t.Cleanup(func() { wallets.Instance = previous })
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := wallets.NewStore(strings.Repeat("ab", 32), "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			store.EnableExport()
+			wallets.Instance = store
+
+			var wallet *wallets.Wallet
+			if tt.generated {
+				wallet, err = store.Generate("", "")
+			} else {
+				wif, _ := newKey(t)
+				wallet, err = store.Import(wif, "", "")
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.exported {
+				if _, _, err := store.Export(wallet.ID, ""); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			client := services.NewFakeClient()
+			if tt.funded {
+				if err := client.Fund(wallet.Address, 1000); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			status, response := serve(t, client, http.MethodDelete, "/wallets/:id", "/wallets/"+wallet.ID+tt.query, DeleteWallet, nil)
+			if status != tt.status || response.Code != tt.code {
+				t.Fatalf("got %d %q (%s), want %d %q", status, response.Code, response.Message, tt.status, tt.code)
+			}
+
+			_, err = store.Get(wallet.ID, "")
+			if deleted := err != nil; deleted != (tt.status == http.StatusOK) {
+				t.Fatalf("wallet deleted: %v, want %v", deleted, tt.status == http.StatusOK)
+			}
+		})
+	}
+}
+
+func TestExportWallet(t *testing.T) {
+	tests := []struct {
+		name     string
+		enabled  bool
+		clientID string
+		status   int
+		code     string
+	}{
+		{name: "disabled", clientID: "alice", status: http.StatusForbidden, code: models.CodeWalletExportDisabled},
+		{name: "owner", enabled: true, clientID: "alice", status: http.StatusOK},
+		{name: "another client", enabled: true, clientID: "bob", status: http.StatusNotFound, code: models.CodeWalletNotFound},
+	}
+
+	previous := wallets.Instance
+	t.Cleanup(func() { wallets.Instance = previous })
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := wallets.NewStore(strings.Repeat("ab", 32), "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.enabled {
+				store.EnableExport()
+			}
+			wallets.Instance = store
+
+			wif, address := newKey(t)
+			wallet, err := store.Import(wif, "", "alice")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			status, response := serveAs(t, services.NewFakeClient(), tt.clientID, http.MethodPost, "/wallets/:id/export", "/wallets/"+wallet.ID+"/export", ExportWallet, nil)
+			if status != tt.status || response.Code != tt.code {
+				t.Fatalf("got %d %q (%s), want %d %q", status, response.Code, response.Message, tt.status, tt.code)
+			}
+			if strings.Contains(string(response.Data)+response.Message, wif) != (tt.status == http.StatusOK) {
+				t.Fatalf("response %s %s, want the WIF only on success", response.Message, response.Data)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var export models.WalletExport
+			if err := json.Unmarshal(response.Data, &export); err != nil {
+				t.Fatal(err)
+			}
+			if export.Wif != wif || export.Address != address || export.ExportedAt.IsZero() {
+				t.Fatalf("got export %+v, want the wallet's key", export)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/models" // Import models
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/webhooks"
+)
+
+// maxCallbackBytes caps the body of a cosigner callback, which is a single
+// ticket.
+const maxCallbackBytes = 64 << 10
+
+// MneeCallback godoc
+// @Summary      Cosigner Callback
+// @Description  Receives ticket callbacks from the cosigner when the webhook relay is enabled. The callback must carry the relay's callback secret. The ticket is then fetched from the cosigner, with the API key of the tenant that created it, and its status is relayed to subscribers only if it changed.
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Success      202  {object}  models.SuccessResponse
+// @Failure      401  {object}  models.GenericFailureResponse
+// @Failure      413  {object}  models.GenericFailureResponse
+// @Failure      422  {object}  models.GenericFailureResponse
+// @Failure      502  {object}  models.GenericFailureResponse
+// @Failure      503  {object}  models.GenericFailureResponse
+// @Failure      504  {object}  models.GenericFailureResponse
+// @Router       /webhooks/mnee [post]
+func MneeCallback(c *gin.Context) {
+	// The endpoint is open to anyone, so nothing unsigned is read, and a
+	// signed body is read only up to a ticket's size.
+	if c.GetHeader(webhooks.CallbackSignatureHeader) == "" {
+		c.JSON(http.StatusUnauthorized, models.GenericFailureResponse{Success: false, Code: models.CodeUnauthorized, Message: "Missing callback signature"})
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxCallbackBytes))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, models.GenericFailureResponse{Success: false, Code: models.CodeRequestTooLarge, Message: "Callback body is too large"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, models.GenericFailureResponse{Success: false, Code: models.CodeInvalidRequestBody, Message: "Failed to read request body"})
+		return
+	}
+
+	if !webhooks.Instance.Verify(c.Request.Header, body) {
+		c.JSON(http.StatusUnauthorized, models.GenericFailureResponse{Success: false, Code: models.CodeUnauthorized, Message: "Invalid callback signature"})
+		return
+	}
+
+	var callback mnee.Ticket
+	if err := json.Unmarshal(body, &callback); err != nil || callback.ID == nil || *callback.ID == "" {
+		c.JSON(http.StatusUnprocessableEntity, models.GenericFailureResponse{Success: false, Code: models.CodeInvalidRequestBody, Message: "Callback body must be a ticket with an id"})
+		return
+	}
+
+	// The callback only says that something changed; the cosigner is the
+	// source of truth for what.
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	client, err := services.Tenants.Client(webhooks.Instance.Owner(*callback.ID))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	ticket, err := client.PollTicket(ctx, *callback.ID, 500*time.Millisecond)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	webhooks.Instance.Publish(ticket)
+
+	c.JSON(http.StatusAccepted, gin.H{"success": true})
+}
+
+// checkCallbackURL rejects a callbackUrl the webhook relay refuses to call.
+// On failure it writes the error response and returns false.
+func checkCallbackURL(c *gin.Context, callbackURL *string) bool {
+	if callbackURL == nil || *callbackURL == "" {
+		return true
+	}
+
+	if err := webhooks.Instance.CheckCallbackURL(c.Request.Context(), *callbackURL); err != nil {
+		c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeValidationFailed, Message: err.Error()})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/models"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/webhooks"
+)
+
+func TestMneeCallbackRejects(t *testing.T) {
+	previous := webhooks.Instance
+	t.Cleanup(func() { webhooks.Instance = previous })
+	webhooks.Instance = webhooks.NewRelay(webhooks.Config{PublicURL: "https://relay.example.com", CallbackSecret: "relay-secret"})
+
+	tests := []struct {
+		name      string
+		signature string
+		body      string
+		status    int
+		code      string
+	}{
+		{name: "no signature", body: `{"id":"t1"}`, status: http.StatusUnauthorized, code: models.CodeUnauthorized},
+		{name: "wrong signature", signature: strings.Repeat("00", 32), body: `{"id":"t1"}`, status: http.StatusUnauthorized, code: models.CodeUnauthorized},
+		{name: "too large", signature: strings.Repeat("00", 32), body: `{"id":"` + strings.Repeat("a", maxCallbackBytes) + `"}`, status: http.StatusRequestEntityTooLarge, code: models.CodeRequestTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST("/callback", MneeCallback)
+
+			request := httptest.NewRequest(http.MethodPost, "/callback", bytes.NewReader([]byte(tt.body)))
+			if tt.signature != "" {
+				request.Header.Set(webhooks.CallbackSignatureHeader, tt.signature)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.status || !strings.Contains(recorder.Body.String(), tt.code) {
+				t.Fatalf("got %d %s, want %d %s", recorder.Code, recorder.Body.String(), tt.status, tt.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package hdwallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	bip32 "github.com/bsv-blockchain/go-sdk/compat/bip32"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
+)
+
+const (
+	ChainReceive uint32 = 0
+	ChainChange  uint32 = 1
+
+	// AccountPath is derived from a master xprv: BIP44 account 0 for coin
+	// type 236 (BSV).
+	AccountPath = "44'/236'/0'"
+
+	// historyProbeLimit bounds the history fetched per batch of addresses
+	// while scanning. When a batch has more entries than this, addresses not
+	// seen in them are checked one by one.
+	historyProbeLimit = 100
+)
+
+var ErrInvalidExtendedKey = errors.New("invalid extended key")
+
+// Account is a BIP44 account registered by its extended public key. Private
+// keys are never kept: an xprv is reduced to its account xpub on
+// registration. ReceiveNext and ChangeNext are one past the highest index
+// found in use on each chain by the last scan.
+type Account struct {
+	ID          string     `json:"id"`
+	Label       string     `json:"label,omitempty"`
+	Xpub        string     `json:"xpub"`
+	GapLimit    int        `json:"gapLimit"`
+	ClientID    string     `json:"clientId,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	ReceiveNext uint32     `json:"receiveNext"`
+	ChangeNext  uint32     `json:"changeNext"`
+	ScannedAt   *time.Time `json:"scannedAt,omitempty"`
+}
+
+// Address is an address derived from an account.
+type Address struct {
+	Address string `json:"address"`
+	Chain   uint32 `json:"chain"`
+	Index   uint32 `json:"index"`
+}
+
+// AccountXpub returns the account-level xpub for extendedKey. A master xprv
+// (depth 0) is first derived along AccountPath; any other key is taken to be
+// the account node already.
+func AccountXpub(extendedKey string) (string, error) {
+	key, err := bip32.NewKeyFromString(extendedKey)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidExtendedKey, err)
+	}
+
+	if key.IsPrivate() && key.Depth() == 0 {
+		if key, err = key.DeriveChildFromPath(AccountPath); err != nil {
+			return "", fmt.Errorf("%w: %v", ErrInvalidExtendedKey, err)
+		}
+	}
+
+	public, err := key.Neuter()
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidExtendedKey, err)
+	}
+	return public.String(), nil
+}
+
+// Derive returns the addresses at indexes [from, to) on chain.
+func (a *Account) Derive(chain uint32, from uint32, to uint32) ([]Address, error) {
+	key, err := bip32.NewKeyFromString(a.Xpub)
+	if err != nil {
+		return nil, err
+	}
+	chainKey, err := key.Child(chain)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := make([]Address, 0, to-from)
+	for index := from; index < to; index++ {
+		child, err := chainKey.Child(index)
+		if err != nil {
+			return nil, err
+		}
+		address, err := bip32.GetAddressFromHDKey(child)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, Address{Address: address.AddressString, Chain: chain, Index: index})
+	}
+	return addresses, nil
+}
+
+// Addresses returns every address up to the last one in use on both chains,
+// plus the next receive address so payments to it show up before a rescan.
+func (a *Account) Addresses() ([]Address, error) {
+	receive, err := a.Derive(ChainReceive, 0, a.ReceiveNext+1)
+	if err != nil {
+		return nil, err
+	}
+	change, err := a.Derive(ChainChange, 0, a.ChangeNext)
+	if err != nil {
+		return nil, err
+	}
+	return append(receive, change...), nil
+}
+
+// NextAddress returns the first unused address on chain.
+func (a *Account) NextAddress(chain uint32) (*Address, error) {
+	next := a.ReceiveNext
+	if chain == ChainChange {
+		next = a.ChangeNext
+	}
+
+	addresses, err := a.Derive(chain, next, next+1)
+	if err != nil {
+		return nil, err
+	}
+	return &addresses[0], nil
+}
+
+// Scan walks both chains from the last known used index until gapLimit
+// consecutive addresses have no balance, no UTXOs and no history, and
+// returns a copy of the account with ReceiveNext and ChangeNext updated.
+// It reads past the cache, so a payment that just arrived is seen.
+func Scan(ctx context.Context, client services.MneeClient, account Account) (*Account, error) {
+	ctx = services.WithoutCache(ctx)
+
+	var err error
+	if account.ReceiveNext, err = scanChain(ctx, client, &account, ChainReceive, account.ReceiveNext); err != nil {
+		return nil, err
+	}
+	if account.ChangeNext, err = scanChain(ctx, client, &account, ChainChange, account.ChangeNext); err != nil {
+		return nil, err
+	}
+
+	scannedAt := time.Now().UTC()
+	account.ScannedAt = &scannedAt
+	return &account, nil
+}
+
+func scanChain(ctx context.Context, client services.MneeClient, account *Account, chain uint32, next uint32) (uint32, error) {
+	gap := 0
+	for index := next; gap < account.GapLimit; {
+		if uint64(index)+uint64(account.GapLimit) > math.MaxInt32 {
+			return 0, fmt.Errorf("scan of chain %d passed the last non-hardened index", chain)
+		}
+
+		batch, err := account.Derive(chain, index, index+uint32(account.GapLimit))
+		if err != nil {
+			return 0, err
+		}
+
+		used, err := usedAddresses(ctx, client, batch)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, address := range batch {
+			if used[address.Address] {
+				next = address.Index + 1
+				gap = 0
+			} else if gap++; gap == account.GapLimit {
+				break
+			}
+		}
+		index += uint32(len(batch))
+	}
+	return next, nil
+}
+
+// usedAddresses reports which of batch have ever been used: they hold a
+// balance or UTXOs, or appear in a transaction.
+func usedAddresses(ctx context.Context, client services.MneeClient, batch []Address) (map[string]bool, error) {
+	addresses := make([]string, 0, len(batch))
+	for _, address := range batch {
+		addresses = append(addresses, address.Address)
+	}
+
+	used := make(map[string]bool)
+
+	balances, err := client.GetBalances(ctx, addresses)
+	if err != nil {
+		return nil, err
+	}
+	for _, balance := range balances {
+		if balance.Address != nil && balance.Amt > 0 {
+			used[*balance.Address] = true
+		}
+	}
+
+	txos, err := client.GetUnspentTxos(ctx, addresses)
+	if err != nil {
+		return nil, err
+	}
+	for _, txo := range txos {
+		for _, owner := range txo.Owners {
+			used[owner] = true
+		}
+	}
+
+	history, err := client.GetSpecificTransactionHistory(ctx, addresses, 0, historyProbeLimit)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range history {
+		for _, address := range entry.Senders {
+			used[address] = true
+		}
+		for _, address := range entry.Receivers {
+			used[address] = true
+		}
+	}
+
+	if len(history) < historyProbeLimit {
+		return used, nil
+	}
+
+	for _, address := range addresses {
+		if used[address] {
+			continue
+		}
+		history, err := client.GetSpecificTransactionHistory(ctx, []string{address}, 0, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(history) > 0 {
+			used[address] = true
+		}
+	}
+	return used, nil
+}
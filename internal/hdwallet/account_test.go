@@ -0,0 +1,120 @@
+package hdwallet
+
+import (
+	"errors"
+	"testing"
+
+	bip32 "github.com/bsv-blockchain/go-sdk/compat/bip32"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
+)
+
+func testAccount(t *testing.T, gapLimit int) Account {
+	t.Helper()
+
+	master, err := bip32.GenerateHDKey(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xpub, err := AccountXpub(master.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Account{Xpub: xpub, GapLimit: gapLimit}
+}
+
+func TestAccountXpub(t *testing.T) {
+	master, err := bip32.GenerateHDKey(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	account, err := master.DeriveChildFromPath(AccountPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	accountXpub, err := account.Neuter()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		key  string
+		want string
+		err  error
+	}{
+		{name: "master xprv", key: master.String(), want: accountXpub.String()},
+		{name: "account xprv", key: account.String(), want: accountXpub.String()},
+		{name: "account xpub", key: accountXpub.String(), want: accountXpub.String()},
+		{name: "garbage", key: "xpub-nope", err: ErrInvalidExtendedKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AccountXpub(tt.key)
+			if !errors.Is(err, tt.err) || got != tt.want {
+				t.Fatalf("got %q, %v; want %q, %v", got, err, tt.want, tt.err)
+			}
+		})
+	}
+}
+
+func TestScan(t *testing.T) {
+	type use struct {
+		chain uint32
+		index uint32
+	}
+	tests := []struct {
+		name        string
+		receiveNext uint32
+		used        []use
+		wantReceive uint32
+		wantChange  uint32
+	}{
+		{name: "unused"},
+		{name: "first receive", used: []use{{ChainReceive, 0}}, wantReceive: 1},
+		{name: "within the gap", used: []use{{ChainReceive, 2}, {ChainReceive, 6}}, wantReceive: 7},
+		{name: "past the gap", used: []use{{ChainReceive, 2}, {ChainReceive, 8}}, wantReceive: 3},
+		{name: "across batches", used: []use{{ChainReceive, 4}, {ChainReceive, 9}, {ChainReceive, 13}}, wantReceive: 14},
+		{name: "change", used: []use{{ChainChange, 1}}, wantChange: 2},
+		{name: "resumes", receiveNext: 10, used: []use{{ChainReceive, 12}}, wantReceive: 13},
+		{name: "keeps earlier progress", receiveNext: 10, wantReceive: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account := testAccount(t, 5)
+			account.ReceiveNext = tt.receiveNext
+
+			client := services.NewFakeClient()
+			for _, u := range tt.used {
+				addresses, err := account.Derive(u.chain, u.index, u.index+1)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := client.Fund(addresses[0].Address, 1000); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			scanned, err := Scan(t.Context(), client, account)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if scanned.ReceiveNext != tt.wantReceive || scanned.ChangeNext != tt.wantChange {
+				t.Fatalf("got receive %d, change %d; want %d, %d", scanned.ReceiveNext, scanned.ChangeNext, tt.wantReceive, tt.wantChange)
+			}
+			if scanned.ScannedAt == nil {
+				t.Fatal("ScannedAt is not set")
+			}
+		})
+	}
+}
+
+func TestScanFails(t *testing.T) {
+	client := services.NewFakeClient()
+	client.FailWith("GetUnspentTxos", services.ErrCircuitOpen)
+
+	if _, err := Scan(t.Context(), client, testAccount(t, 5)); !errors.Is(err, services.ErrCircuitOpen) {
+		t.Fatalf("got %v, want %v", err, services.ErrCircuitOpen)
+	}
+}
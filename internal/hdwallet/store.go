@@ -0,0 +1,115 @@
+package hdwallet
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/config"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/jsonstore"
+)
+
+var (
+	ErrNotFound      = errors.New("account not found")
+	ErrAlreadyExists = errors.New("an account for this key already exists")
+)
+
+func (a Account) RecordID() string           { return a.ID }
+func (a Account) RecordOwner() string        { return a.ClientID }
+func (a Account) RecordKey() string          { return a.Xpub }
+func (a Account) RecordCreatedAt() time.Time { return a.CreatedAt }
+
+// Store keeps accounts with the gap limit new accounts default to.
+type Store struct {
+	gapLimit int
+	accounts *jsonstore.Store[Account]
+}
+
+// Instance holds accounts in memory only until InitAccounts runs; opening a
+// store without a path cannot fail.
+var Instance, _ = NewStore("", 0)
+
+func InitAccounts(cfg *config.Config) {
+	store, err := NewStore(cfg.HDAccountStorePath, cfg.HDGapLimit)
+	if err != nil {
+		log.Fatalf("Failed to load HD accounts: %v", err)
+	}
+
+	Instance = store
+	if n := store.accounts.Len(); n > 0 {
+		log.Printf("Loaded %d HD accounts", n)
+	}
+}
+
+func NewStore(path string, gapLimit int) (*Store, error) {
+	if gapLimit <= 0 {
+		gapLimit = 20
+	}
+
+	accounts, err := jsonstore.Open[Account](path, ErrNotFound, ErrAlreadyExists)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{gapLimit: gapLimit, accounts: accounts}, nil
+}
+
+// New returns an unsaved account for extendedKey owned by clientID. A
+// gapLimit of 0 uses the store's default.
+func (s *Store) New(extendedKey string, label string, gapLimit int, clientID string) (*Account, error) {
+	xpub, err := AccountXpub(extendedKey)
+	if err != nil {
+		return nil, err
+	}
+	if gapLimit <= 0 {
+		gapLimit = s.gapLimit
+	}
+
+	if s.accounts.Has(clientID, xpub) {
+		return nil, ErrAlreadyExists
+	}
+
+	return &Account{
+		ID:        randomID(),
+		Label:     label,
+		Xpub:      xpub,
+		GapLimit:  gapLimit,
+		ClientID:  clientID,
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+// Add stores a new account.
+func (s *Store) Add(account Account) error {
+	return s.accounts.Add(account)
+}
+
+// Update replaces the scan results of an account that still exists.
+func (s *Store) Update(account Account) error {
+	return s.accounts.Update(account)
+}
+
+// List returns the accounts owned by clientID, oldest first.
+func (s *Store) List(clientID string) []Account {
+	return s.accounts.List(clientID)
+}
+
+// Get returns account id if clientID owns it.
+func (s *Store) Get(id string, clientID string) (*Account, error) {
+	account, err := s.accounts.Get(id, clientID)
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (s *Store) Delete(id string, clientID string) error {
+	return s.accounts.Delete(id, clientID)
+}
+
+func randomID() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return "a_" + hex.EncodeToString(b)
+}
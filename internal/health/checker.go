@@ -0,0 +1,99 @@
+// Package health keeps track of whether the cosigner answers, for the
+// readiness probe.
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/logging"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
+)
+
+const checkTimeout = 5 * time.Second
+
+// Status is the outcome of the latest checks of a dependency.
+type Status struct {
+	OK            bool       `json:"ok"`
+	LatencyMs     int64      `json:"latencyMs"`
+	CheckedAt     *time.Time `json:"checkedAt,omitempty"`
+	LastSuccessAt *time.Time `json:"lastSuccessAt,omitempty"`
+	LastError     string     `json:"lastError,omitempty"`
+}
+
+// Checker calls GetConfig on the cosigner in the background, so probes
+// answer from the cached result instead of waiting on the cosigner. The
+// cosigner counts as up while the last successful call is at most maxAge old.
+type Checker struct {
+	interval time.Duration
+	maxAge   time.Duration
+	check    func(ctx context.Context) error
+
+	mutex  sync.RWMutex
+	status Status
+}
+
+var Instance = NewChecker(15*time.Second, time.Minute)
+
+func NewChecker(interval time.Duration, maxAge time.Duration) *Checker {
+	return &Checker{interval: interval, maxAge: maxAge, check: checkCosigner}
+}
+
+// Run checks the cosigner right away and then every interval until ctx is
+// done.
+func (c *Checker) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		c.refresh(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *Checker) refresh(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	started := time.Now()
+	err := c.check(ctx)
+	checkedAt := time.Now().UTC()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.status.LatencyMs = time.Since(started).Milliseconds()
+	c.status.CheckedAt = &checkedAt
+	if err != nil {
+		// Readyz is unauthenticated, so the API key in SDK errors must not leak.
+		c.status.LastError = logging.Redact(err.Error())
+	} else {
+		c.status.LastError = ""
+		c.status.LastSuccessAt = &checkedAt
+	}
+}
+
+// Status returns the cached result of the latest checks.
+func (c *Checker) Status() Status {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	status := c.status
+	status.OK = status.LastSuccessAt != nil && time.Since(*status.LastSuccessAt) <= c.maxAge
+	return status
+}
+
+func checkCosigner(ctx context.Context) error {
+	if services.Instance == nil {
+		return errors.New("MNEE SDK is not initialized")
+	}
+	_, err := services.Instance.GetConfig(services.WithoutCache(ctx))
+	return err
+}
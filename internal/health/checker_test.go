@@ -0,0 +1,124 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
+)
+
+func TestChecker(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []error
+		age     time.Duration
+		ok      bool
+		lastErr string
+	}{
+		{name: "never checked"},
+		{name: "answered", results: []error{nil}, ok: true},
+		{name: "never answered", results: []error{errors.New("connection refused")}, lastErr: "connection refused"},
+		{name: "failing after an answer", results: []error{nil, errors.New("connection refused")}, ok: true, lastErr: "connection refused"},
+		{name: "recovered", results: []error{errors.New("connection refused"), nil}, ok: true},
+		{name: "answer too old", results: []error{nil}, age: 2 * time.Minute},
+		{
+			name:    "API key redacted",
+			results: []error{errors.New(`Get "https://api.mnee.net/v1/config?auth_token=sdk-key-123": timeout`)},
+			lastErr: "auth_token=[REDACTED]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(time.Hour, time.Minute)
+			calls := 0
+			checker.check = func(context.Context) error {
+				err := tt.results[calls]
+				calls++
+				return err
+			}
+			for range tt.results {
+				checker.refresh(t.Context())
+			}
+			if tt.age > 0 {
+				old := time.Now().Add(-tt.age)
+				checker.status.LastSuccessAt = &old
+			}
+
+			status := checker.Status()
+			if status.OK != tt.ok {
+				t.Fatalf("OK = %v, want %v (%+v)", status.OK, tt.ok, status)
+			}
+			if !strings.Contains(status.LastError, tt.lastErr) || (tt.lastErr == "" && status.LastError != "") {
+				t.Fatalf("LastError = %q, want %q", status.LastError, tt.lastErr)
+			}
+			if strings.Contains(status.LastError, "sdk-key-123") {
+				t.Fatalf("LastError %q leaks the API key", status.LastError)
+			}
+			if len(tt.results) > 0 && status.CheckedAt == nil {
+				t.Fatal("CheckedAt not set")
+			}
+		})
+	}
+}
+
+func TestCheckerRun(t *testing.T) {
+	checker := NewChecker(time.Millisecond, time.Minute)
+	checks := make(chan struct{}, 100)
+	checker.check = func(ctx context.Context) error {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("check runs without a deadline")
+		}
+		checks <- struct{}{}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan struct{})
+	go func() {
+		checker.Run(ctx)
+		close(done)
+	}()
+
+	for range 3 {
+		select {
+		case <-checks:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Run does not check periodically")
+		}
+	}
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run does not stop when its context ends")
+	}
+	if !checker.Status().OK {
+		t.Fatal("not OK after successful checks")
+	}
+}
+
+func TestCheckCosigner(t *testing.T) {
+	previous := services.Instance
+	t.Cleanup(func() { services.Instance = previous })
+
+	services.Instance = nil
+	if err := checkCosigner(t.Context()); err == nil {
+		t.Fatal("checkCosigner without an SDK client succeeded")
+	}
+
+	client := services.NewFakeClient()
+	services.Instance = client
+	if err := checkCosigner(t.Context()); err != nil {
+		t.Fatalf("checkCosigner: %v", err)
+	}
+
+	client.FailWith("GetConfig", errors.New("status received from mnee-cosigner -> 503"))
+	if err := checkCosigner(t.Context()); err == nil {
+		t.Fatal("checkCosigner succeeded while the cosigner fails")
+	}
+}
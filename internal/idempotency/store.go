@@ -0,0 +1,239 @@
+package idempotency
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/atomicfile"
+)
+
+var (
+	// ErrInProgress is returned by Reserve while the first request with a key
+	// has not completed.
+	ErrInProgress = errors.New("request with this idempotency key is still in progress")
+	// ErrMismatch is returned by Reserve when a key is reused with a
+	// different request.
+	ErrMismatch = errors.New("idempotency key was used with a different request")
+)
+
+// Record is what is kept for an idempotency key: the hash of the request that
+// first used it and, once it completed, the response to replay.
+type Record struct {
+	RequestHash string              `json:"requestHash"`
+	Completed   bool                `json:"completed"`
+	Status      int                 `json:"status,omitempty"`
+	Header      map[string][]string `json:"header,omitempty"`
+	Body        []byte              `json:"body,omitempty"`
+	TicketID    string              `json:"ticketId,omitempty"`
+	Txid        string              `json:"txid,omitempty"`
+	CreatedAt   time.Time           `json:"createdAt"`
+}
+
+// Store keeps idempotency records. Reserve claims a key for a request hash
+// and returns the completed record when there is one to replay.
+type Store interface {
+	Reserve(key, requestHash string) (*Record, error)
+	Complete(key string, record Record) error
+	Release(key string) error
+}
+
+// MemoryStore is a Store held in memory. With a path, completed records are
+// appended to that file and reloaded on start, so replays survive restarts.
+// Records expire ttl after they were created; expired records are swept at
+// most once a minute, and the file is compacted when most of its lines are
+// stale.
+type MemoryStore struct {
+	mutex     sync.Mutex
+	records   map[string]Record
+	ttl       time.Duration
+	path      string
+	file      *os.File
+	lines     int
+	lastSweep time.Time
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+const (
+	sweepInterval = time.Minute
+
+	// minCompactLines keeps small files from being rewritten over and over.
+	minCompactLines = 1000
+)
+
+// entry is one line of the store's file.
+type entry struct {
+	Key    string `json:"key"`
+	Record Record `json:"record"`
+}
+
+func NewMemoryStore(ttl time.Duration, path string) (*MemoryStore, error) {
+	s := &MemoryStore{records: make(map[string]Record), ttl: ttl, path: path, lastSweep: time.Now()}
+	if path == "" {
+		return s, nil
+	}
+
+	file, err := os.Open(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		decoder := json.NewDecoder(file)
+		for {
+			var e entry
+			err := decoder.Decode(&e)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				file.Close()
+				return nil, fmt.Errorf("reading %s: %w", path, err)
+			}
+			s.records[e.Key] = e.Record
+		}
+		file.Close()
+	}
+
+	s.expire()
+	if err := s.compact(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *MemoryStore) Reserve(key, requestHash string) (*Record, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if time.Since(s.lastSweep) >= sweepInterval {
+		s.lastSweep = time.Now()
+		s.expire()
+		if s.lines >= minCompactLines && s.lines > 2*len(s.records) {
+			// The old file stays in use, so a failure only costs space.
+			if err := s.compact(); err != nil {
+				slog.Error("idempotency: compacting store", "path", s.path, "error", err)
+			}
+		}
+	}
+
+	if record, ok := s.records[key]; ok && !s.expired(record) {
+		switch {
+		case record.RequestHash != requestHash:
+			return nil, ErrMismatch
+		case !record.Completed:
+			return nil, ErrInProgress
+		default:
+			return &record, nil
+		}
+	}
+
+	s.records[key] = Record{RequestHash: requestHash, CreatedAt: time.Now()}
+	return nil, nil
+}
+
+func (s *MemoryStore) Complete(key string, record Record) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if reserved, ok := s.records[key]; ok {
+		record.CreatedAt = reserved.CreatedAt
+	}
+	record.Completed = true
+	s.records[key] = record
+
+	return s.append(entry{Key: key, Record: record})
+}
+
+func (s *MemoryStore) Release(key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.records, key)
+	return nil
+}
+
+// Close closes the store's file. The store must not be used afterwards.
+func (s *MemoryStore) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+func (s *MemoryStore) expired(record Record) bool {
+	return s.ttl > 0 && time.Since(record.CreatedAt) > s.ttl
+}
+
+func (s *MemoryStore) expire() {
+	for key, record := range s.records {
+		if s.expired(record) {
+			delete(s.records, key)
+		}
+	}
+}
+
+// append adds e to the end of the store's file.
+func (s *MemoryStore) append(e entry) error {
+	if s.file == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if _, err := s.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	s.lines++
+	return nil
+}
+
+// compact replaces the store's file with one line per completed record, and
+// reopens it for appending.
+func (s *MemoryStore) compact() error {
+	if s.path == "" {
+		return nil
+	}
+
+	var data []byte
+	lines := 0
+	for key, record := range s.records {
+		if !record.Completed {
+			continue
+		}
+		line, err := json.Marshal(entry{Key: key, Record: record})
+		if err != nil {
+			return err
+		}
+		data = append(append(data, line...), '\n')
+		lines++
+	}
+
+	if err := atomicfile.Write(s.path, data); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	if s.file != nil {
+		s.file.Close()
+	}
+	s.file = file
+	s.lines = lines
+	return nil
+}
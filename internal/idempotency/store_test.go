@@ -0,0 +1,194 @@
+package idempotency
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreReserve(t *testing.T) {
+	completed := Record{RequestHash: "h1", Status: 200, Body: []byte(`{"success":true}`), Txid: "abc"}
+
+	tests := []struct {
+		name     string
+		setup    func(s *MemoryStore)
+		hash     string
+		replayed bool
+		err      error
+	}{
+		{name: "new key", setup: func(*MemoryStore) {}, hash: "h1"},
+		{
+			name:  "in progress",
+			setup: func(s *MemoryStore) { _, _ = s.Reserve("k", "h1") },
+			hash:  "h1",
+			err:   ErrInProgress,
+		},
+		{
+			name:     "completed",
+			setup:    func(s *MemoryStore) { _, _ = s.Reserve("k", "h1"); _ = s.Complete("k", completed) },
+			hash:     "h1",
+			replayed: true,
+		},
+		{
+			name:  "different request",
+			setup: func(s *MemoryStore) { _, _ = s.Reserve("k", "h1"); _ = s.Complete("k", completed) },
+			hash:  "h2",
+			err:   ErrMismatch,
+		},
+		{
+			name:  "different request in progress",
+			setup: func(s *MemoryStore) { _, _ = s.Reserve("k", "h1") },
+			hash:  "h2",
+			err:   ErrMismatch,
+		},
+		{
+			name:  "released",
+			setup: func(s *MemoryStore) { _, _ = s.Reserve("k", "h1"); _ = s.Release("k") },
+			hash:  "h2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewMemoryStore(time.Hour, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.setup(s)
+
+			record, err := s.Reserve("k", tt.hash)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if (record != nil) != tt.replayed {
+				t.Fatalf("got record %+v, want replayed %v", record, tt.replayed)
+			}
+			if record != nil && (!record.Completed || record.Txid != completed.Txid || !bytes.Equal(record.Body, completed.Body)) {
+				t.Fatalf("got record %+v, want %+v", record, completed)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreExpiry(t *testing.T) {
+	s, err := NewMemoryStore(time.Millisecond, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Reserve("k", "h1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Complete("k", Record{RequestHash: "h1", Status: 200}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	if record, err := s.Reserve("k", "h2"); record != nil || err != nil {
+		t.Fatalf("expired key: got %+v, %v", record, err)
+	}
+}
+
+func TestMemoryStoreReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "idempotency.jsonl")
+
+	s, err := NewMemoryStore(time.Hour, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"done", "rewritten", "pending"} {
+		if _, err := s.Reserve(key, "h1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, key := range []string{"done", "rewritten", "rewritten"} {
+		if err := s.Complete(key, Record{RequestHash: "h1", Status: 200, TicketID: key}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if n := countLines(t, path); n != 3 {
+		t.Fatalf("file has %d lines, want 3", n)
+	}
+
+	s, err = NewMemoryStore(time.Hour, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if n := countLines(t, path); n != 2 {
+		t.Fatalf("compacted file has %d lines, want 2", n)
+	}
+
+	tests := []struct {
+		key      string
+		replayed bool
+	}{
+		{key: "done", replayed: true},
+		{key: "rewritten", replayed: true},
+		{key: "pending"},
+	}
+	for _, tt := range tests {
+		record, err := s.Reserve(tt.key, "h1")
+		if err != nil {
+			t.Fatalf("%s: %v", tt.key, err)
+		}
+		if (record != nil) != tt.replayed || record != nil && record.TicketID != tt.key {
+			t.Fatalf("%s: got %+v, want replayed %v", tt.key, record, tt.replayed)
+		}
+	}
+}
+
+func TestMemoryStoreCompactsWhileRunning(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "idempotency.jsonl")
+
+	s, err := NewMemoryStore(time.Hour, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if _, err := s.Reserve("k", "h1"); err != nil {
+		t.Fatal(err)
+	}
+	for range minCompactLines {
+		if err := s.Complete("k", Record{RequestHash: "h1", Status: 200}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := countLines(t, path); n != minCompactLines {
+		t.Fatalf("file has %d lines, want %d", n, minCompactLines)
+	}
+
+	s.lastSweep = time.Now().Add(-sweepInterval)
+	if _, err := s.Reserve("other", "h1"); err != nil {
+		t.Fatal(err)
+	}
+	if n := countLines(t, path); n != 1 {
+		t.Fatalf("compacted file has %d lines, want 1", n)
+	}
+
+	// Appends go to the compacted file.
+	if err := s.Complete("other", Record{RequestHash: "h1", Status: 200}); err != nil {
+		t.Fatal(err)
+	}
+	if n := countLines(t, path); n != 2 {
+		t.Fatalf("file has %d lines after an append, want 2", n)
+	}
+}
+
+func countLines(t *testing.T, path string) int {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bytes.Count(data, []byte("\n"))
+}
@@ -0,0 +1,209 @@
+// Package jsonstore keeps records owned by API clients in memory and in a
+// JSON file, for the managed wallets and HD accounts.
+package jsonstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/atomicfile"
+)
+
+// ErrNotSaved wraps the error of a change that could not be written to disk.
+var ErrNotSaved = errors.New("cannot save the store")
+
+// Record is a record owned by an API client. Key identifies what the record
+// stands for, e.g. an address or an xpub; a client holds one record per key.
+type Record interface {
+	RecordID() string
+	RecordOwner() string
+	RecordKey() string
+	RecordCreatedAt() time.Time
+}
+
+// Store keeps records in memory and, with a path, in a JSON file that is
+// rewritten atomically on every change. A change that cannot be saved is
+// undone. Records are only visible to the client that owns them.
+type Store[T Record] struct {
+	mutex       sync.RWMutex
+	path        string
+	records     map[string]T
+	errNotFound error
+	errExists   error
+}
+
+// Open loads the store at path, if it exists. Lookups of records the caller
+// does not own fail with errNotFound, and adding a record for a key its
+// owner already holds fails with errExists.
+func Open[T Record](path string, errNotFound error, errExists error) (*Store[T], error) {
+	s := &Store[T]{path: path, records: make(map[string]T), errNotFound: errNotFound, errExists: errExists}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var records []T
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		s.records[record.RecordID()] = record
+	}
+
+	return s, nil
+}
+
+func (s *Store[T]) Len() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return len(s.records)
+}
+
+// All returns every record, whoever owns it, oldest first.
+func (s *Store[T]) All() []T {
+	return s.list(func(T) bool { return true })
+}
+
+// List returns the records owned by owner, oldest first.
+func (s *Store[T]) List(owner string) []T {
+	return s.list(func(record T) bool { return record.RecordOwner() == owner })
+}
+
+// Get returns record id if owner owns it.
+func (s *Store[T]) Get(id string, owner string) (T, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	record, ok := s.records[id]
+	if !ok || record.RecordOwner() != owner {
+		var zero T
+		return zero, s.errNotFound
+	}
+	return record, nil
+}
+
+// Has reports whether owner holds a record for key.
+func (s *Store[T]) Has(owner string, key string) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.has(owner, key)
+}
+
+// Add stores a new record.
+func (s *Store[T]) Add(record T) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.has(record.RecordOwner(), record.RecordKey()) {
+		return s.errExists
+	}
+
+	s.records[record.RecordID()] = record
+	if err := s.save(); err != nil {
+		delete(s.records, record.RecordID())
+		return err
+	}
+	return nil
+}
+
+// Update replaces a record that still exists and has the same owner.
+func (s *Store[T]) Update(record T) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	previous, ok := s.records[record.RecordID()]
+	if !ok || previous.RecordOwner() != record.RecordOwner() {
+		return s.errNotFound
+	}
+
+	s.records[record.RecordID()] = record
+	if err := s.save(); err != nil {
+		s.records[record.RecordID()] = previous
+		return err
+	}
+	return nil
+}
+
+func (s *Store[T]) Delete(id string, owner string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	record, ok := s.records[id]
+	if !ok || record.RecordOwner() != owner {
+		return s.errNotFound
+	}
+
+	delete(s.records, id)
+	if err := s.save(); err != nil {
+		s.records[id] = record
+		return err
+	}
+	return nil
+}
+
+func (s *Store[T]) has(owner string, key string) bool {
+	for _, existing := range s.records {
+		if existing.RecordOwner() == owner && existing.RecordKey() == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store[T]) list(keep func(T) bool) []T {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var records []T
+	for _, record := range s.records {
+		if keep(record) {
+			records = append(records, record)
+		}
+	}
+	sortByCreation(records)
+	return records
+}
+
+// save writes the records to path, replacing the file atomically. The caller
+// holds the write lock.
+func (s *Store[T]) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	records := make([]T, 0, len(s.records))
+	for _, record := range s.records {
+		records = append(records, record)
+	}
+	sortByCreation(records)
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := atomicfile.Write(s.path, data); err != nil {
+		return fmt.Errorf("%w: %w", ErrNotSaved, err)
+	}
+	return nil
+}
+
+func sortByCreation[T Record](records []T) {
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].RecordCreatedAt().Before(records[j].RecordCreatedAt())
+	})
+}
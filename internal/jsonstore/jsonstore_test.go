@@ -0,0 +1,127 @@
+package jsonstore
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var (
+	errNotFound = errors.New("not found")
+	errExists   = errors.New("exists")
+)
+
+type testRecord struct {
+	ID        string    `json:"id"`
+	Owner     string    `json:"owner"`
+	Key       string    `json:"key"`
+	Label     string    `json:"label"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (r testRecord) RecordID() string           { return r.ID }
+func (r testRecord) RecordOwner() string        { return r.Owner }
+func (r testRecord) RecordKey() string          { return r.Key }
+func (r testRecord) RecordCreatedAt() time.Time { return r.CreatedAt }
+
+func record(id, owner, key string, age time.Duration) testRecord {
+	return testRecord{ID: id, Owner: owner, Key: key, CreatedAt: time.Now().Add(-age)}
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "records.json")
+	s, err := Open[testRecord](path, errNotFound, errExists)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range []testRecord{record("b", "alice", "k2", time.Minute), record("a", "alice", "k1", time.Hour), record("c", "bob", "k1", 0)} {
+		if err := s.Add(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		run  func(s *Store[testRecord]) error
+		err  error
+	}{
+		{name: "same key for another owner", run: func(s *Store[testRecord]) error { return s.Add(record("d", "bob", "k2", 0)) }},
+		{name: "same key for the same owner", run: func(s *Store[testRecord]) error { return s.Add(record("d", "alice", "k1", 0)) }, err: errExists},
+		{name: "get own", run: func(s *Store[testRecord]) error { _, err := s.Get("a", "alice"); return err }},
+		{name: "get another owner's", run: func(s *Store[testRecord]) error { _, err := s.Get("c", "alice"); return err }, err: errNotFound},
+		{name: "get missing", run: func(s *Store[testRecord]) error { _, err := s.Get("z", "alice"); return err }, err: errNotFound},
+		{name: "update another owner's", run: func(s *Store[testRecord]) error { return s.Update(record("c", "alice", "k1", 0)) }, err: errNotFound},
+		{name: "delete another owner's", run: func(s *Store[testRecord]) error { return s.Delete("c", "alice") }, err: errNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(s); !errors.Is(err, tt.err) {
+				t.Fatalf("got %v, want %v", err, tt.err)
+			}
+		})
+	}
+
+	updated := record("a", "alice", "k1", time.Hour)
+	updated.Label = "renamed"
+	if err := s.Update(updated); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("d", "bob"); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := Open[testRecord](path, errNotFound, errExists)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := reloaded.Len(); n != 3 {
+		t.Fatalf("reloaded %d records, want 3", n)
+	}
+	list := reloaded.List("alice")
+	if len(list) != 2 || list[0].ID != "a" || list[1].ID != "b" || list[0].Label != "renamed" {
+		t.Fatalf("got %+v, want a then b, oldest first", list)
+	}
+	if !reloaded.Has("bob", "k1") || reloaded.Has("bob", "k2") {
+		t.Fatal("Has does not match the stored keys")
+	}
+}
+
+func TestStoreUndoesUnsavedChanges(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open[testRecord](filepath.Join(dir, "records.json"), errNotFound, errExists)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add(record("a", "alice", "k1", 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Writes now fail: the temporary file cannot be created.
+	s.path = filepath.Join(dir, "missing", "records.json")
+
+	renamed := record("a", "alice", "k1", 0)
+	renamed.Label = "renamed"
+	tests := []struct {
+		name string
+		run  func() error
+	}{
+		{name: "add", run: func() error { return s.Add(record("b", "alice", "k2", 0)) }},
+		{name: "update", run: func() error { return s.Update(renamed) }},
+		{name: "delete", run: func() error { return s.Delete("a", "alice") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(); !errors.Is(err, ErrNotSaved) {
+				t.Fatalf("got %v, want %v", err, ErrNotSaved)
+			}
+			list := s.List("alice")
+			if len(list) != 1 || list[0].ID != "a" || list[0].Label != "" {
+				t.Fatalf("got %+v, want the record as it was", list)
+			}
+		})
+	}
+}
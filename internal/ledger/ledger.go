@@ -0,0 +1,182 @@
+package ledger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/config"
+)
+
+var ErrDisabled = errors.New("the transfer ledger is disabled: LEDGER_BACKEND is none")
+
+// Kind is what was submitted: a transfer the service built and signed, or a
+// raw transaction signed elsewhere.
+type Kind string
+
+const (
+	KindTransfer Kind = "transfer"
+	KindRawTx    Kind = "rawtx"
+)
+
+// Status is where a submission stands. Pending submissions wait for their
+// ticket; unknown ones timed out or were canceled before the cosigner
+// answered, so they may or may not have gone through. Stuck ones have not
+// finished long after they were submitted, but may still.
+type Status string
+
+const (
+	StatusPending      Status = "pending"
+	StatusBroadcasting Status = "broadcasting"
+	StatusSuccess      Status = "success"
+	StatusFailed       Status = "failed"
+	StatusUnknown      Status = "unknown"
+	StatusStuck        Status = "stuck"
+)
+
+func ParseStatus(value string) (Status, bool) {
+	switch status := Status(value); status {
+	case StatusPending, StatusBroadcasting, StatusSuccess, StatusFailed, StatusUnknown, StatusStuck:
+		return status, true
+	}
+	return "", false
+}
+
+func ParseKind(value string) (Kind, bool) {
+	switch kind := Kind(value); kind {
+	case KindTransfer, KindRawTx:
+		return kind, true
+	}
+	return "", false
+}
+
+type Recipient struct {
+	Address      string `json:"address" example:"1G6CB3Ch4zFkPmuhZzEyChQmrQPfi86qk3"`
+	AtomicAmount uint64 `json:"atomicAmount" example:"10000"`
+}
+
+// Transition is a change of a transfer's status, or of its ticket's errors.
+type Transition struct {
+	Status Status    `json:"status" example:"success"`
+	Errors []string  `json:"errors,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// Transfer is one submission to the cosigner. Sources are the addresses that
+// signed it. AtomicFee is known once the transaction is: right away for
+// synchronous and raw submissions, and once the ticket finishes otherwise.
+type Transfer struct {
+	ID           string       `json:"id" example:"tr_3b9f0c..."`
+	ClientID     string       `json:"clientId,omitempty" example:"payments"`
+	RequestID    string       `json:"requestId,omitempty" example:"4f1c2a..."`
+	Kind         Kind         `json:"kind" example:"transfer"`
+	Async        bool         `json:"async" example:"true"`
+	Status       Status       `json:"status" example:"success"`
+	TicketID     string       `json:"ticketId,omitempty" example:"a1b2c3..."`
+	Txid         string       `json:"txid,omitempty" example:"9e2f..."`
+	Sources      []string     `json:"sources"`
+	Recipients   []Recipient  `json:"recipients"`
+	AtomicAmount uint64       `json:"atomicAmount" example:"10000"`
+	AtomicFee    *uint64      `json:"atomicFee,omitempty" example:"1000"`
+	ErrorCode    string       `json:"errorCode,omitempty" example:"INSUFFICIENT_BALANCE"`
+	Errors       []string     `json:"errors,omitempty"`
+	CreatedAt    time.Time    `json:"createdAt"`
+	UpdatedAt    time.Time    `json:"updatedAt"`
+	Transitions  []Transition `json:"transitions"`
+}
+
+// TicketUpdate is what a ticket lookup learned about the transfers it
+// belongs to. Empty fields leave the transfer as it is.
+type TicketUpdate struct {
+	Status    Status
+	Txid      string
+	AtomicFee *uint64
+	Errors    []string
+}
+
+// TrackedTicket is an asynchronous submission's ticket the webhook relay
+// follows: the client that created it and the callback its request asked
+// for, if any. The callback's secret is only kept sealed by the relay.
+type TrackedTicket struct {
+	TicketID             string
+	ClientID             string
+	CallbackURL          string
+	SealedCallbackSecret string
+	TrackedAt            time.Time
+}
+
+// Filter selects the transfers of ClientID, newest first. Zero fields match
+// everything.
+type Filter struct {
+	ClientID string
+	Kind     Kind
+	Status   Status
+	TicketID string
+	Txid     string
+	Address  string
+	Since    time.Time
+	Until    time.Time
+	Offset   int
+	Limit    int
+}
+
+// Store keeps the ledger. List returns one page of the transfers matching a
+// filter and how many match in total. Unfinished returns up to limit
+// transfers submitted since then whose ticket is still pending, broadcasting
+// or stuck, newest first and without their addresses or transitions.
+//
+// Track, Tracked and ForgetTracked keep the webhook relay's tickets across
+// restarts. Tracked returns nil for a ticket that is not tracked.
+type Store interface {
+	Record(ctx context.Context, transfer Transfer) error
+	UpdateTicket(ctx context.Context, ticketID string, update TicketUpdate) error
+	List(ctx context.Context, filter Filter) ([]Transfer, int, error)
+	Unfinished(ctx context.Context, since time.Time, limit int) ([]Transfer, error)
+	Track(ctx context.Context, ticket TrackedTicket) error
+	Tracked(ctx context.Context, ticketID string) (*TrackedTicket, error)
+	ForgetTracked(ctx context.Context, before time.Time) error
+	Close() error
+}
+
+// Instance is nil when the ledger is disabled.
+var Instance Store
+
+func InitLedger(cfg *config.Config) {
+	switch cfg.LedgerBackend {
+	case "none":
+		log.Printf("LEDGER_BACKEND is none; transfers are not recorded")
+		return
+	case "sqlite":
+	default:
+		log.Fatalf("Invalid LEDGER_BACKEND %q: want sqlite or none", cfg.LedgerBackend)
+	}
+
+	store, err := OpenSQLite(cfg.LedgerPath)
+	if err != nil {
+		log.Fatalf("Failed to open ledger: %v", err)
+	}
+
+	Instance = store
+	log.Printf("Recording transfers in %s", cfg.LedgerPath)
+}
+
+// Close closes Instance, if there is one.
+func Close() error {
+	if Instance == nil {
+		return nil
+	}
+	if err := Instance.Close(); err != nil {
+		return fmt.Errorf("closing ledger: %w", err)
+	}
+	return nil
+}
+
+func NewID() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return "tr_" + hex.EncodeToString(b)
+}
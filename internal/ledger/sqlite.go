@@ -0,0 +1,420 @@
+package ledger
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS transfers (
+	id            TEXT PRIMARY KEY,
+	client_id     TEXT NOT NULL,
+	request_id    TEXT NOT NULL,
+	kind          TEXT NOT NULL,
+	async         INTEGER NOT NULL,
+	status        TEXT NOT NULL,
+	ticket_id     TEXT NOT NULL,
+	txid          TEXT NOT NULL,
+	atomic_amount INTEGER NOT NULL,
+	atomic_fee    INTEGER,
+	error_code    TEXT NOT NULL,
+	errors        TEXT NOT NULL,
+	created_at    INTEGER NOT NULL,
+	updated_at    INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS transfers_client_created ON transfers (client_id, created_at);
+CREATE INDEX IF NOT EXISTS transfers_ticket ON transfers (ticket_id) WHERE ticket_id != '';
+CREATE INDEX IF NOT EXISTS transfers_txid ON transfers (txid) WHERE txid != '';
+CREATE INDEX IF NOT EXISTS transfers_unfinished ON transfers (created_at)
+	WHERE ticket_id != '' AND status IN ('pending', 'broadcasting', 'stuck');
+
+CREATE TABLE IF NOT EXISTS transfer_addresses (
+	transfer_id   TEXT NOT NULL REFERENCES transfers (id),
+	role          TEXT NOT NULL,
+	position      INTEGER NOT NULL,
+	address       TEXT NOT NULL,
+	atomic_amount INTEGER NOT NULL,
+	PRIMARY KEY (transfer_id, role, position)
+);
+CREATE INDEX IF NOT EXISTS transfer_addresses_address ON transfer_addresses (address);
+
+CREATE TABLE IF NOT EXISTS transfer_transitions (
+	transfer_id TEXT NOT NULL REFERENCES transfers (id),
+	status      TEXT NOT NULL,
+	errors      TEXT NOT NULL,
+	at          INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS transfer_transitions_transfer ON transfer_transitions (transfer_id);
+
+CREATE TABLE IF NOT EXISTS tracked_tickets (
+	ticket_id              TEXT PRIMARY KEY,
+	client_id              TEXT NOT NULL,
+	callback_url           TEXT NOT NULL,
+	sealed_callback_secret TEXT NOT NULL,
+	tracked_at             INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS tracked_tickets_tracked_at ON tracked_tickets (tracked_at);
+`
+
+const (
+	roleSource    = "source"
+	roleRecipient = "recipient"
+)
+
+// SQLiteStore is a Store in a SQLite database file. Times are kept as Unix
+// milliseconds and error lists as JSON arrays.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+var _ Store = (*SQLiteStore)(nil)
+
+func OpenSQLite(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)")
+	if err != nil {
+		return nil, err
+	}
+	// SQLite takes one writer at a time; a single connection queues them
+	// here instead of failing with SQLITE_BUSY.
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(schema); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *SQLiteStore) Record(ctx context.Context, transfer Transfer) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var fee any
+	if transfer.AtomicFee != nil {
+		fee = int64(*transfer.AtomicFee)
+	}
+	_, err = tx.ExecContext(ctx, `INSERT INTO transfers (id, client_id, request_id, kind, async, status, ticket_id, txid,
+		atomic_amount, atomic_fee, error_code, errors, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		transfer.ID, transfer.ClientID, transfer.RequestID, transfer.Kind, transfer.Async, transfer.Status, transfer.TicketID,
+		transfer.Txid, int64(transfer.AtomicAmount), fee, transfer.ErrorCode, encodeErrors(transfer.Errors),
+		transfer.CreatedAt.UnixMilli(), transfer.UpdatedAt.UnixMilli())
+	if err != nil {
+		return err
+	}
+
+	for i, address := range transfer.Sources {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO transfer_addresses (transfer_id, role, position, address, atomic_amount)
+			VALUES (?, ?, ?, ?, 0)`, transfer.ID, roleSource, i, address); err != nil {
+			return err
+		}
+	}
+	for i, recipient := range transfer.Recipients {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO transfer_addresses (transfer_id, role, position, address, atomic_amount)
+			VALUES (?, ?, ?, ?, ?)`, transfer.ID, roleRecipient, i, recipient.Address, int64(recipient.AtomicAmount)); err != nil {
+			return err
+		}
+	}
+
+	if err := addTransition(ctx, tx, transfer.ID, transfer.Status, transfer.Errors, transfer.CreatedAt); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UpdateTicket applies update to the transfers with ticketID. A status or
+// error change is added to their transitions. A transfer that already
+// succeeded or failed keeps its status, and a stuck one stays stuck until
+// its ticket finishes.
+func (s *SQLiteStore) UpdateTicket(ctx context.Context, ticketID string, update TicketUpdate) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	rows, err := tx.QueryContext(ctx, `SELECT id, status, txid, atomic_fee, errors FROM transfers WHERE ticket_id = ?`, ticketID)
+	if err != nil {
+		return err
+	}
+	type current struct {
+		id, txid, errors string
+		status           Status
+		fee              sql.NullInt64
+	}
+	var transfers []current
+	for rows.Next() {
+		var t current
+		if err := rows.Scan(&t.id, &t.status, &t.txid, &t.fee, &t.errors); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		transfers = append(transfers, t)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for _, t := range transfers {
+		status := t.status
+		switch {
+		case update.Status == "", t.status == StatusSuccess, t.status == StatusFailed:
+		case t.status == StatusStuck && (update.Status == StatusPending || update.Status == StatusBroadcasting):
+		default:
+			status = update.Status
+		}
+		errs := t.errors
+		if len(update.Errors) > 0 {
+			errs = encodeErrors(update.Errors)
+		}
+		txid := t.txid
+		if txid == "" {
+			txid = update.Txid
+		}
+		fee := t.fee
+		if !fee.Valid && update.AtomicFee != nil {
+			fee = sql.NullInt64{Int64: int64(*update.AtomicFee), Valid: true}
+		}
+
+		changed := status != t.status || errs != t.errors
+		if !changed && txid == t.txid && fee == t.fee {
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, `UPDATE transfers SET status = ?, txid = ?, atomic_fee = ?, errors = ?, updated_at = ?
+			WHERE id = ?`, status, txid, fee, errs, now.UnixMilli(), t.id); err != nil {
+			return err
+		}
+		if changed {
+			if err := addTransition(ctx, tx, t.id, status, decodeErrors(errs), now); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *SQLiteStore) List(ctx context.Context, filter Filter) ([]Transfer, int, error) {
+	where := []string{"client_id = ?"}
+	args := []any{filter.ClientID}
+	if filter.Kind != "" {
+		where = append(where, "kind = ?")
+		args = append(args, filter.Kind)
+	}
+	if filter.Status != "" {
+		where = append(where, "status = ?")
+		args = append(args, filter.Status)
+	}
+	if filter.TicketID != "" {
+		where = append(where, "ticket_id = ?")
+		args = append(args, filter.TicketID)
+	}
+	if filter.Txid != "" {
+		where = append(where, "txid = ?")
+		args = append(args, filter.Txid)
+	}
+	if filter.Address != "" {
+		where = append(where, "id IN (SELECT transfer_id FROM transfer_addresses WHERE address = ?)")
+		args = append(args, filter.Address)
+	}
+	if !filter.Since.IsZero() {
+		where = append(where, "created_at >= ?")
+		args = append(args, filter.Since.UnixMilli())
+	}
+	if !filter.Until.IsZero() {
+		where = append(where, "created_at < ?")
+		args = append(args, filter.Until.UnixMilli())
+	}
+	clause := " WHERE " + strings.Join(where, " AND ")
+
+	var total int
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM transfers"+clause, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT id, client_id, request_id, kind, async, status, ticket_id, txid, atomic_amount,
+		atomic_fee, error_code, errors, created_at, updated_at FROM transfers`+clause+
+		` ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	transfers := []Transfer{}
+	index := make(map[string]int)
+	for rows.Next() {
+		var t Transfer
+		var amount int64
+		var fee sql.NullInt64
+		var errs string
+		var createdAt, updatedAt int64
+		if err := rows.Scan(&t.ID, &t.ClientID, &t.RequestID, &t.Kind, &t.Async, &t.Status, &t.TicketID, &t.Txid, &amount,
+			&fee, &t.ErrorCode, &errs, &createdAt, &updatedAt); err != nil {
+			return nil, 0, err
+		}
+		t.AtomicAmount = uint64(amount)
+		if fee.Valid {
+			value := uint64(fee.Int64)
+			t.AtomicFee = &value
+		}
+		t.Errors = decodeErrors(errs)
+		t.CreatedAt = time.UnixMilli(createdAt).UTC()
+		t.UpdatedAt = time.UnixMilli(updatedAt).UTC()
+		t.Sources = []string{}
+		t.Recipients = []Recipient{}
+		t.Transitions = []Transition{}
+
+		index[t.ID] = len(transfers)
+		transfers = append(transfers, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	if len(transfers) == 0 {
+		return transfers, total, nil
+	}
+
+	if err := s.loadDetails(ctx, transfers, index); err != nil {
+		return nil, 0, err
+	}
+	return transfers, total, nil
+}
+
+func (s *SQLiteStore) Unfinished(ctx context.Context, since time.Time, limit int) ([]Transfer, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, client_id, kind, async, status, ticket_id, created_at FROM transfers
+		WHERE ticket_id != '' AND status IN ('pending', 'broadcasting', 'stuck') AND created_at >= ?
+		ORDER BY created_at DESC LIMIT ?`, since.UnixMilli(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transfers []Transfer
+	for rows.Next() {
+		var t Transfer
+		var createdAt int64
+		if err := rows.Scan(&t.ID, &t.ClientID, &t.Kind, &t.Async, &t.Status, &t.TicketID, &createdAt); err != nil {
+			return nil, err
+		}
+		t.CreatedAt = time.UnixMilli(createdAt).UTC()
+		transfers = append(transfers, t)
+	}
+	return transfers, rows.Err()
+}
+
+// Track stores ticket, replacing an earlier entry for the same ticket.
+func (s *SQLiteStore) Track(ctx context.Context, ticket TrackedTicket) error {
+	_, err := s.db.ExecContext(ctx, `INSERT INTO tracked_tickets (ticket_id, client_id, callback_url, sealed_callback_secret, tracked_at)
+		VALUES (?, ?, ?, ?, ?) ON CONFLICT (ticket_id) DO UPDATE SET client_id = excluded.client_id,
+		callback_url = excluded.callback_url, sealed_callback_secret = excluded.sealed_callback_secret, tracked_at = excluded.tracked_at`,
+		ticket.TicketID, ticket.ClientID, ticket.CallbackURL, ticket.SealedCallbackSecret, ticket.TrackedAt.UnixMilli())
+	return err
+}
+
+func (s *SQLiteStore) Tracked(ctx context.Context, ticketID string) (*TrackedTicket, error) {
+	ticket := TrackedTicket{TicketID: ticketID}
+	var trackedAt int64
+	err := s.db.QueryRowContext(ctx, `SELECT client_id, callback_url, sealed_callback_secret, tracked_at FROM tracked_tickets
+		WHERE ticket_id = ?`, ticketID).Scan(&ticket.ClientID, &ticket.CallbackURL, &ticket.SealedCallbackSecret, &trackedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ticket.TrackedAt = time.UnixMilli(trackedAt).UTC()
+	return &ticket, nil
+}
+
+// ForgetTracked deletes the tickets tracked before then.
+func (s *SQLiteStore) ForgetTracked(ctx context.Context, before time.Time) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM tracked_tickets WHERE tracked_at < ?`, before.UnixMilli())
+	return err
+}
+
+// loadDetails fills in the addresses and transitions of transfers.
+func (s *SQLiteStore) loadDetails(ctx context.Context, transfers []Transfer, index map[string]int) error {
+	ids := make([]any, 0, len(transfers))
+	for _, t := range transfers {
+		ids = append(ids, t.ID)
+	}
+	in := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ") + ")"
+
+	rows, err := s.db.QueryContext(ctx, `SELECT transfer_id, role, address, atomic_amount FROM transfer_addresses
+		WHERE transfer_id IN `+in+` ORDER BY transfer_id, role, position`, ids...)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var id, role, address string
+		var amount int64
+		if err := rows.Scan(&id, &role, &address, &amount); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		t := &transfers[index[id]]
+		if role == roleSource {
+			t.Sources = append(t.Sources, address)
+		} else {
+			t.Recipients = append(t.Recipients, Recipient{Address: address, AtomicAmount: uint64(amount)})
+		}
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = s.db.QueryContext(ctx, `SELECT transfer_id, status, errors, at FROM transfer_transitions
+		WHERE transfer_id IN `+in+` ORDER BY at, rowid`, ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, errs string
+		var transition Transition
+		var at int64
+		if err := rows.Scan(&id, &transition.Status, &errs, &at); err != nil {
+			return err
+		}
+		transition.Errors = decodeErrors(errs)
+		transition.At = time.UnixMilli(at).UTC()
+		t := &transfers[index[id]]
+		t.Transitions = append(t.Transitions, transition)
+	}
+	return rows.Err()
+}
+
+func addTransition(ctx context.Context, tx *sql.Tx, id string, status Status, errs []string, at time.Time) error {
+	_, err := tx.ExecContext(ctx, `INSERT INTO transfer_transitions (transfer_id, status, errors, at) VALUES (?, ?, ?, ?)`,
+		id, status, encodeErrors(errs), at.UnixMilli())
+	return err
+}
+
+func encodeErrors(errs []string) string {
+	if len(errs) == 0 {
+		return "[]"
+	}
+	data, _ := json.Marshal(errs)
+	return string(data)
+}
+
+func decodeErrors(data string) []string {
+	var errs []string
+	_ = json.Unmarshal([]byte(data), &errs)
+	return errs
+}
@@ -0,0 +1,297 @@
+package ledger
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func openTestStore(t *testing.T) *SQLiteStore {
+	t.Helper()
+
+	store, err := OpenSQLite(filepath.Join(t.TempDir(), "ledger.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func testTransfer(id string, status Status, ticketID string, createdAt time.Time) Transfer {
+	return Transfer{
+		ID:           id,
+		ClientID:     "alice",
+		Kind:         KindTransfer,
+		Async:        ticketID != "",
+		Status:       status,
+		TicketID:     ticketID,
+		Sources:      []string{"source"},
+		Recipients:   []Recipient{{Address: "recipient", AtomicAmount: 1000}},
+		AtomicAmount: 1000,
+		CreatedAt:    createdAt,
+		UpdatedAt:    createdAt,
+	}
+}
+
+func TestUpdateTicket(t *testing.T) {
+	fee := uint64(100)
+	otherFee := uint64(999)
+
+	tests := []struct {
+		name        string
+		status      Status
+		updates     []TicketUpdate
+		want        Status
+		txid        string
+		fee         *uint64
+		errors      []string
+		transitions []Status
+	}{
+		{
+			name:        "pending to success",
+			status:      StatusPending,
+			updates:     []TicketUpdate{{Status: StatusBroadcasting}, {Status: StatusSuccess, Txid: "tx1", AtomicFee: &fee}},
+			want:        StatusSuccess,
+			txid:        "tx1",
+			fee:         &fee,
+			transitions: []Status{StatusPending, StatusBroadcasting, StatusSuccess},
+		},
+		{
+			name:        "pending to failed",
+			status:      StatusPending,
+			updates:     []TicketUpdate{{Status: StatusFailed, Errors: []string{"rejected"}}},
+			want:        StatusFailed,
+			errors:      []string{"rejected"},
+			transitions: []Status{StatusPending, StatusFailed},
+		},
+		{
+			name:        "repeated status is not a transition",
+			status:      StatusPending,
+			updates:     []TicketUpdate{{Status: StatusBroadcasting}, {Status: StatusBroadcasting}},
+			want:        StatusBroadcasting,
+			transitions: []Status{StatusPending, StatusBroadcasting},
+		},
+		{
+			name:        "new errors are a transition",
+			status:      StatusPending,
+			updates:     []TicketUpdate{{Status: StatusBroadcasting}, {Status: StatusBroadcasting, Errors: []string{"retrying"}}},
+			want:        StatusBroadcasting,
+			errors:      []string{"retrying"},
+			transitions: []Status{StatusPending, StatusBroadcasting, StatusBroadcasting},
+		},
+		{
+			name:        "final status is kept",
+			status:      StatusPending,
+			updates:     []TicketUpdate{{Status: StatusSuccess, Txid: "tx1", AtomicFee: &fee}, {Status: StatusFailed, Txid: "tx2", AtomicFee: &otherFee}},
+			want:        StatusSuccess,
+			txid:        "tx1",
+			fee:         &fee,
+			transitions: []Status{StatusPending, StatusSuccess},
+		},
+		{
+			name:        "stuck stays stuck until it finishes",
+			status:      StatusPending,
+			updates:     []TicketUpdate{{Status: StatusStuck}, {Status: StatusBroadcasting}, {Status: StatusPending}, {Status: StatusSuccess}},
+			want:        StatusSuccess,
+			transitions: []Status{StatusPending, StatusStuck, StatusSuccess},
+		},
+		{
+			name:        "unknown learns its outcome",
+			status:      StatusUnknown,
+			updates:     []TicketUpdate{{Status: StatusSuccess, Txid: "tx1"}},
+			want:        StatusSuccess,
+			txid:        "tx1",
+			transitions: []Status{StatusUnknown, StatusSuccess},
+		},
+		{
+			name:        "empty update",
+			status:      StatusPending,
+			updates:     []TicketUpdate{{}},
+			want:        StatusPending,
+			transitions: []Status{StatusPending},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := openTestStore(t)
+			ctx := t.Context()
+
+			if err := store.Record(ctx, testTransfer("tr_1", tt.status, "ticket", time.Now().UTC())); err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Record(ctx, testTransfer("tr_2", StatusPending, "other", time.Now().UTC())); err != nil {
+				t.Fatal(err)
+			}
+			for _, update := range tt.updates {
+				if err := store.UpdateTicket(ctx, "ticket", update); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			transfers, _, err := store.List(ctx, Filter{ClientID: "alice", TicketID: "ticket", Limit: 10})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(transfers) != 1 {
+				t.Fatalf("got %d transfers, want 1", len(transfers))
+			}
+			got := transfers[0]
+
+			if got.Status != tt.want || got.Txid != tt.txid || !slices.Equal(got.Errors, tt.errors) {
+				t.Fatalf("got %s, txid %q, errors %v; want %s, %q, %v", got.Status, got.Txid, got.Errors, tt.want, tt.txid, tt.errors)
+			}
+			if (got.AtomicFee == nil) != (tt.fee == nil) || got.AtomicFee != nil && *got.AtomicFee != *tt.fee {
+				t.Fatalf("got fee %v, want %v", got.AtomicFee, tt.fee)
+			}
+
+			var transitions []Status
+			for _, transition := range got.Transitions {
+				transitions = append(transitions, transition.Status)
+			}
+			if !slices.Equal(transitions, tt.transitions) {
+				t.Fatalf("got transitions %v, want %v", transitions, tt.transitions)
+			}
+
+			other, _, err := store.List(ctx, Filter{ClientID: "alice", TicketID: "other", Limit: 10})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if other[0].Status != StatusPending || len(other[0].Transitions) != 1 {
+				t.Fatalf("another ticket's transfer changed: %+v", other[0])
+			}
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	store := openTestStore(t)
+	ctx := t.Context()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	transfers := []Transfer{
+		testTransfer("tr_1", StatusSuccess, "", now.Add(-3*time.Hour)),
+		testTransfer("tr_2", StatusPending, "t2", now.Add(-2*time.Hour)),
+		testTransfer("tr_3", StatusFailed, "", now.Add(-time.Hour)),
+	}
+	transfers[1].Recipients = []Recipient{{Address: "elsewhere", AtomicAmount: 1000}}
+	transfers[2].Kind = KindRawTx
+	bob := testTransfer("tr_4", StatusSuccess, "", now)
+	bob.ClientID = "bob"
+	for _, transfer := range append(transfers, bob) {
+		if err := store.Record(ctx, transfer); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+		total  int
+	}{
+		{name: "all, newest first", filter: Filter{}, want: []string{"tr_3", "tr_2", "tr_1"}, total: 3},
+		{name: "page", filter: Filter{Offset: 1, Limit: 1}, want: []string{"tr_2"}, total: 3},
+		{name: "status", filter: Filter{Status: StatusPending}, want: []string{"tr_2"}, total: 1},
+		{name: "kind", filter: Filter{Kind: KindRawTx}, want: []string{"tr_3"}, total: 1},
+		{name: "address", filter: Filter{Address: "recipient"}, want: []string{"tr_3", "tr_1"}, total: 2},
+		{name: "source address", filter: Filter{Address: "source"}, want: []string{"tr_3", "tr_2", "tr_1"}, total: 3},
+		{name: "since", filter: Filter{Since: now.Add(-2 * time.Hour)}, want: []string{"tr_3", "tr_2"}, total: 2},
+		{name: "until", filter: Filter{Until: now.Add(-2 * time.Hour)}, want: []string{"tr_1"}, total: 1},
+		{name: "another client", filter: Filter{ClientID: "bob"}, want: []string{"tr_4"}, total: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := tt.filter
+			if filter.ClientID == "" {
+				filter.ClientID = "alice"
+			}
+			if filter.Limit == 0 {
+				filter.Limit = 10
+			}
+
+			got, total, err := store.List(ctx, filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ids []string
+			for _, transfer := range got {
+				ids = append(ids, transfer.ID)
+			}
+			if !slices.Equal(ids, tt.want) || total != tt.total {
+				t.Fatalf("got %v of %d, want %v of %d", ids, total, tt.want, tt.total)
+			}
+		})
+	}
+}
+
+func TestUnfinished(t *testing.T) {
+	store := openTestStore(t)
+	ctx := t.Context()
+	now := time.Now().UTC()
+
+	for _, transfer := range []Transfer{
+		testTransfer("tr_pending", StatusPending, "t1", now.Add(-time.Minute)),
+		testTransfer("tr_stuck", StatusStuck, "t2", now.Add(-time.Hour)),
+		testTransfer("tr_old", StatusPending, "t3", now.Add(-48*time.Hour)),
+		testTransfer("tr_done", StatusSuccess, "t4", now),
+		testTransfer("tr_sync", StatusUnknown, "", now),
+	} {
+		if err := store.Record(ctx, transfer); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := store.Unfinished(ctx, now.Add(-24*time.Hour), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, transfer := range got {
+		ids = append(ids, transfer.ID)
+	}
+	if want := []string{"tr_pending", "tr_stuck"}; !slices.Equal(ids, want) {
+		t.Fatalf("got %v, want %v", ids, want)
+	}
+}
+
+func TestTrackedTickets(t *testing.T) {
+	store := openTestStore(t)
+	ctx := t.Context()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	tracked := []TrackedTicket{
+		{TicketID: "old", ClientID: "alice", TrackedAt: now.Add(-48 * time.Hour)},
+		{TicketID: "t1", ClientID: "alice", CallbackURL: "https://example.com/a", SealedCallbackSecret: "s1", TrackedAt: now},
+		// Tracking again replaces the entry.
+		{TicketID: "t1", ClientID: "alice", CallbackURL: "https://example.com/b", SealedCallbackSecret: "s2", TrackedAt: now},
+	}
+	for _, ticket := range tracked {
+		if err := store.Track(ctx, ticket); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.ForgetTracked(ctx, now.Add(-24*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		ticketID string
+		want     *TrackedTicket
+	}{
+		{ticketID: "t1", want: &tracked[2]},
+		{ticketID: "old"},
+		{ticketID: "missing"},
+	}
+	for _, tt := range tests {
+		got, err := store.Tracked(ctx, tt.ticketID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (got == nil) != (tt.want == nil) || got != nil && *got != *tt.want {
+			t.Fatalf("%s: got %+v, want %+v", tt.ticketID, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Package lifecycle tracks whether the server is shutting down and which
+// transfer requests it is still serving.
+package lifecycle
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	draining     = make(chan struct{})
+	drainingOnce sync.Once
+)
+
+// StartDraining marks the server as shutting down: it is no longer ready,
+// and long-lived requests such as ticket streams should end.
+func StartDraining() {
+	drainingOnce.Do(func() { close(draining) })
+}
+
+// Draining is closed once StartDraining has been called.
+func Draining() <-chan struct{} {
+	return draining
+}
+
+func IsDraining() bool {
+	select {
+	case <-draining:
+		return true
+	default:
+		return false
+	}
+}
+
+// Request is a request that moves or watches funds.
+type Request struct {
+	ID             uint64
+	Method         string
+	Path           string
+	ClientID       string
+	IdempotencyKey string
+	TicketID       string
+	StartedAt      time.Time
+}
+
+// Tracker records the requests in flight so shutdown can report the ones it
+// waits for or abandons.
+type Tracker struct {
+	mutex    sync.Mutex
+	next     uint64
+	requests map[uint64]*Request
+}
+
+var InFlight = NewTracker()
+
+func NewTracker() *Tracker {
+	return &Tracker{requests: make(map[uint64]*Request)}
+}
+
+// Begin records request and returns its ID for SetTicket and End.
+func (t *Tracker) Begin(request Request) uint64 {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.next++
+	request.ID = t.next
+	t.requests[request.ID] = &request
+	return request.ID
+}
+
+// SetTicket records the ticket a request is waiting for, once known.
+func (t *Tracker) SetTicket(id uint64, ticketID string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if request, ok := t.requests[id]; ok {
+		request.TicketID = ticketID
+	}
+}
+
+func (t *Tracker) End(id uint64) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.requests, id)
+}
+
+// Snapshot returns the requests in flight, oldest first.
+func (t *Tracker) Snapshot() []Request {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	requests := make([]Request, 0, len(t.requests))
+	for _, request := range t.requests {
+		requests = append(requests, *request)
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].ID < requests[j].ID
+	})
+	return requests
+}
@@ -0,0 +1,72 @@
+package lifecycle
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTracker(t *testing.T) {
+	tracker := NewTracker()
+	if got := tracker.Snapshot(); len(got) != 0 {
+		t.Fatalf("new tracker holds %+v", got)
+	}
+
+	started := time.Now()
+	first := tracker.Begin(Request{Method: "POST", Path: "/api/transfer", ClientID: "acme", IdempotencyKey: "key-1", StartedAt: started})
+	second := tracker.Begin(Request{Method: "GET", Path: "/api/transaction/status/t-2", TicketID: "t-2", StartedAt: started})
+	third := tracker.Begin(Request{Method: "POST", Path: "/api/transfer-async"})
+	if first == second || second == third || first == third {
+		t.Fatalf("IDs %d, %d, %d are not unique", first, second, third)
+	}
+
+	tracker.SetTicket(first, "t-1")
+	tracker.SetTicket(12345, "unknown")
+	tracker.End(third)
+	tracker.End(third)
+
+	got := tracker.Snapshot()
+	if len(got) != 2 {
+		t.Fatalf("Snapshot() = %+v, want 2 requests", got)
+	}
+	if got[0].ID != first || got[0].TicketID != "t-1" || got[0].ClientID != "acme" || got[0].IdempotencyKey != "key-1" {
+		t.Fatalf("first request = %+v", got[0])
+	}
+	if got[1].ID != second || got[1].TicketID != "t-2" {
+		t.Fatalf("second request = %+v", got[1])
+	}
+
+	// A snapshot is a copy.
+	got[0].TicketID = "changed"
+	if tracker.Snapshot()[0].TicketID != "t-1" {
+		t.Fatal("changing a snapshot changed the tracker")
+	}
+
+	tracker.End(first)
+	tracker.End(second)
+	if got := tracker.Snapshot(); len(got) != 0 {
+		t.Fatalf("Snapshot() after End = %+v", got)
+	}
+}
+
+func TestDraining(t *testing.T) {
+	if IsDraining() {
+		t.Fatal("draining before StartDraining")
+	}
+	select {
+	case <-Draining():
+		t.Fatal("Draining() closed before StartDraining")
+	default:
+	}
+
+	StartDraining()
+	StartDraining()
+
+	if !IsDraining() {
+		t.Fatal("not draining after StartDraining")
+	}
+	select {
+	case <-Draining():
+	default:
+		t.Fatal("Draining() still open after StartDraining")
+	}
+}
@@ -0,0 +1,121 @@
+// Package logging sets up the structured logger and keeps keys, secrets and
+// raw transactions out of everything it writes.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are attribute keys whose values are always redacted,
+// compared in lower case.
+var sensitiveKeys = map[string]bool{
+	"wif":             true,
+	"wifs":            true,
+	"callbacksecret":  true,
+	"callback_secret": true,
+	"rawtx":           true,
+	"rawtxhex":        true,
+	"txhex":           true,
+	"tx_hex":          true,
+	"authorization":   true,
+	"apikey":          true,
+	"api_key":         true,
+	"mneetoken":       true,
+}
+
+var (
+	// sensitiveFields matches the same keys as JSON fields, e.g. in request
+	// bodies quoted by error messages.
+	sensitiveFields = regexp.MustCompile(`(?i)"(wifs?|callback_?secret|raw_?tx(hex)?|tx_?hex|api_?key|mnee_?token|authorization)"\s*:\s*("(\\.|[^"\\])*"|\[[^\]]*\])`)
+	// wifPattern matches WIF private keys, compressed or not, on mainnet or
+	// testnet.
+	wifPattern = regexp.MustCompile(`\b[5KLc9][1-9A-HJ-NP-Za-km-z]{50,51}\b`)
+	// rawHexPattern matches hex too long to be a txid or key, i.e. raw
+	// transactions.
+	rawHexPattern = regexp.MustCompile(`\b[0-9a-fA-F]{130,}\b`)
+	// authTokenPattern matches the API key the SDK puts in request URLs,
+	// which show up in its errors.
+	authTokenPattern = regexp.MustCompile(`auth_token=[^&\s"]*`)
+	// bearerPattern matches a bearer token, as in a quoted Authorization
+	// header.
+	bearerPattern = regexp.MustCompile(`(?i)\b(bearer)\s+[^\s",]+`)
+	// clientKeyPattern matches the API keys auth.GenerateKey hands out.
+	clientKeyPattern = regexp.MustCompile(`\bmnee_[0-9a-f]{64}\b`)
+)
+
+// Redact replaces keys, secrets and raw transactions in s.
+func Redact(s string) string {
+	s = sensitiveFields.ReplaceAllString(s, `"$1":"`+redacted+`"`)
+	s = authTokenPattern.ReplaceAllString(s, "auth_token="+redacted)
+	s = bearerPattern.ReplaceAllString(s, "$1 "+redacted)
+	s = clientKeyPattern.ReplaceAllString(s, redacted)
+	s = wifPattern.ReplaceAllString(s, redacted)
+	return rawHexPattern.ReplaceAllString(s, redacted)
+}
+
+// replaceAttr redacts sensitive attributes by key, and every string,
+// including the message, by content.
+func replaceAttr(_ []string, attr slog.Attr) slog.Attr {
+	if sensitiveKeys[strings.ToLower(attr.Key)] {
+		return slog.String(attr.Key, redacted)
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, Redact(attr.Value.String()))
+	case slog.KindAny:
+		if err, ok := attr.Value.Any().(error); ok {
+			return slog.String(attr.Key, Redact(err.Error()))
+		}
+		return slog.String(attr.Key, Redact(fmt.Sprint(attr.Value.Any())))
+	}
+	return attr
+}
+
+// Init makes a redacting slog logger writing format ("json" or "text") at
+// level the default, which log.Printf writes through too.
+func Init(w io.Writer, format string, level string) error {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return err
+	}
+
+	options := &slog.HandlerOptions{Level: lvl, ReplaceAttr: replaceAttr}
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case "", "json":
+		handler = slog.NewJSONHandler(w, options)
+	case "text":
+		handler = slog.NewTextHandler(w, options)
+	default:
+		return fmt.Errorf("unknown format %q: use json or text", format)
+	}
+
+	slog.SetDefault(slog.New(handler))
+	log.SetFlags(0)
+	return nil
+}
+
+type loggerKey struct{}
+
+// WithLogger returns a copy of ctx carrying logger, for FromContext.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger of the request ctx belongs to, which tags
+// lines with its request ID, or the default logger.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
@@ -0,0 +1,106 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"log/slog"
+	"strings"
+	"testing"
+
+	primitives "github.com/bsv-blockchain/go-sdk/primitives/ec"
+)
+
+func TestRedact(t *testing.T) {
+	privateKey, err := primitives.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wif := privateKey.Wif()
+	rawTx := strings.Repeat("0a", 100)
+	clientKey := "mnee_" + strings.Repeat("3f", 32)
+
+	tests := []struct {
+		name   string
+		input  string
+		secret string
+		kept   string
+	}{
+		{name: "WIF", input: "invalid key " + wif, secret: wif, kept: "invalid key"},
+		{name: "WIFs field", input: `{"wifs":["` + wif + `","x"]}`, secret: wif, kept: `"wifs"`},
+		{name: "callback secret field", input: `{"callbackSecret":"hunter2","callbackUrl":"https://example.com"}`, secret: "hunter2", kept: "https://example.com"},
+		{name: "snake case callback secret", input: `{"callback_secret": "hunter2"}`, secret: "hunter2"},
+		{name: "raw transaction field", input: `{"rawTxHex":"0100ab"}`, secret: "0100ab"},
+		{name: "raw transaction", input: "broadcasting " + rawTx, secret: rawTx},
+		{name: "SDK URL token", input: `Get "https://api.mnee.net/v1/balance?auth_token=sdk-key-123": timeout`, secret: "sdk-key-123", kept: "https://api.mnee.net/v1/balance"},
+		{name: "mneeToken field", input: `{"mneeToken":"sdk-key-123"}`, secret: "sdk-key-123"},
+		{name: "API key field", input: `{"apiKey":"sdk-key-123"}`, secret: "sdk-key-123"},
+		{name: "authorization header", input: "Authorization: Bearer " + clientKey, secret: clientKey},
+		{name: "bearer token", input: "retrying with bearer abc.def-123", secret: "abc.def-123"},
+		{name: "client key", input: "rejected key " + clientKey, secret: clientKey},
+		{name: "txid is kept", input: "txid " + strings.Repeat("ab", 32), kept: strings.Repeat("ab", 32)},
+		{name: "address is kept", input: "address 1G6CB3Ch4zFkPmuhZzEyChQmrQPfi86qk3", kept: "1G6CB3Ch4zFkPmuhZzEyChQmrQPfi86qk3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Redact(tt.input)
+			if tt.secret != "" && strings.Contains(got, tt.secret) {
+				t.Fatalf("Redact(%q) = %q, which still holds the secret", tt.input, got)
+			}
+			if tt.secret != "" && !strings.Contains(got, redacted) {
+				t.Fatalf("Redact(%q) = %q, without %s", tt.input, got, redacted)
+			}
+			if !strings.Contains(got, tt.kept) {
+				t.Fatalf("Redact(%q) = %q, want %q kept", tt.input, got, tt.kept)
+			}
+		})
+	}
+}
+
+func TestLoggerRedacts(t *testing.T) {
+	privateKey, err := primitives.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wif := privateKey.Wif()
+
+	tests := []struct {
+		name   string
+		log    func(logger *slog.Logger)
+		secret string
+	}{
+		{name: "message", log: func(l *slog.Logger) { l.Info("importing " + wif) }, secret: wif},
+		{name: "wif attribute", log: func(l *slog.Logger) { l.Info("import", "wif", "anything") }, secret: "anything"},
+		{name: "string attribute", log: func(l *slog.Logger) { l.Info("import", "input", wif) }, secret: wif},
+		{name: "error attribute", log: func(l *slog.Logger) { l.Error("call", "error", errors.New("GET /v1?auth_token=sdk-key-123 failed")) }, secret: "sdk-key-123"},
+		{name: "any attribute", log: func(l *slog.Logger) { l.Info("request", "wifs", []string{wif}) }, secret: wif},
+		{name: "authorization attribute", log: func(l *slog.Logger) { l.Info("request", "Authorization", "Bearer client-key") }, secret: "client-key"},
+		{name: "API key attribute", log: func(l *slog.Logger) { l.Info("tenant", "apiKey", "sdk-key-123") }, secret: "sdk-key-123"},
+		{name: "mneeToken attribute", log: func(l *slog.Logger) { l.Info("sdk", "mneeToken", "sdk-key-123") }, secret: "sdk-key-123"},
+		{name: "callback secret attribute", log: func(l *slog.Logger) { l.Info("track", "callbackSecret", "hunter2") }, secret: "hunter2"},
+		{name: "log.Printf", log: func(*slog.Logger) { log.Printf("webhooks: giving up on %s", wif) }, secret: wif},
+	}
+
+	for _, format := range []string{"json", "text"} {
+		for _, tt := range tests {
+			t.Run(format+" "+tt.name, func(t *testing.T) {
+				previous := slog.Default()
+				t.Cleanup(func() { slog.SetDefault(previous) })
+
+				var out bytes.Buffer
+				if err := Init(&out, format, "info"); err != nil {
+					t.Fatal(err)
+				}
+				tt.log(slog.Default())
+
+				if out.Len() == 0 {
+					t.Fatal("nothing was logged")
+				}
+				if strings.Contains(out.String(), tt.secret) || !strings.Contains(out.String(), redacted) {
+					t.Fatalf("logged %s", out.String())
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// Package metrics holds the Prometheus collectors served on /metrics.
+package metrics
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry only holds the collectors below plus the Go runtime and process
+// ones, so /metrics does not pick up whatever dependencies register globally.
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests served, by gin route, method and status.",
+	}, []string{"route", "method", "status"})
+
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Time taken to serve HTTP requests, by gin route, method and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	SDKCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "mnee_sdk_calls_total",
+		Help: "Calls to the MNEE SDK, by method and outcome (ok or error).",
+	}, []string{"method", "outcome"})
+
+	SDKDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "mnee_sdk_call_duration_seconds",
+		Help:    "Time taken by calls to the MNEE SDK, by method.",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"method"})
+
+	SDKErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "mnee_sdk_errors_total",
+		Help: "Failed calls to the MNEE SDK, by method and error class.",
+	}, []string{"method", "class"})
+
+	SDKRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "mnee_sdk_retries_total",
+		Help: "Reads to the MNEE SDK retried because the cosigner seemed down, by method.",
+	}, []string{"method"})
+
+	BreakerState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mnee_circuit_breaker_state",
+		Help: "State of each cosigner's circuit breaker: 0 closed, 1 half-open, 2 open.",
+	}, []string{"cosigner"})
+
+	ReconcilerChecks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "mnee_reconciler_checks_total",
+		Help: "Ticket lookups by the background reconciler, by result (changed, unchanged, not_found or error).",
+	}, []string{"result"})
+
+	TicketsStuck = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "mnee_tickets_stuck_total",
+		Help: "Tickets the reconciler marked stuck for not finishing in time.",
+	})
+
+	CacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "mnee_cache_requests_total",
+		Help: "Cached SDK reads, by method and result (hit or miss).",
+	}, []string{"method", "result"})
+
+	TransferVolume = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "mnee_transfer_volume_atomic_total",
+		Help: "MNEE sent by successful transfers, in atomic units, by SDK method.",
+	}, []string{"method"})
+
+	TicketOutcomes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "mnee_ticket_outcomes_total",
+		Help: "Tickets seen reaching a final status, by outcome (success or failed).",
+	}, []string{"outcome"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPDuration,
+		SDKCalls,
+		SDKDuration,
+		SDKErrors,
+		SDKRetries,
+		BreakerState,
+		ReconcilerChecks,
+		TicketsStuck,
+		CacheRequests,
+		TransferVolume,
+		TicketOutcomes,
+	)
+}
+
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// ticketMemory is how long a finished ticket is remembered, so polling it
+// again does not count its outcome twice.
+const ticketMemory = time.Hour
+
+var (
+	ticketsMutex sync.Mutex
+	ticketsSeen  = make(map[string]time.Time)
+)
+
+// ObserveTicketOutcome counts the outcome of a finished ticket once, however
+// often it is fetched within ticketMemory.
+func ObserveTicketOutcome(ticketID string, outcome string) {
+	ticketsMutex.Lock()
+	defer ticketsMutex.Unlock()
+
+	now := time.Now()
+	if seen, ok := ticketsSeen[ticketID]; ok && now.Sub(seen) < ticketMemory {
+		return
+	}
+	if len(ticketsSeen) >= 10000 {
+		for id, seen := range ticketsSeen {
+			if now.Sub(seen) >= ticketMemory {
+				delete(ticketsSeen, id)
+			}
+		}
+	}
+	ticketsSeen[ticketID] = now
+	TicketOutcomes.WithLabelValues(outcome).Inc()
+}
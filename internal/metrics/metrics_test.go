@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// value returns the sample of metric name whose labels include labels, from
+// Registry as /metrics would serve it.
+func value(t *testing.T, name string, labels map[string]string) float64 {
+	t.Helper()
+
+	families, err := Registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	samples:
+		for _, metric := range family.GetMetric() {
+			for _, pair := range metric.GetLabel() {
+				if want, ok := labels[pair.GetName()]; ok && want != pair.GetValue() {
+					continue samples
+				}
+			}
+			if metric.GetCounter() != nil {
+				return metric.GetCounter().GetValue()
+			}
+			return metric.GetGauge().GetValue()
+		}
+	}
+	return 0
+}
+
+func TestObserveTicketOutcome(t *testing.T) {
+	success := value(t, "mnee_ticket_outcomes_total", map[string]string{"outcome": "success"})
+	failed := value(t, "mnee_ticket_outcomes_total", map[string]string{"outcome": "failed"})
+
+	ObserveTicketOutcome("t-outcome-1", "success")
+	ObserveTicketOutcome("t-outcome-1", "success")
+	ObserveTicketOutcome("t-outcome-2", "failed")
+	ObserveTicketOutcome("t-outcome-3", "success")
+
+	if got := value(t, "mnee_ticket_outcomes_total", map[string]string{"outcome": "success"}) - success; got != 2 {
+		t.Fatalf("counted %v successes, want 2: a ticket polled twice counts once", got)
+	}
+	if got := value(t, "mnee_ticket_outcomes_total", map[string]string{"outcome": "failed"}) - failed; got != 1 {
+		t.Fatalf("counted %v failures, want 1", got)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	HTTPRequests.WithLabelValues("/api/balance/:address", "GET", "200").Inc()
+	HTTPDuration.WithLabelValues("/api/balance/:address", "GET", "200").Observe(0.01)
+	SDKCalls.WithLabelValues("GetBalances", "ok").Inc()
+	SDKErrors.WithLabelValues("GetBalances", "timeout").Inc()
+	BreakerState.WithLabelValues("sandbox").Set(2)
+	ReconcilerChecks.WithLabelValues("unchanged").Inc()
+	TicketsStuck.Inc()
+
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := io.ReadAll(recorder.Body)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("got %d", recorder.Code)
+	}
+
+	for _, want := range []string{
+		`http_requests_total{method="GET",route="/api/balance/:address",status="200"}`,
+		`http_request_duration_seconds_bucket`,
+		`mnee_sdk_calls_total{method="GetBalances",outcome="ok"}`,
+		`mnee_sdk_errors_total{class="timeout",method="GetBalances"}`,
+		`mnee_circuit_breaker_state{cosigner="sandbox"} 2`,
+		`mnee_reconciler_checks_total{result="unchanged"}`,
+		`mnee_tickets_stuck_total`,
+		`go_goroutines`,
+		`process_`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("/metrics lacks %s", want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/auth"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/models" // Import models
+)
+
+const clientContextKey = "auth.client"
+
+// Authenticate requires a client key in the Authorization header, either bare
+// or as a bearer token, and records the client on the context.
+func Authenticate(keys *auth.KeyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := strings.TrimSpace(c.GetHeader("Authorization"))
+		if scheme, token, ok := strings.Cut(key, " "); ok && strings.EqualFold(scheme, "Bearer") {
+			key = strings.TrimSpace(token)
+		}
+
+		if key == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, models.GenericFailureResponse{Success: false, Code: models.CodeUnauthorized, Message: "Missing API key in Authorization header"})
+			return
+		}
+
+		client, ok := keys.Lookup(key)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, models.GenericFailureResponse{Success: false, Code: models.CodeUnauthorized, Message: "Invalid API key"})
+			return
+		}
+
+		c.Set(clientContextKey, client)
+		c.Next()
+	}
+}
+
+// RequireScope rejects clients without scope. Without Authenticate in front
+// of it, for example when authentication is disabled, it lets every request
+// through.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client := CurrentClient(c)
+		if client != nil && !client.HasScope(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, models.GenericFailureResponse{Success: false, Code: models.CodeInsufficientScope, Message: "API key lacks the " + scope + " scope"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// CurrentClient returns the authenticated client, or nil when authentication
+// is disabled.
+func CurrentClient(c *gin.Context) *auth.Client {
+	if value, ok := c.Get(clientContextKey); ok {
+		return value.(*auth.Client)
+	}
+	return nil
+}
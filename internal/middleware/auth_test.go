@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/auth"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/models"
+)
+
+func TestAuthenticate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// revoked-key was removed from API_KEYS.
+	keys, err := auth.ParseKeys("admin:" + auth.HashKey("admin-key") + ":" + strings.Join(auth.Scopes, ",") +
+		";reader:" + auth.HashKey("reader-key") + ":" + auth.ScopeReadBalance)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// One route per group the server mounts, with the scope it requires.
+	routes := []struct {
+		method string
+		path   string
+		scope  string
+	}{
+		{http.MethodGet, "/balance", auth.ScopeReadBalance},
+		{http.MethodPost, "/wallets", auth.ScopeWriteWallets},
+		{http.MethodPost, "/wallets/w_1/export", auth.ScopeExportWallets},
+		{http.MethodGet, "/transfers", auth.ScopeReadHistory},
+		{http.MethodPost, "/transaction/transfer", auth.ScopeWriteTransfer},
+		{http.MethodPost, "/transaction/submit-rawtx", auth.ScopeWriteRawTx},
+	}
+
+	router := gin.New()
+	authed := router.Group("", Authenticate(keys))
+	for _, route := range routes {
+		authed.Handle(route.method, route.path, RequireScope(route.scope), func(c *gin.Context) {
+			c.String(http.StatusOK, CurrentClient(c).ID)
+		})
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		client        string
+		scopes        []string
+	}{
+		{name: "missing key"},
+		{name: "blank key", authorization: "Bearer  "},
+		{name: "unknown key", authorization: "Bearer mallory-key"},
+		{name: "revoked key", authorization: "Bearer revoked-key"},
+		{name: "key hash instead of key", authorization: "Bearer " + auth.HashKey("admin-key")},
+		{name: "every scope", authorization: "Bearer admin-key", client: "admin", scopes: auth.Scopes},
+		{name: "bare key", authorization: "admin-key", client: "admin", scopes: auth.Scopes},
+		{name: "lower case scheme", authorization: "bearer admin-key", client: "admin", scopes: auth.Scopes},
+		{name: "one scope", authorization: "Bearer reader-key", client: "reader", scopes: []string{auth.ScopeReadBalance}},
+	}
+
+	for _, tt := range tests {
+		for _, route := range routes {
+			t.Run(tt.name+" "+route.path, func(t *testing.T) {
+				request := httptest.NewRequest(route.method, route.path, nil)
+				if tt.authorization != "" {
+					request.Header.Set("Authorization", tt.authorization)
+				}
+				recorder := httptest.NewRecorder()
+				router.ServeHTTP(recorder, request)
+
+				status, code := http.StatusOK, ""
+				switch {
+				case tt.client == "":
+					status, code = http.StatusUnauthorized, models.CodeUnauthorized
+				case !slices.Contains(tt.scopes, route.scope):
+					status, code = http.StatusForbidden, models.CodeInsufficientScope
+				}
+				if recorder.Code != status || !strings.Contains(recorder.Body.String(), code) {
+					t.Fatalf("got %d %s, want %d %s", recorder.Code, recorder.Body.String(), status, code)
+				}
+				if status == http.StatusOK && recorder.Body.String() != tt.client {
+					t.Fatalf("authenticated as %q, want %q", recorder.Body.String(), tt.client)
+				}
+			})
+		}
+	}
+}
+
+func TestRequireScopeWithoutAuthentication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/balance", RequireScope(auth.ScopeReadBalance), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/balance", nil))
+	if recorder.Code != http.StatusNoContent {
+		t.Fatalf("got %d, want %d with authentication disabled", recorder.Code, http.StatusNoContent)
+	}
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bufferedWriter holds the response back so CacheHeaders can tag it before it
+// is sent.
+type bufferedWriter struct {
+	gin.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	w.status = code
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.status != 0 || w.body.Len() > 0
+}
+
+// CacheHeaders tags successful responses with an ETag of their body and a
+// private Cache-Control max-age of maxAge, and answers 304 Not Modified when
+// If-None-Match already holds the ETag.
+func CacheHeaders(maxAge time.Duration) gin.HandlerFunc {
+	cacheControl := "private, no-cache"
+	if seconds := int(maxAge.Seconds()); seconds > 0 {
+		cacheControl = "private, max-age=" + strconv.Itoa(seconds)
+	}
+
+	return func(c *gin.Context) {
+		writer := &bufferedWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		// Restored on panics too, so Recovery's response is not held back.
+		defer func() { c.Writer = writer.ResponseWriter }()
+		c.Next()
+
+		out := writer.ResponseWriter
+		status := writer.Status()
+		if status == http.StatusOK {
+			sum := sha256.Sum256(writer.body.Bytes())
+			etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+			out.Header().Set("ETag", etag)
+			out.Header().Set("Cache-Control", cacheControl)
+
+			if etagMatches(c.GetHeader("If-None-Match"), etag) {
+				out.WriteHeader(http.StatusNotModified)
+				out.WriteHeaderNow()
+				return
+			}
+		}
+
+		out.WriteHeader(status)
+		_, _ = out.Write(writer.body.Bytes())
+	}
+}
+
+// etagMatches reports whether the If-None-Match header ifNoneMatch lists
+// etag, comparing weakly as RFC 9110 asks.
+func etagMatches(ifNoneMatch string, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,197 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/idempotency"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/logging"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/models" // Import models
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotencyReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+
+	// maxIdempotentBodyBytes caps the body buffered and hashed for a request
+	// with an Idempotency-Key; signed transactions are far smaller.
+	maxIdempotentBodyBytes = 1 << 20
+
+	skipIdempotencyContextKey = "idempotency.skip"
+)
+
+// SkipIdempotency keeps the response to the request from being stored under
+// its Idempotency-Key, which is freed instead. Dry runs submit nothing, so a
+// later submission reusing their key must not be answered with their preview.
+func SkipIdempotency(c *gin.Context) {
+	c.Set(skipIdempotencyContextKey, true)
+}
+
+// Idempotency replays the stored response when a request repeats the
+// Idempotency-Key of an earlier one with the same method, path and body, and
+// rejects the key with 409 when the request differs. Requests without the
+// header pass through. Successes are stored, and so are failures after which
+// the submission may still have gone through, so a retry never submits a
+// second time. Any other failure, or a response marked with SkipIdempotency,
+// frees the key, so the request can be retried with it once the cause is fixed.
+func Idempotency(store idempotency.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeValidationFailed, Message: "Idempotency-Key must be at most 255 characters"})
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxIdempotentBodyBytes))
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, models.GenericFailureResponse{Success: false, Code: models.CodeRequestTooLarge, Message: "Request body must be at most 1 MiB"})
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeInvalidRequestBody, Message: "Failed to read request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := sha256.New()
+		hash.Write([]byte(c.Request.Method + " " + c.FullPath() + "\n"))
+		hash.Write(body)
+		requestHash := hex.EncodeToString(hash.Sum(nil))
+
+		// Keys are scoped to the client so clients cannot collide or replay
+		// each other's responses.
+		if client := CurrentClient(c); client != nil {
+			key = client.ID + ":" + key
+		}
+
+		record, err := store.Reserve(key, requestHash)
+		switch {
+		case errors.Is(err, idempotency.ErrMismatch):
+			c.AbortWithStatusJSON(http.StatusConflict, models.GenericFailureResponse{Success: false, Code: models.CodeIdempotencyKeyReused, Message: err.Error()})
+			return
+		case errors.Is(err, idempotency.ErrInProgress):
+			c.AbortWithStatusJSON(http.StatusConflict, models.GenericFailureResponse{Success: false, Code: models.CodeIdempotencyInProgress, Message: err.Error()})
+			return
+		case err != nil:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, models.GenericFailureResponse{Success: false, Code: models.CodeInternal, Message: err.Error()})
+			return
+		case record != nil:
+			for name, values := range record.Header {
+				for _, value := range values {
+					c.Writer.Header().Add(name, value)
+				}
+			}
+			c.Header(IdempotencyReplayedHeader, "true")
+			c.Data(record.Status, c.Writer.Header().Get("Content-Type"), record.Body)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		completed := false
+		defer func() {
+			// A panic leaves no response to replay; free the key for a retry.
+			if !completed {
+				if err := store.Release(key); err != nil {
+					logging.FromContext(c.Request.Context()).Error("idempotency: releasing key", "error", err)
+				}
+			}
+		}()
+
+		c.Next()
+
+		if c.GetBool(skipIdempotencyContextKey) {
+			return
+		}
+		response := parseResponse(recorder.body.Bytes())
+		if !replayable(recorder.Status(), response.Code) {
+			return
+		}
+
+		err = store.Complete(key, idempotency.Record{
+			RequestHash: requestHash,
+			Status:      recorder.Status(),
+			Header:      map[string][]string{"Content-Type": {recorder.Header().Get("Content-Type")}},
+			Body:        recorder.body.Bytes(),
+			TicketID:    response.TicketID,
+			Txid:        response.Txid,
+		})
+		if err != nil {
+			logging.FromContext(c.Request.Context()).Error("idempotency: storing response for key", "error", err)
+		}
+		completed = true
+	}
+}
+
+// replayable reports whether a response is stored for replay: a success, or
+// a failure that leaves it unknown whether the cosigner received the
+// submission. Validation errors, rate limits, an open circuit breaker and
+// cosigner rejections all mean nothing was submitted.
+func replayable(status int, code string) bool {
+	if status < http.StatusBadRequest {
+		return true
+	}
+	switch code {
+	case models.CodeUpstreamTimeout, models.CodeUpstreamUnavailable, models.CodeRequestCanceled:
+		return true
+	}
+	return false
+}
+
+type submissionResponse struct {
+	Code     string
+	TicketID string
+	Txid     string
+}
+
+// parseResponse extracts the error code of a failure response, or the ticket
+// ID and txid of a success response.
+func parseResponse(body []byte) submissionResponse {
+	var response struct {
+		Code string `json:"code"`
+		Data struct {
+			TicketId string  `json:"ticketId"`
+			Txid     *string `json:"txid"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return submissionResponse{}
+	}
+
+	parsed := submissionResponse{Code: response.Code, TicketID: response.Data.TicketId}
+	if response.Data.Txid != nil {
+		parsed.Txid = *response.Data.Txid
+	}
+	return parsed
+}
+
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
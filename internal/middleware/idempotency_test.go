@@ -0,0 +1,151 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/idempotency"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/models"
+)
+
+func TestReplayable(t *testing.T) {
+	tests := []struct {
+		status int
+		code   string
+		want   bool
+	}{
+		{status: http.StatusOK, want: true},
+		{status: http.StatusGatewayTimeout, code: models.CodeUpstreamTimeout, want: true},
+		{status: http.StatusBadGateway, code: models.CodeUpstreamUnavailable, want: true},
+		{status: 499, code: models.CodeRequestCanceled, want: true},
+		{status: http.StatusBadRequest, code: models.CodeValidationFailed},
+		{status: http.StatusBadRequest, code: models.CodeUpstreamRejected},
+		{status: http.StatusPaymentRequired, code: models.CodeInsufficientBalance},
+		{status: http.StatusTooManyRequests, code: models.CodeRateLimited},
+		{status: http.StatusServiceUnavailable, code: models.CodeCircuitOpen},
+	}
+
+	for _, tt := range tests {
+		if got := replayable(tt.status, tt.code); got != tt.want {
+			t.Errorf("replayable(%d, %q) = %v, want %v", tt.status, tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestIdempotency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type request struct {
+		body     string
+		status   int
+		replayed bool
+	}
+	tests := []struct {
+		name     string
+		status   int
+		code     string
+		dryRun   bool
+		requests []request
+		calls    int
+	}{
+		{
+			name:   "success is replayed",
+			status: http.StatusOK,
+			requests: []request{
+				{body: `{"a":1}`, status: http.StatusOK},
+				{body: `{"a":1}`, status: http.StatusOK, replayed: true},
+			},
+			calls: 1,
+		},
+		{
+			name:   "different body",
+			status: http.StatusOK,
+			requests: []request{
+				{body: `{"a":1}`, status: http.StatusOK},
+				{body: `{"a":2}`, status: http.StatusConflict},
+			},
+			calls: 1,
+		},
+		{
+			name:   "timeout is replayed",
+			status: http.StatusGatewayTimeout,
+			code:   models.CodeUpstreamTimeout,
+			requests: []request{
+				{body: `{"a":1}`, status: http.StatusGatewayTimeout},
+				{body: `{"a":1}`, status: http.StatusGatewayTimeout, replayed: true},
+			},
+			calls: 1,
+		},
+		{
+			name:   "rejection frees the key",
+			status: http.StatusBadRequest,
+			code:   models.CodeValidationFailed,
+			requests: []request{
+				{body: `{"a":1}`, status: http.StatusBadRequest},
+				{body: `{"a":2}`, status: http.StatusBadRequest},
+			},
+			calls: 2,
+		},
+		{
+			name:   "body too large",
+			status: http.StatusOK,
+			requests: []request{
+				{body: `{"a":"` + strings.Repeat("x", maxIdempotentBodyBytes) + `"}`, status: http.StatusRequestEntityTooLarge},
+				{body: `{"a":1}`, status: http.StatusOK},
+			},
+			calls: 1,
+		},
+		{
+			name:   "dry run frees the key",
+			status: http.StatusOK,
+			dryRun: true,
+			requests: []request{
+				{body: `{"a":1}`, status: http.StatusOK},
+				{body: `{"a":1}`, status: http.StatusOK},
+			},
+			calls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := idempotency.NewMemoryStore(time.Hour, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			calls := 0
+			router := gin.New()
+			router.POST("/transfer", Idempotency(store), func(c *gin.Context) {
+				calls++
+				if tt.dryRun {
+					SkipIdempotency(c)
+				}
+				if tt.status >= http.StatusBadRequest {
+					c.JSON(tt.status, models.GenericFailureResponse{Success: false, Code: tt.code})
+					return
+				}
+				c.JSON(tt.status, gin.H{"success": true, "data": gin.H{"txid": "abc"}})
+			})
+
+			for i, r := range tt.requests {
+				req := httptest.NewRequest(http.MethodPost, "/transfer", strings.NewReader(r.body))
+				req.Header.Set(IdempotencyKeyHeader, "key")
+				recorder := httptest.NewRecorder()
+				router.ServeHTTP(recorder, req)
+
+				replayed := recorder.Header().Get(IdempotencyReplayedHeader) == "true"
+				if recorder.Code != r.status || replayed != r.replayed {
+					t.Fatalf("request %d: got %d, replayed %v; want %d, replayed %v", i, recorder.Code, replayed, r.status, r.replayed)
+				}
+			}
+			if calls != tt.calls {
+				t.Fatalf("handler ran %d times, want %d", calls, tt.calls)
+			}
+		})
+	}
+}
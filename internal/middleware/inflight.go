@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/lifecycle"
+)
+
+const inFlightContextKey = "lifecycle.request"
+
+// InFlight records the request in tracker until it completes, with the
+// ticket in its path if it has one, so shutdown can report it.
+func InFlight(tracker *lifecycle.Tracker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		request := lifecycle.Request{
+			Method:         c.Request.Method,
+			Path:           c.Request.URL.Path,
+			IdempotencyKey: c.GetHeader(IdempotencyKeyHeader),
+			TicketID:       c.Param("ticketId"),
+			StartedAt:      time.Now(),
+		}
+		if client := CurrentClient(c); client != nil {
+			request.ClientID = client.ID
+		}
+
+		id := tracker.Begin(request)
+		defer tracker.End(id)
+
+		c.Set(inFlightContextKey, inFlightRequest{tracker: tracker, id: id})
+		c.Next()
+	}
+}
+
+type inFlightRequest struct {
+	tracker *lifecycle.Tracker
+	id      uint64
+}
+
+// SetInFlightTicket records the ticket the request created, if InFlight
+// tracks it.
+func SetInFlightTicket(c *gin.Context, ticketID string) {
+	if value, ok := c.Get(inFlightContextKey); ok {
+		request := value.(inFlightRequest)
+		request.tracker.SetTicket(request.id, ticketID)
+	}
+}
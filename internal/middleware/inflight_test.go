@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/lifecycle"
+)
+
+func TestInFlight(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tracker := lifecycle.NewTracker()
+	var during []lifecycle.Request
+	router := gin.New()
+	router.GET("/status/:ticketId", InFlight(tracker), func(c *gin.Context) {
+		during = tracker.Snapshot()
+		c.Status(http.StatusOK)
+	})
+	router.POST("/transfer", InFlight(tracker), func(c *gin.Context) {
+		SetInFlightTicket(c, "t-created")
+		during = tracker.Snapshot()
+		c.Status(http.StatusOK)
+	})
+	router.POST("/untracked", func(c *gin.Context) {
+		SetInFlightTicket(c, "t-ignored")
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   lifecycle.Request
+	}{
+		{name: "ticket in path", method: http.MethodGet, path: "/status/t-1", want: lifecycle.Request{Method: http.MethodGet, Path: "/status/t-1", TicketID: "t-1", IdempotencyKey: "key-1"}},
+		{name: "ticket set by handler", method: http.MethodPost, path: "/transfer", want: lifecycle.Request{Method: http.MethodPost, Path: "/transfer", TicketID: "t-created", IdempotencyKey: "key-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			during = nil
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set(IdempotencyKeyHeader, "key-1")
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			if len(during) != 1 {
+				t.Fatalf("tracked %+v during the request, want one request", during)
+			}
+			got := during[0]
+			if got.Method != tt.want.Method || got.Path != tt.want.Path || got.TicketID != tt.want.TicketID || got.IdempotencyKey != tt.want.IdempotencyKey || got.StartedAt.IsZero() {
+				t.Fatalf("tracked %+v, want %+v", got, tt.want)
+			}
+			if after := tracker.Snapshot(); len(after) != 0 {
+				t.Fatalf("still tracking %+v after the request", after)
+			}
+		})
+	}
+
+	// Without InFlight, SetInFlightTicket does nothing.
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/untracked", nil))
+}
+
+// TestInFlightDrain checks the shutdown sequence: a server shutting down
+// waits for the transfer in flight, which stays tracked until it completes,
+// and one that outlives the timeout is still tracked to be reported.
+func TestInFlightDrain(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		hold      time.Duration
+		timeout   time.Duration
+		abandoned bool
+	}{
+		{name: "drained", hold: 50 * time.Millisecond, timeout: 5 * time.Second},
+		{name: "abandoned", hold: 5 * time.Second, timeout: 50 * time.Millisecond, abandoned: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := lifecycle.NewTracker()
+			started := make(chan struct{})
+			release := make(chan struct{})
+			router := gin.New()
+			router.POST("/transfer", InFlight(tracker), func(c *gin.Context) {
+				SetInFlightTicket(c, "t-1")
+				close(started)
+				select {
+				case <-time.After(tt.hold):
+				case <-release:
+				}
+				c.Status(http.StatusOK)
+			})
+
+			server := httptest.NewServer(router)
+			defer server.Close()
+			defer close(release)
+
+			go func() {
+				response, err := http.Post(server.URL+"/transfer", "application/json", nil)
+				if err == nil {
+					response.Body.Close()
+				}
+			}()
+			<-started
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+			err := server.Config.Shutdown(ctx)
+
+			remaining := tracker.Snapshot()
+			if tt.abandoned {
+				if err == nil || len(remaining) != 1 || remaining[0].TicketID != "t-1" {
+					t.Fatalf("Shutdown = %v with %+v in flight, want a timeout with ticket t-1 still tracked", err, remaining)
+				}
+				return
+			}
+			if err != nil || len(remaining) != 0 {
+				t.Fatalf("Shutdown = %v with %+v in flight, want a clean drain", err, remaining)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/logging"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/models" // Import models
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDPattern bounds the request IDs taken from callers, so they cannot
+// inject anything into logs.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestID takes the caller's X-Request-ID, or generates one, echoes it in
+// the response and tags the request's log lines with it.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(id) {
+			id = newRequestID()
+		}
+		c.Header(RequestIDHeader, id)
+
+		logger := slog.Default().With("request_id", id)
+		c.Request = c.Request.WithContext(logging.WithLogger(c.Request.Context(), logger))
+		c.Next()
+	}
+}
+
+func newRequestID() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// maxLoggedErrorBody bounds how much of an error response AccessLog keeps
+// to find its code and message.
+const maxLoggedErrorBody = 4096
+
+// errorBodyWriter keeps the start of error responses.
+type errorBodyWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *errorBodyWriter) Write(data []byte) (int, error) {
+	w.keep(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *errorBodyWriter) WriteString(s string) (int, error) {
+	w.keep([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *errorBodyWriter) keep(data []byte) {
+	if w.Status() >= http.StatusBadRequest && w.body.Len() < maxLoggedErrorBody {
+		w.body.Write(data[:min(len(data), maxLoggedErrorBody-w.body.Len())])
+	}
+}
+
+// AccessLog writes one line per request. It logs the path without the query
+// string, and of the body only the code and message of error responses,
+// which the logger redacts like everything else, e.g. input echoed back by
+// a bind error.
+func AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		started := time.Now()
+		writer := &errorBodyWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		status := c.Writer.Status()
+		attrs := []any{
+			"method", c.Request.Method,
+			"route", c.FullPath(),
+			"path", c.Request.URL.Path,
+			"status", status,
+			"duration_ms", time.Since(started).Milliseconds(),
+			"bytes", c.Writer.Size(),
+			"client_ip", c.ClientIP(),
+		}
+		if client := CurrentClient(c); client != nil {
+			attrs = append(attrs, "client_id", client.ID)
+		}
+		var failure models.GenericFailureResponse
+		if writer.body.Len() > 0 && json.Unmarshal(writer.body.Bytes(), &failure) == nil && failure.Code != "" {
+			attrs = append(attrs, "code", failure.Code, "message", failure.Message)
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, "errors", c.Errors.String())
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+		logging.FromContext(c.Request.Context()).Log(c.Request.Context(), level, "request", attrs...)
+	}
+}
+
+// Recovery turns a panic into a logged 500 instead of gin's plain-text dump.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(nil, func(c *gin.Context, recovered any) {
+		logging.FromContext(c.Request.Context()).Error("panic serving request", "path", c.Request.URL.Path, "panic", recovered)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.GenericFailureResponse{Success: false, Code: models.CodeInternal, Message: "Internal server error"})
+	})
+}
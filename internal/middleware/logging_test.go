@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	primitives "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/gin-gonic/gin"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/logging"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/models"
+)
+
+func TestAccessLogRedacts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	privateKey, err := primitives.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wif := privateKey.Wif()
+	clientKey := "mnee_" + strings.Repeat("3f", 32)
+
+	tests := []struct {
+		name    string
+		path    string
+		message string
+		header  string
+		secret  string
+		kept    string
+	}{
+		{name: "WIF in message", path: "/transfer", message: "invalid key " + wif, secret: wif, kept: "invalid key"},
+		{name: "body echoed by bind error", path: "/transfer", message: `bad body {"wifs":["` + wif + `"],"callbackSecret":"hunter2"}`, secret: "hunter2", kept: `wifs`},
+		{name: "authorization header in error", path: "/transfer", message: "upstream said Authorization: Bearer " + clientKey, secret: clientKey},
+		{name: "query string", path: "/transfer?auth_token=sdk-key-123", message: "failed", secret: "sdk-key-123", kept: `"path":"/transfer"`},
+		{name: "API key header", path: "/transfer", message: "failed", header: clientKey, secret: clientKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := slog.Default()
+			t.Cleanup(func() { slog.SetDefault(previous) })
+			var logs bytes.Buffer
+			if err := logging.Init(&logs, "json", "info"); err != nil {
+				t.Fatal(err)
+			}
+
+			router := gin.New()
+			router.Use(RequestID(), AccessLog())
+			router.POST("/transfer", func(c *gin.Context) {
+				c.JSON(http.StatusBadRequest, models.GenericFailureResponse{Success: false, Code: models.CodeValidationFailed, Message: tt.message})
+			})
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.header)
+			}
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			line := logs.String()
+			if !strings.Contains(line, `"msg":"request"`) {
+				t.Fatalf("no access log line in %q", line)
+			}
+			if strings.Contains(line, tt.secret) {
+				t.Fatalf("access log %q still holds the secret", line)
+			}
+			if !strings.Contains(line, tt.kept) {
+				t.Fatalf("access log %q, want %q kept", line, tt.kept)
+			}
+		})
+	}
+}
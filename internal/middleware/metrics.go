@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/metrics"
+)
+
+// Metrics records the count and latency of requests by gin route, so paths
+// with IDs in them share a series. Requests no route matched are counted
+// under "unmatched".
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		started := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		metrics.HTTPRequests.WithLabelValues(route, c.Request.Method, status).Inc()
+		metrics.HTTPDuration.WithLabelValues(route, c.Request.Method, status).Observe(time.Since(started).Seconds())
+	}
+}
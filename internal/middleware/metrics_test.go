@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/metrics"
+)
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Metrics())
+	router.GET("/metrics-test/:address", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/metrics-test/fail", func(c *gin.Context) { c.Status(http.StatusBadGateway) })
+
+	tests := []struct {
+		method string
+		path   string
+		labels []string
+	}{
+		{method: http.MethodGet, path: "/metrics-test/1abc", labels: []string{"/metrics-test/:address", "GET", "200"}},
+		{method: http.MethodGet, path: "/metrics-test/1def", labels: []string{"/metrics-test/:address", "GET", "200"}},
+		{method: http.MethodPost, path: "/metrics-test/fail", labels: []string{"/metrics-test/fail", "POST", "502"}},
+		{method: http.MethodGet, path: "/no/such/route", labels: []string{"unmatched", "GET", "404"}},
+	}
+
+	for _, tt := range tests {
+		before := counterValue(t, tt.labels)
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.path, nil))
+		if got := counterValue(t, tt.labels) - before; got != 1 {
+			t.Fatalf("%s %s counted %v times under %v, want once", tt.method, tt.path, got, tt.labels)
+		}
+	}
+}
+
+// counterValue reads http_requests_total for labels route, method and
+// status from the registry.
+func counterValue(t *testing.T, labels []string) float64 {
+	t.Helper()
+
+	families, err := metrics.Registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"route": labels[0], "method": labels[1], "status": labels[2]}
+	for _, family := range families {
+		if family.GetName() != "http_requests_total" {
+			continue
+		}
+	samples:
+		for _, metric := range family.GetMetric() {
+			for _, pair := range metric.GetLabel() {
+				if want[pair.GetName()] != pair.GetValue() {
+					continue samples
+				}
+			}
+			return metric.GetCounter().GetValue()
+		}
+	}
+	return 0
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/models" // Import models
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/ratelimit"
+)
+
+// RateLimitClient limits requests per authenticated client, or per IP when
+// authentication is disabled. It must come after Authenticate.
+func RateLimitClient(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := "ip:" + c.ClientIP()
+		if client := CurrentClient(c); client != nil {
+			key = "client:" + client.ID
+		}
+		if ok, wait := limiter.Allow(key); !ok {
+			AbortRateLimited(c, wait, "Too many requests from this client")
+			return
+		}
+		c.Next()
+	}
+}
+
+// RateLimitIP limits requests per client IP.
+func RateLimitIP(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ok, wait := limiter.Allow(c.ClientIP()); !ok {
+			AbortRateLimited(c, wait, "Too many requests from this IP")
+			return
+		}
+		c.Next()
+	}
+}
+
+// AbortRateLimited answers 429 with a Retry-After of wait, in whole seconds.
+func AbortRateLimited(c *gin.Context, wait time.Duration, message string) {
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, models.GenericFailureResponse{Success: false, Code: models.CodeRateLimited, Message: message})
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/logging"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/models" // Import models
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
+)
+
+// Tenant runs the request against the authenticated client's tenant, if it
+// has one. It must come after Authenticate.
+func Tenant(registry *services.TenantRegistry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client := CurrentClient(c)
+		if client == nil {
+			c.Next()
+			return
+		}
+
+		mneeClient, err := registry.Client(client.ID)
+		if err != nil {
+			logging.FromContext(c.Request.Context()).Error("Failed to initialize MNEE SDK", "client_id", client.ID, "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, models.GenericFailureResponse{Success: false, Code: models.CodeInternal, Message: "Failed to initialize the MNEE SDK for this client"})
+			return
+		}
+
+		c.Request = c.Request.WithContext(services.WithClient(c.Request.Context(), mneeClient))
+		c.Next()
+	}
+}
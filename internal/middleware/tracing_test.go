@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	recorder := tracetest.NewSpanRecorder()
+	previousTracer, previousPropagator := tracing.Tracer, otel.GetTextMapPropagator()
+	t.Cleanup(func() {
+		tracing.Tracer = previousTracer
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+	tracing.Tracer = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	const parentTrace = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	var handlerSpan trace.SpanContext
+	router := gin.New()
+	router.Use(Tracing())
+	router.GET("/status/:ticketId", func(c *gin.Context) {
+		handlerSpan = trace.SpanContextFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+	router.POST("/transfer", func(c *gin.Context) {
+		c.Status(http.StatusBadGateway)
+	})
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		traceparent string
+		spanName    string
+		status      int
+		ticketID    string
+		failed      bool
+	}{
+		{name: "ticket route", method: http.MethodGet, path: "/status/t-1", spanName: "GET /status/:ticketId", status: http.StatusOK, ticketID: "t-1"},
+		{name: "continues the caller's trace", method: http.MethodGet, path: "/status/t-2", traceparent: "00-" + parentTrace + "-00f067aa0ba902b7-01", spanName: "GET /status/:ticketId", status: http.StatusOK, ticketID: "t-2"},
+		{name: "server error", method: http.MethodPost, path: "/transfer", spanName: "POST /transfer", status: http.StatusBadGateway, failed: true},
+		{name: "unmatched", method: http.MethodGet, path: "/nowhere", spanName: "GET", status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(recorder.Ended())
+			handlerSpan = trace.SpanContext{}
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.traceparent != "" {
+				req.Header.Set("traceparent", tt.traceparent)
+			}
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			ended := recorder.Ended()
+			if len(ended) != before+1 {
+				t.Fatalf("ended %d spans, want 1", len(ended)-before)
+			}
+			span := ended[len(ended)-1]
+
+			if span.Name() != tt.spanName || span.SpanKind() != trace.SpanKindServer {
+				t.Fatalf("got span %q of kind %v, want server span %q", span.Name(), span.SpanKind(), tt.spanName)
+			}
+			attrs := make(map[attribute.Key]attribute.Value)
+			for _, attr := range span.Attributes() {
+				attrs[attr.Key] = attr.Value
+			}
+			if got := attrs["http.response.status_code"].AsInt64(); got != int64(tt.status) {
+				t.Fatalf("status attribute = %d, want %d", got, tt.status)
+			}
+			if got := attrs["mnee.ticket_id"].AsString(); got != tt.ticketID {
+				t.Fatalf("ticket attribute = %q, want %q", got, tt.ticketID)
+			}
+			if failed := span.Status().Code == codes.Error; failed != tt.failed {
+				t.Fatalf("span status %v, want failed %v", span.Status(), tt.failed)
+			}
+			if tt.traceparent != "" && span.SpanContext().TraceID().String() != parentTrace {
+				t.Fatalf("trace %s does not continue %s", span.SpanContext().TraceID(), parentTrace)
+			}
+			if tt.ticketID != "" && handlerSpan.SpanID() != span.SpanContext().SpanID() {
+				t.Fatal("the handler's context does not carry the request span")
+			}
+		})
+	}
+}
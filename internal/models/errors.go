@@ -0,0 +1,42 @@
+package models
+
+// Error codes returned in GenericFailureResponse.Code. They are stable and
+// safe for clients to switch on; messages are not.
+const (
+	CodeInvalidRequestBody    = "INVALID_REQUEST_BODY"
+	CodeRequestTooLarge       = "REQUEST_TOO_LARGE"
+	CodeValidationFailed      = "VALIDATION_FAILED"
+	CodeInvalidAddress        = "INVALID_ADDRESS"
+	CodeInvalidAmount         = "INVALID_AMOUNT"
+	CodeInvalidWif            = "INVALID_WIF"
+	CodeInvalidRawTx          = "INVALID_RAW_TX"
+	CodeInsufficientBalance   = "INSUFFICIENT_BALANCE"
+	CodeCosignerForbidden     = "COSIGNER_FORBIDDEN"
+	CodeInvalidConfig         = "INVALID_COSIGNER_CONFIG"
+	CodeEmptyTicketID         = "EMPTY_TICKET_ID"
+	CodeUpstreamRejected      = "UPSTREAM_REJECTED"
+	CodeUpstreamUnavailable   = "UPSTREAM_UNAVAILABLE"
+	CodeUpstreamTimeout       = "UPSTREAM_TIMEOUT"
+	CodeCircuitOpen           = "UPSTREAM_CIRCUIT_OPEN"
+	CodeRequestCanceled       = "REQUEST_CANCELED"
+	CodeIdempotencyKeyReused  = "IDEMPOTENCY_KEY_REUSED"
+	CodeIdempotencyInProgress = "IDEMPOTENCY_KEY_IN_PROGRESS"
+	CodeUnauthorized          = "UNAUTHORIZED"
+	CodeInsufficientScope     = "INSUFFICIENT_SCOPE"
+	CodeTicketNotFound        = "TICKET_NOT_FOUND"
+	CodeWalletNotFound        = "WALLET_NOT_FOUND"
+	CodeWalletExists          = "WALLET_EXISTS"
+	CodeWalletsDisabled       = "WALLETS_DISABLED"
+	CodeWalletNotEmpty        = "WALLET_NOT_EMPTY"
+	CodeWalletNotExported     = "WALLET_NOT_EXPORTED"
+	CodeWalletExportDisabled  = "WALLET_EXPORT_DISABLED"
+	CodeAccountNotFound       = "ACCOUNT_NOT_FOUND"
+	CodeAccountExists         = "ACCOUNT_EXISTS"
+	CodeInvalidExtendedKey    = "INVALID_EXTENDED_KEY"
+	CodeLedgerDisabled        = "LEDGER_DISABLED"
+	CodeTooManyAddresses      = "TOO_MANY_ADDRESSES"
+	CodeRateLimited           = "RATE_LIMITED"
+	CodeShuttingDown          = "SHUTTING_DOWN"
+	CodeNotReady              = "NOT_READY"
+	CodeInternal              = "INTERNAL_ERROR"
+)
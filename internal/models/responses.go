@@ -1,6 +1,11 @@
 package models
 
-import "github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/types"
+import (
+	"time"
+
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/ledger"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/types"
+)
 
 type GetBalanceSuccessResponse struct {
 	Success bool                 `json:"success"`
@@ -22,6 +27,7 @@ type GetConfigSuccessResponse struct {
 
 type GenericFailureResponse struct {
 	Success bool   `json:"success" example:"false"`
+	Code    string `json:"code" example:"INSUFFICIENT_BALANCE"`
 	Message string `json:"message" example:"error description"`
 }
 
@@ -44,13 +50,26 @@ type GetTicketSuccessResponse struct {
 	Data    types.Ticket `json:"data"`
 }
 
+type RecipientAmount struct {
+	Address      string `json:"address" example:"1G6CB3Ch4zFkPmuhZzEyChQmrQPfi86qk3"`
+	AtomicAmount uint64 `json:"atomicAmount" example:"29000"`
+	Amount       string `json:"amount" example:"0.29"`
+}
+
+type TransferResultWrapper struct {
+	Txid       *string           `json:"txid,omitempty"`
+	Txhex      *string           `json:"txhex,omitempty"`
+	Recipients []RecipientAmount `json:"recipients,omitempty"`
+}
+
 type TransferSyncSuccessResponse struct {
-	Success bool                      `json:"success" example:"true"`
-	Data    types.TransferResponseDTO `json:"data"`
+	Success bool                  `json:"success" example:"true"`
+	Data    TransferResultWrapper `json:"data"`
 }
 
 type TicketIdWrapper struct {
-	TicketId string `json:"ticketId" example:"KKJS-..."`
+	TicketId   string            `json:"ticketId" example:"KKJS-..."`
+	Recipients []RecipientAmount `json:"recipients,omitempty"`
 }
 
 type TransferAsyncSuccessResponse struct {
@@ -59,10 +78,177 @@ type TransferAsyncSuccessResponse struct {
 }
 
 type RawTxWrapper struct {
-	RawTxHex string `json:"rawTxHex" example:"02000000..."`
+	RawTxHex   string            `json:"rawTxHex" example:"02000000..."`
+	Recipients []RecipientAmount `json:"recipients,omitempty"`
 }
 
 type PartialSignSuccessResponse struct {
 	Success bool         `json:"success" example:"true"`
 	Data    RawTxWrapper `json:"data"`
 }
+
+type TransferInputSummary struct {
+	Outpoint      string `json:"outpoint" example:"9a3c..._0"`
+	Address       string `json:"address" example:"1G6CB3Ch4zFkPmuhZzEyChQmrQPfi86qk3"`
+	AtomicAmount  uint64 `json:"atomicAmount" example:"100000"`
+	Amount        string `json:"amount" example:"1"`
+	Satoshis      uint64 `json:"satoshis" example:"1"`
+	LockingScript string `json:"lockingScript,omitempty" example:"0063036f7264..."`
+	Preimage      string `json:"preimage,omitempty" example:"01000000..."`
+	Sighash       string `json:"sighash,omitempty" example:"5f2c..."`
+}
+
+type TransferOutputSummary struct {
+	Vout         int    `json:"vout" example:"0"`
+	Address      string `json:"address" example:"1G6CB3Ch4zFkPmuhZzEyChQmrQPfi86qk3"`
+	AtomicAmount uint64 `json:"atomicAmount" example:"29000"`
+	Amount       string `json:"amount" example:"0.29"`
+	Kind         string `json:"kind" example:"recipient" enums:"recipient,fee,change"`
+}
+
+type BuildTransactionWrapper struct {
+	RawTxHex     string                  `json:"rawTxHex" example:"01000000..."`
+	SighashFlags uint32                  `json:"sighashFlags" example:"193"`
+	Inputs       []TransferInputSummary  `json:"inputs"`
+	Outputs      []TransferOutputSummary `json:"outputs"`
+	AtomicFee    uint64                  `json:"atomicFee" example:"100"`
+	Fee          string                  `json:"fee" example:"0.001"`
+	AtomicChange uint64                  `json:"atomicChange" example:"70900"`
+	Change       string                  `json:"change" example:"0.709"`
+}
+
+type BuildTransactionSuccessResponse struct {
+	Success bool                    `json:"success" example:"true"`
+	Data    BuildTransactionWrapper `json:"data"`
+}
+
+type FeeQuoteWrapper struct {
+	Recipients      []RecipientAmount `json:"recipients,omitempty"`
+	AtomicAmount    uint64            `json:"atomicAmount" example:"29000"`
+	Amount          string            `json:"amount" example:"0.29"`
+	AtomicFee       uint64            `json:"atomicFee" example:"100"`
+	Fee             string            `json:"fee" example:"0.001"`
+	AtomicTotal     uint64            `json:"atomicTotal" example:"29100"`
+	Total           string            `json:"total" example:"0.291"`
+	Tier            *types.Fee        `json:"tier,omitempty"`
+	SourceAddresses []string          `json:"sourceAddresses,omitempty"`
+	AtomicBalance   *uint64           `json:"atomicBalance,omitempty" example:"100000"`
+	Balance         *string           `json:"balance,omitempty" example:"1"`
+	Sufficient      *bool             `json:"sufficient,omitempty" example:"true"`
+}
+
+type FeeQuoteSuccessResponse struct {
+	Success bool            `json:"success" example:"true"`
+	Data    FeeQuoteWrapper `json:"data"`
+}
+
+// DryRunWrapper previews a signed transfer that was not submitted. Txid is the
+// id of the transaction as signed here; the cosigner's signature changes the
+// id of the transaction that is finally broadcast.
+type DryRunWrapper struct {
+	DryRun       bool                    `json:"dryRun" example:"true"`
+	Txid         string                  `json:"txid" example:"9a3c..."`
+	RawTxHex     string                  `json:"rawTxHex" example:"01000000..."`
+	Recipients   []RecipientAmount       `json:"recipients"`
+	Inputs       []TransferInputSummary  `json:"inputs"`
+	Outputs      []TransferOutputSummary `json:"outputs"`
+	AtomicFee    uint64                  `json:"atomicFee" example:"100"`
+	Fee          string                  `json:"fee" example:"0.001"`
+	AtomicChange uint64                  `json:"atomicChange" example:"70900"`
+	Change       string                  `json:"change" example:"0.709"`
+}
+
+type DryRunSuccessResponse struct {
+	Success bool          `json:"success" example:"true"`
+	Data    DryRunWrapper `json:"data"`
+}
+
+type SuccessResponse struct {
+	Success bool `json:"success" example:"true"`
+}
+
+type WalletSummary struct {
+	ID            string     `json:"id" example:"w_5f2c9a..."`
+	Label         string     `json:"label,omitempty" example:"payouts"`
+	Address       string     `json:"address" example:"1G6CB3Ch4zFkPmuhZzEyChQmrQPfi86qk3"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	Generated     bool       `json:"generated,omitempty" example:"true"`
+	ExportedAt    *time.Time `json:"exportedAt,omitempty"`
+	AtomicBalance *uint64    `json:"atomicBalance,omitempty" example:"100000"`
+	Balance       *string    `json:"balance,omitempty" example:"1"`
+}
+
+type WalletSuccessResponse struct {
+	Success bool          `json:"success" example:"true"`
+	Data    WalletSummary `json:"data"`
+}
+
+type WalletExport struct {
+	ID         string    `json:"id" example:"w_5f2c9a..."`
+	Address    string    `json:"address" example:"1G6CB3Ch4zFkPmuhZzEyChQmrQPfi86qk3"`
+	Wif        string    `json:"wif" example:"L1dRKo..."`
+	ExportedAt time.Time `json:"exportedAt"`
+}
+
+type WalletExportSuccessResponse struct {
+	Success bool         `json:"success" example:"true"`
+	Data    WalletExport `json:"data"`
+}
+
+type ListWalletsSuccessResponse struct {
+	Success bool            `json:"success" example:"true"`
+	Data    []WalletSummary `json:"data"`
+}
+
+type AccountAddress struct {
+	Address string `json:"address" example:"1G6CB3Ch4zFkPmuhZzEyChQmrQPfi86qk3"`
+	Chain   uint32 `json:"chain" example:"0"`
+	Index   uint32 `json:"index" example:"4"`
+}
+
+type AccountSummary struct {
+	ID                 string           `json:"id" example:"a_9d41e7..."`
+	Label              string           `json:"label,omitempty" example:"treasury"`
+	Xpub               string           `json:"xpub" example:"xpub6C..."`
+	GapLimit           int              `json:"gapLimit" example:"20"`
+	ReceiveNext        uint32           `json:"receiveNext" example:"5"`
+	ChangeNext         uint32           `json:"changeNext" example:"2"`
+	NextReceiveAddress AccountAddress   `json:"nextReceiveAddress"`
+	NextChangeAddress  AccountAddress   `json:"nextChangeAddress"`
+	Addresses          []AccountAddress `json:"addresses,omitempty"`
+	CreatedAt          time.Time        `json:"createdAt"`
+	ScannedAt          *time.Time       `json:"scannedAt,omitempty"`
+}
+
+type AccountSuccessResponse struct {
+	Success bool           `json:"success" example:"true"`
+	Data    AccountSummary `json:"data"`
+}
+
+type ListAccountsSuccessResponse struct {
+	Success bool             `json:"success" example:"true"`
+	Data    []AccountSummary `json:"data"`
+}
+
+type AccountBalanceWrapper struct {
+	AtomicBalance uint64                 `json:"atomicBalance" example:"100000"`
+	Balance       string                 `json:"balance" example:"1"`
+	Addresses     []types.BalanceDataDTO `json:"addresses"`
+}
+
+type AccountBalanceSuccessResponse struct {
+	Success bool                  `json:"success" example:"true"`
+	Data    AccountBalanceWrapper `json:"data"`
+}
+
+type TransfersPage struct {
+	Transfers []ledger.Transfer `json:"transfers"`
+	Page      int               `json:"page" example:"1"`
+	Size      int               `json:"size" example:"20"`
+	Total     int               `json:"total" example:"42"`
+}
+
+type ListTransfersSuccessResponse struct {
+	Success bool          `json:"success" example:"true"`
+	Data    TransfersPage `json:"data"`
+}
@@ -0,0 +1,96 @@
+// Package ratelimit keeps a token bucket per key, such as an API client, a
+// source address or an IP.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Limiter allows perMinute requests per key, with bursts of up to perMinute.
+// A nil Limiter allows everything.
+type Limiter struct {
+	limit rate.Limit
+	burst int
+
+	mutex     sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// SourceAddresses limits the transfers sent from each address. It is nil,
+// allowing everything, until main configures it.
+var SourceAddresses *Limiter
+
+// New returns a Limiter allowing perMinute requests per key, or nil when
+// perMinute is not positive.
+func New(perMinute int) *Limiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &Limiter{
+		limit:   rate.Limit(float64(perMinute) / 60),
+		burst:   perMinute,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Allow takes a token from the bucket of every key, or from none of them. When
+// one is empty it returns false and how long until it has a token again.
+func (l *Limiter) Allow(keys ...string) (bool, time.Duration) {
+	if l == nil {
+		return true, 0
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+	l.sweep(now)
+
+	reservations := make([]*rate.Reservation, 0, len(keys))
+	var wait time.Duration
+	for _, key := range keys {
+		b, ok := l.buckets[key]
+		if !ok {
+			b = &bucket{limiter: rate.NewLimiter(l.limit, l.burst)}
+			l.buckets[key] = b
+		}
+		b.lastSeen = now
+
+		reservation := b.limiter.ReserveN(now, 1)
+		reservations = append(reservations, reservation)
+		wait = max(wait, reservation.DelayFrom(now))
+	}
+
+	if wait > 0 {
+		for _, reservation := range reservations {
+			reservation.CancelAt(now)
+		}
+		return false, wait
+	}
+	return true, 0
+}
+
+// sweep drops the buckets that have been idle long enough to be full again,
+// which behave exactly like new ones.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+
+	refill := time.Duration(float64(l.burst) / float64(l.limit) * float64(time.Second))
+	for key, b := range l.buckets {
+		if now.Sub(b.lastSeen) > refill {
+			delete(l.buckets, key)
+		}
+	}
+}
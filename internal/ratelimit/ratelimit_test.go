@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiterAllow(t *testing.T) {
+	type call struct {
+		keys []string
+		want bool
+	}
+	tests := []struct {
+		name      string
+		perMinute int
+		calls     []call
+	}{
+		{
+			name:      "disabled",
+			perMinute: 0,
+			calls:     []call{{[]string{"a"}, true}, {[]string{"a"}, true}, {[]string{"a"}, true}},
+		},
+		{
+			name:      "burst then limited",
+			perMinute: 2,
+			calls:     []call{{[]string{"a"}, true}, {[]string{"a"}, true}, {[]string{"a"}, false}},
+		},
+		{
+			name:      "keys are separate",
+			perMinute: 1,
+			calls:     []call{{[]string{"a"}, true}, {[]string{"b"}, true}, {[]string{"a"}, false}},
+		},
+		{
+			name:      "all keys or none",
+			perMinute: 1,
+			calls: []call{
+				{[]string{"a"}, true},
+				// a is empty, so b must not be charged either.
+				{[]string{"b", "a"}, false},
+				{[]string{"b"}, true},
+				{[]string{"b"}, false},
+			},
+		},
+		{
+			name:      "no keys",
+			perMinute: 1,
+			calls:     []call{{nil, true}, {nil, true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := New(tt.perMinute)
+			for i, c := range tt.calls {
+				ok, wait := limiter.Allow(c.keys...)
+				if ok != c.want {
+					t.Fatalf("call %d with %v: got %v, want %v", i, c.keys, ok, c.want)
+				}
+				if ok && wait != 0 || !ok && (wait <= 0 || wait > time.Minute) {
+					t.Fatalf("call %d with %v: got wait %v", i, c.keys, wait)
+				}
+			}
+		})
+	}
+}
+
+func TestLimiterSweep(t *testing.T) {
+	limiter := New(60)
+	limiter.Allow("a")
+	limiter.Allow("b")
+
+	limiter.buckets["a"].lastSeen = time.Now().Add(-2 * time.Minute)
+	limiter.lastSweep = time.Now().Add(-time.Minute)
+	limiter.Allow("b")
+
+	if _, ok := limiter.buckets["a"]; ok {
+		t.Fatal("an idle, refilled bucket was kept")
+	}
+	if _, ok := limiter.buckets["b"]; !ok {
+		t.Fatal("an active bucket was dropped")
+	}
+}
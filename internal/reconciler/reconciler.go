@@ -0,0 +1,251 @@
+// Package reconciler follows the tickets of asynchronous submissions in the
+// ledger until they finish, so their status no longer depends on a client
+// polling for it.
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/ledger"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/logging"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/metrics"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/webhooks"
+)
+
+const (
+	checkTimeout = 10 * time.Second
+
+	// checkers caps the tickets looked up at once.
+	checkers = 8
+
+	// batchSize caps the tickets loaded per round. The newest come first, so
+	// a pile of old stuck tickets cannot starve fresh ones.
+	batchSize = 500
+)
+
+// Config sets how often tickets are looked up. A ticket is first checked
+// Interval after the previous round, and the wait doubles up to MaxInterval
+// while its status stays the same. Tickets still pending or broadcasting
+// StuckAfter after submission are marked stuck; those submitted more than
+// MaxAge ago are no longer checked.
+type Config struct {
+	Interval    time.Duration
+	MaxInterval time.Duration
+	StuckAfter  time.Duration
+	MaxAge      time.Duration
+}
+
+type schedule struct {
+	next     time.Time
+	interval time.Duration
+	seen     mnee.TicketStatus
+}
+
+// Reconciler looks up unfinished tickets with GetTicket in the background.
+// The lookups update the ledger through the client, and every status change
+// is published to the webhook relay.
+type Reconciler struct {
+	config Config
+	store  ledger.Store
+
+	// roundTimeout bounds a round, so a slow cosigner delays lookups rather
+	// than piling rounds up.
+	roundTimeout time.Duration
+
+	// schedules is only used by the goroutine Start runs. Each schedule is
+	// only updated by the lookup of its ticket.
+	schedules map[string]*schedule
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Instance is nil when the ledger or the reconciler is disabled.
+var Instance *Reconciler
+
+func New(config Config, store ledger.Store) *Reconciler {
+	if config.MaxInterval < config.Interval {
+		config.MaxInterval = config.Interval
+	}
+	return &Reconciler{
+		config:       config,
+		store:        store,
+		roundTimeout: max(config.Interval, checkTimeout),
+		schedules:    make(map[string]*schedule),
+	}
+}
+
+// Start runs the reconciler until Close.
+func (r *Reconciler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.done = make(chan struct{})
+
+	go func() {
+		defer close(r.done)
+		r.run(ctx)
+	}()
+}
+
+// Close stops the reconciler and waits for the lookups in progress, or until
+// ctx is done.
+func (r *Reconciler) Close(ctx context.Context) error {
+	if r == nil || r.cancel == nil {
+		return nil
+	}
+
+	r.cancel()
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *Reconciler) run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		r.reconcile(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// reconcile checks the unfinished tickets that are due, up to checkers at
+// once, and forgets the schedules of tickets that finished. Tickets not
+// reached before roundTimeout stay due for the next round.
+func (r *Reconciler) reconcile(ctx context.Context) {
+	transfers, err := r.store.Unfinished(ctx, time.Now().Add(-r.config.MaxAge), batchSize)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.Error("Failed to load unfinished transfers from the ledger", "error", err)
+		}
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.roundTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, checkers)
+	schedules := make(map[string]*schedule, len(transfers))
+	for _, transfer := range transfers {
+		// A ticket is looked up once per round, however many entries share it.
+		if _, ok := schedules[transfer.TicketID]; ok {
+			continue
+		}
+		s, ok := r.schedules[transfer.TicketID]
+		if !ok {
+			s = &schedule{interval: r.config.Interval}
+		}
+		schedules[transfer.TicketID] = s
+
+		if time.Now().Before(s.next) || ctx.Err() != nil {
+			continue
+		}
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			r.check(ctx, transfer, s)
+		}()
+	}
+	wg.Wait()
+	r.schedules = schedules
+}
+
+func (r *Reconciler) check(ctx context.Context, transfer ledger.Transfer, s *schedule) {
+	// SDK calls log the ticket ID themselves.
+	clientLogger := slog.Default().With("client_id", transfer.ClientID)
+	logger := clientLogger.With("ticket_id", transfer.TicketID)
+
+	client, err := services.Tenants.Client(transfer.ClientID)
+	if err != nil {
+		logger.Error("Failed to initialize MNEE SDK for the reconciler", "error", err)
+		r.backOff(s, false)
+		return
+	}
+
+	checkCtx, cancel := context.WithTimeout(logging.WithLogger(ctx, clientLogger), checkTimeout)
+	ticket, err := client.GetTicket(checkCtx, transfer.TicketID)
+	cancel()
+
+	switch {
+	case errors.Is(err, services.ErrTicketNotFound):
+		// The cosigner has not picked the ticket up yet.
+		metrics.ReconcilerChecks.WithLabelValues("not_found").Inc()
+		ticket = nil
+	case err != nil:
+		if ctx.Err() != nil {
+			return
+		}
+		metrics.ReconcilerChecks.WithLabelValues("error").Inc()
+		logger.Warn("Failed to look up ticket", "error", err)
+		r.backOff(s, false)
+		return
+	case ticket.Status != s.seen:
+		metrics.ReconcilerChecks.WithLabelValues("changed").Inc()
+		webhooks.Instance.Publish(ticket)
+	default:
+		metrics.ReconcilerChecks.WithLabelValues("unchanged").Inc()
+	}
+
+	var seen mnee.TicketStatus
+	if ticket != nil {
+		seen = ticket.Status
+	}
+	r.backOff(s, seen != s.seen)
+	s.seen = seen
+
+	if ticket != nil && services.TicketFinished(ticket) {
+		return
+	}
+	if transfer.Status != ledger.StatusStuck && time.Since(transfer.CreatedAt) >= r.config.StuckAfter {
+		r.markStuck(ctx, logger, transfer, ticket)
+	}
+}
+
+// backOff schedules the next check of a ticket: after Interval when it
+// changed, otherwise after twice the last wait, up to MaxInterval. A tenth
+// of jitter keeps tickets submitted together from being checked together.
+func (r *Reconciler) backOff(s *schedule, changed bool) {
+	if changed {
+		s.interval = r.config.Interval
+	} else {
+		s.interval = min(2*s.interval, r.config.MaxInterval)
+	}
+	s.next = time.Now().Add(s.interval + time.Duration(rand.Int64N(int64(s.interval/10)+1)))
+}
+
+func (r *Reconciler) markStuck(ctx context.Context, logger *slog.Logger, transfer ledger.Transfer, ticket *mnee.Ticket) {
+	if err := r.store.UpdateTicket(ctx, transfer.TicketID, ledger.TicketUpdate{Status: ledger.StatusStuck}); err != nil {
+		logger.Error("Failed to mark ticket stuck in the ledger", "error", err)
+		return
+	}
+
+	if ticket == nil {
+		ticket = &mnee.Ticket{ID: &transfer.TicketID, Errors: []string{}}
+	}
+	metrics.TicketsStuck.Inc()
+	logger.Warn("Ticket is stuck", "status", ticket.Status, "submitted_at", transfer.CreatedAt)
+	webhooks.Instance.PublishStuck(ticket)
+}
@@ -0,0 +1,263 @@
+package reconciler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/ledger"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/services"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/webhooks"
+)
+
+func openTestStore(t *testing.T) *ledger.SQLiteStore {
+	t.Helper()
+
+	store, err := ledger.OpenSQLite(filepath.Join(t.TempDir(), "ledger.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func record(t *testing.T, store ledger.Store, ticketID string, status ledger.Status, createdAt time.Time) {
+	t.Helper()
+
+	err := store.Record(t.Context(), ledger.Transfer{
+		ID:           "tr_" + ticketID,
+		ClientID:     "alice",
+		Kind:         ledger.KindTransfer,
+		Async:        true,
+		Status:       status,
+		TicketID:     ticketID,
+		Sources:      []string{"source"},
+		Recipients:   []ledger.Recipient{{Address: "recipient", AtomicAmount: 1000}},
+		AtomicAmount: 1000,
+		CreatedAt:    createdAt,
+		UpdatedAt:    createdAt,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// useClient makes client the MNEE client of every ledger entry.
+func useClient(t *testing.T, client services.MneeClient) {
+	previous := services.Instance
+	t.Cleanup(func() { services.Instance = previous })
+	services.Instance = client
+}
+
+// subscribe points webhooks.Instance at a subscriber, and returns a function
+// that waits for the deliveries so far and returns them as "type ticketId".
+func subscribe(t *testing.T) func() []string {
+	var (
+		mutex  sync.Mutex
+		events []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event webhooks.Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("decode event: %v", err)
+		}
+		mutex.Lock()
+		events = append(events, event.Type+" "+event.Data.TicketID)
+		mutex.Unlock()
+	}))
+	t.Cleanup(server.Close)
+
+	previous := webhooks.Instance
+	t.Cleanup(func() { webhooks.Instance = previous })
+	relay := webhooks.NewRelay(webhooks.Config{Subscribers: []string{server.URL}})
+	webhooks.Instance = relay
+
+	return func() []string {
+		t.Helper()
+		ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+		defer cancel()
+		if err := relay.Close(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		got := slices.Clone(events)
+		events = nil
+		slices.Sort(got)
+		return got
+	}
+}
+
+func TestBackOff(t *testing.T) {
+	r := New(Config{Interval: time.Second, MaxInterval: 4 * time.Second}, nil)
+
+	tests := []struct {
+		name     string
+		interval time.Duration
+		changed  bool
+		want     time.Duration
+	}{
+		{name: "unchanged doubles", interval: time.Second, want: 2 * time.Second},
+		{name: "unchanged doubles again", interval: 2 * time.Second, want: 4 * time.Second},
+		{name: "capped at MaxInterval", interval: 4 * time.Second, want: 4 * time.Second},
+		{name: "changed resets", interval: 4 * time.Second, changed: true, want: time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &schedule{interval: tt.interval}
+			before := time.Now()
+			r.backOff(s, tt.changed)
+			after := time.Now()
+
+			if s.interval != tt.want {
+				t.Fatalf("interval = %v, want %v", s.interval, tt.want)
+			}
+			// Up to a tenth of jitter is added to the wait.
+			if s.next.Before(before.Add(tt.want)) || s.next.After(after.Add(tt.want+tt.want/10)) {
+				t.Fatalf("next check in %v, want between %v and %v", s.next.Sub(before), tt.want, tt.want+tt.want/10)
+			}
+		})
+	}
+
+	if r := New(Config{Interval: time.Minute, MaxInterval: time.Second}, nil); r.config.MaxInterval != time.Minute {
+		t.Fatalf("MaxInterval = %v, want it raised to Interval", r.config.MaxInterval)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	store := openTestStore(t)
+	events := subscribe(t)
+	client := services.NewFakeClient()
+	useClient(t, client)
+
+	now := time.Now().UTC()
+	late := now.Add(-time.Hour)
+	for _, entry := range []struct {
+		ticketID  string
+		status    ledger.Status
+		createdAt time.Time
+		ticket    mnee.TicketStatus
+	}{
+		{ticketID: "fresh", status: ledger.StatusPending, createdAt: now, ticket: mnee.BROADCASTING},
+		{ticketID: "late", status: ledger.StatusPending, createdAt: late},
+		{ticketID: "late-broadcasting", status: ledger.StatusBroadcasting, createdAt: late, ticket: mnee.BROADCASTING},
+		{ticketID: "late-success", status: ledger.StatusPending, createdAt: late, ticket: mnee.SUCCESS},
+		{ticketID: "already-stuck", status: ledger.StatusStuck, createdAt: late},
+	} {
+		record(t, store, entry.ticketID, entry.status, entry.createdAt)
+		if entry.ticket != "" {
+			if err := client.PutTicket(mnee.Ticket{ID: &entry.ticketID, Status: entry.ticket, Errors: []string{}}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	r := New(Config{Interval: time.Minute, MaxInterval: time.Hour, StuckAfter: 30 * time.Minute, MaxAge: 24 * time.Hour}, store)
+	r.reconcile(t.Context())
+
+	// Status changes are published, and unfinished tickets past StuckAfter
+	// are marked stuck once, with a ticket.stuck event.
+	want := []string{
+		webhooks.EventTicketStuck + " late",
+		webhooks.EventTicketStuck + " late-broadcasting",
+		webhooks.EventTicketUpdated + " fresh",
+		webhooks.EventTicketUpdated + " late-broadcasting",
+		webhooks.EventTicketUpdated + " late-success",
+	}
+	if got := events(); !slices.Equal(got, want) {
+		t.Fatalf("events = %q, want %q", got, want)
+	}
+
+	transfers, _, err := store.List(t.Context(), ledger.Filter{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantStatus := map[string]ledger.Status{
+		"fresh":             ledger.StatusPending,
+		"late":              ledger.StatusStuck,
+		"late-broadcasting": ledger.StatusStuck,
+		"late-success":      ledger.StatusPending,
+		"already-stuck":     ledger.StatusStuck,
+	}
+	for _, transfer := range transfers {
+		if transfer.Status != wantStatus[transfer.TicketID] {
+			t.Errorf("ticket %s is %s, want %s", transfer.TicketID, transfer.Status, wantStatus[transfer.TicketID])
+		}
+	}
+
+	// Nothing is due again before Interval.
+	r.reconcile(t.Context())
+	if got := events(); len(got) != 0 {
+		t.Fatalf("second round published %q", got)
+	}
+	if len(r.schedules) != len(wantStatus) {
+		t.Fatalf("got %d schedules, want %d", len(r.schedules), len(wantStatus))
+	}
+}
+
+// blockingClient answers GetTicket only once its context is done, and
+// records how many lookups ran at once.
+type blockingClient struct {
+	*services.FakeClient
+
+	mutex     sync.Mutex
+	active    int
+	maxActive int
+}
+
+func (b *blockingClient) GetTicket(ctx context.Context, ticketID string) (*mnee.Ticket, error) {
+	b.mutex.Lock()
+	b.active++
+	b.maxActive = max(b.maxActive, b.active)
+	b.mutex.Unlock()
+
+	<-ctx.Done()
+
+	b.mutex.Lock()
+	b.active--
+	b.mutex.Unlock()
+	return nil, ctx.Err()
+}
+
+func TestReconcileBounds(t *testing.T) {
+	store := openTestStore(t)
+	client := &blockingClient{FakeClient: services.NewFakeClient()}
+	useClient(t, client)
+
+	tickets := 3 * checkers
+	for i := range tickets {
+		record(t, store, "t"+string(rune('a'+i)), ledger.StatusPending, time.Now().UTC())
+	}
+
+	r := New(Config{Interval: time.Minute, MaxInterval: time.Hour, StuckAfter: time.Hour, MaxAge: time.Hour}, store)
+	r.roundTimeout = 100 * time.Millisecond
+
+	start := time.Now()
+	r.reconcile(t.Context())
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("round took %v, want it cut off after %v", elapsed, r.roundTimeout)
+	}
+
+	if client.maxActive != checkers {
+		t.Fatalf("%d lookups ran at once, want %d", client.maxActive, checkers)
+	}
+
+	// Lookups cut off by the deadline leave their tickets due.
+	if len(r.schedules) != tickets {
+		t.Fatalf("got %d schedules, want %d", len(r.schedules), tickets)
+	}
+	for ticketID, s := range r.schedules {
+		if !s.next.IsZero() {
+			t.Fatalf("ticket %s is next checked at %v, want it still due", ticketID, s.next)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+	"unsafe"
+
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+)
+
+// isBaseURL reports whether env names a custom cosigner URL rather than one of
+// the hosted MNEE environments.
+func isBaseURL(env string) bool {
+	u, err := url.Parse(env)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// newMneeInstanceWithURL builds an SDK client that talks to baseURL, e.g. a
+// local cmd/fakemnee server. The SDK only knows its two hosted URLs and keeps
+// the base URL, and the HTTP client a RoundTripper could redirect, unexported,
+// so the field is rewritten after construction; if a future SDK release
+// renames it this fails at startup instead of silently talking to the
+// sandbox. TestSDKBaseURL pins the field.
+func newMneeInstanceWithURL(baseURL string, apiKey string) (*mnee.MNEE, error) {
+	instance, err := mnee.NewMneeInstance(mnee.EnvSandbox, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	field, err := sdkBaseURLField(instance)
+	if err != nil {
+		return nil, err
+	}
+	reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem().SetString(strings.TrimRight(baseURL, "/"))
+
+	return instance, nil
+}
+
+// sdkBaseURL returns the base URL instance sends its requests to.
+func sdkBaseURL(instance *mnee.MNEE) (string, error) {
+	field, err := sdkBaseURLField(instance)
+	if err != nil {
+		return "", err
+	}
+	return field.String(), nil
+}
+
+func sdkBaseURLField(instance *mnee.MNEE) (reflect.Value, error) {
+	field := reflect.ValueOf(instance).Elem().FieldByName("mneeURL")
+	if !field.IsValid() || field.Kind() != reflect.String {
+		return reflect.Value{}, errors.New("mnee sdk does not support a custom base URL")
+	}
+	return field, nil
+}
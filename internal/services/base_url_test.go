@@ -0,0 +1,85 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+)
+
+// TestSDKBaseURL pins the SDK's unexported base URL field, which custom
+// cosigner URLs and GetTicket depend on.
+func TestSDKBaseURL(t *testing.T) {
+	tests := []struct {
+		env  string
+		want string
+	}{
+		{env: mnee.EnvMain, want: "https://proxy-api.mnee.net"},
+		{env: mnee.EnvSandbox, want: "https://sandbox-proxy-api.mnee.net"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			instance, err := mnee.NewMneeInstance(tt.env, "key-1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, err := sdkBaseURL(instance); err != nil || got != tt.want {
+				t.Fatalf("sdkBaseURL = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewMneeInstanceWithURL(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.URL.Path+" "+r.URL.Query().Get("auth_token"))
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/config":
+			w.Write([]byte(`{"decimals":5}`))
+		case "/v2/ticket":
+			w.Write([]byte(`{"id":"` + r.URL.Query().Get("ticketID") + `","status":"SUCCESS"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	// A trailing slash is trimmed so paths do not start with "//".
+	instance, err := newMneeInstanceWithURL(server.URL+"/", "key-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := newSDKClient(instance, "key-1", NetworkTestnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.GetConfig(t.Context()); err != nil {
+		t.Fatalf("GetConfig: %v", err)
+	}
+	ticket, err := client.GetTicket(t.Context(), "t-1")
+	if err != nil {
+		t.Fatalf("GetTicket: %v", err)
+	}
+	if ticket.ID == nil || *ticket.ID != "t-1" {
+		t.Fatalf("GetTicket = %+v, want ticket t-1", ticket)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"/v1/config key-1", "/v2/ticket key-1"}
+	if len(requests) != len(want) || requests[0] != want[0] || requests[1] != want[1] {
+		t.Fatalf("server got %q, want %q", requests, want)
+	}
+}
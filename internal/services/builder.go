@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+
+	primitives "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	sighash "github.com/bsv-blockchain/go-sdk/transaction/sighash"
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+)
+
+// TransferSighashFlags are the flags MNEE transfers are signed with, leaving
+// room for the cosigner to add its own signature.
+const TransferSighashFlags = sighash.ForkID | sighash.All | sighash.AnyOneCanPay
+
+const (
+	OutputRecipient = "recipient"
+	OutputFee       = "fee"
+	OutputChange    = "change"
+)
+
+// TransferInput is an MNEE UTXO spent by a transfer. Preimage and Sighash are
+// only set for unsigned transfers.
+type TransferInput struct {
+	Outpoint      string
+	Address       string
+	AtomicAmount  uint64
+	Satoshis      uint64
+	LockingScript string
+	Preimage      string
+	Sighash       string
+}
+
+// TransferOutput is an MNEE output created by a transfer.
+type TransferOutput struct {
+	Vout         int
+	Address      string
+	AtomicAmount uint64
+	Kind         string
+}
+
+type TransferPlan struct {
+	Tx      *transaction.Transaction
+	Inputs  []TransferInput
+	Outputs []TransferOutput
+	Fee     uint64
+	Change  uint64
+}
+
+// BuildUnsignedTransfer selects UTXOs owned by sourceAddresses and builds an
+// unsigned MNEE transfer to mneeTransferDTO, paying the fee to the cosigner's
+// fee address and any change to changeAddress. Each input carries the sighash
+// preimage an external signer needs; its unlocking script is
+// <signature+TransferSighashFlags> <compressed public key>.
+func BuildUnsignedTransfer(ctx context.Context, client MneeClient, sourceAddresses []string,
+	mneeTransferDTO []mnee.TransferMneeDTO, changeAddress string) (*TransferPlan, error) {
+
+	config, err := client.GetConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Approver == nil || config.FeeAddress == nil || config.Fees == nil || config.TokenId == nil {
+		return nil, mnee.ErrInvalidConfig
+	}
+
+	approver, err := primitives.PublicKeyFromString(*config.Approver)
+	if err != nil {
+		return nil, err
+	}
+
+	txos, err := client.GetUnspentTxos(WithoutCache(ctx), sourceAddresses)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make(map[string]bool, len(sourceAddresses))
+	for _, address := range sourceAddresses {
+		sources[address] = true
+	}
+
+	selected, totalInputAmount, fee, err := selectInputs(txos, func(owner string) bool {
+		return sources[owner]
+	}, mneeTransferDTO, config.Fees)
+	if err != nil {
+		return nil, err
+	}
+
+	// selectInputs checked that the total fits and is covered by the inputs.
+	totalTransferAmt, _ := TotalAmount(mneeTransferDTO)
+
+	plan := &TransferPlan{Tx: transaction.NewTransaction(), Fee: fee}
+	for _, dto := range mneeTransferDTO {
+		if err := plan.addOutput(config, approver, dto.Address, dto.Amount, OutputRecipient); err != nil {
+			return nil, err
+		}
+	}
+
+	if fee > 0 {
+		if err := plan.addOutput(config, approver, *config.FeeAddress, fee, OutputFee); err != nil {
+			return nil, err
+		}
+	}
+
+	plan.Change = totalInputAmount - totalTransferAmt - fee
+	if plan.Change > 0 {
+		if err := plan.addOutput(config, approver, changeAddress, plan.Change, OutputChange); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, txo := range selected {
+		scriptBytes, err := base64.StdEncoding.DecodeString(*txo.Script)
+		if err != nil {
+			return nil, err
+		}
+
+		lockingScript := hex.EncodeToString(scriptBytes)
+		err = plan.Tx.AddInputFrom(*txo.Txid, uint32(txo.Vout), lockingScript, uint64(txo.Satoshis), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		plan.Inputs = append(plan.Inputs, TransferInput{
+			Outpoint:      fmt.Sprintf("%s_%d", *txo.Txid, txo.Vout),
+			Address:       txo.Owners[0],
+			AtomicAmount:  txo.Data.Bsv21.Amt,
+			Satoshis:      uint64(txo.Satoshis),
+			LockingScript: lockingScript,
+		})
+	}
+
+	// Preimages commit to every output, so they are computed once the
+	// transaction is complete.
+	for i := range plan.Inputs {
+		preimage, err := plan.Tx.CalcInputPreimage(uint32(i), TransferSighashFlags)
+		if err != nil {
+			return nil, err
+		}
+
+		hash, err := plan.Tx.CalcInputSignatureHash(uint32(i), TransferSighashFlags)
+		if err != nil {
+			return nil, err
+		}
+
+		plan.Inputs[i].Preimage = hex.EncodeToString(preimage)
+		plan.Inputs[i].Sighash = hex.EncodeToString(hash)
+	}
+
+	return plan, nil
+}
+
+func (p *TransferPlan) addOutput(config *mnee.SystemConfig, approver *primitives.PublicKey, address string, amount uint64, kind string) error {
+	if err := inscribeTransfer(p.Tx, config, approver, address, amount); err != nil {
+		return err
+	}
+
+	p.Outputs = append(p.Outputs, TransferOutput{
+		Vout:         len(p.Tx.Outputs) - 1,
+		Address:      address,
+		AtomicAmount: amount,
+		Kind:         kind,
+	})
+	return nil
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	primitives "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+)
+
+func testAddress(t *testing.T) string {
+	t.Helper()
+
+	_, address := testKey(t)
+	return address
+}
+
+// testKey returns a fresh WIF and its mainnet address.
+func testKey(t *testing.T) (string, string) {
+	t.Helper()
+
+	privateKey, err := primitives.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	address, err := script.NewAddressFromPublicKey(privateKey.PubKey(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return privateKey.Wif(), address.AddressString
+}
+
+func TestBuildUnsignedTransfer(t *testing.T) {
+	source := testAddress(t)
+	recipient := testAddress(t)
+
+	type output struct {
+		kind   string
+		amount uint64
+	}
+	tests := []struct {
+		name    string
+		dtos    []mnee.TransferMneeDTO
+		inputs  int
+		outputs []output
+		err     error
+	}{
+		{
+			name:    "with change",
+			dtos:    []mnee.TransferMneeDTO{{Address: recipient, Amount: 10000}},
+			inputs:  1,
+			outputs: []output{{OutputRecipient, 10000}, {OutputFee, 100}, {OutputChange, 39900}},
+		},
+		{
+			name:    "exact",
+			dtos:    []mnee.TransferMneeDTO{{Address: recipient, Amount: 49900}},
+			inputs:  1,
+			outputs: []output{{OutputRecipient, 49900}, {OutputFee, 100}},
+		},
+		{
+			name:    "two inputs",
+			dtos:    []mnee.TransferMneeDTO{{Address: recipient, Amount: 60000}},
+			inputs:  2,
+			outputs: []output{{OutputRecipient, 60000}, {OutputFee, 100}, {OutputChange, 14900}},
+		},
+		{
+			name: "insufficient",
+			dtos: []mnee.TransferMneeDTO{{Address: recipient, Amount: 75000}},
+			err:  mnee.ErrInsufficientMneeBalance,
+		},
+		{
+			name: "overflow",
+			dtos: []mnee.TransferMneeDTO{{Address: recipient, Amount: math.MaxUint64}, {Address: recipient, Amount: 1}},
+			err:  ErrAmountOverflow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewFakeClient()
+			for _, amount := range []uint64{50000, 25000} {
+				if err := client.Fund(source, amount); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			plan, err := BuildUnsignedTransfer(t.Context(), client, []string{source}, tt.dtos, source)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+
+			if len(plan.Inputs) != tt.inputs || len(plan.Tx.Inputs) != tt.inputs {
+				t.Fatalf("got %d inputs, want %d", len(plan.Inputs), tt.inputs)
+			}
+			for _, input := range plan.Inputs {
+				if input.Address != source || input.Preimage == "" || input.Sighash == "" {
+					t.Fatalf("incomplete input %+v", input)
+				}
+			}
+
+			if len(plan.Outputs) != len(tt.outputs) || len(plan.Tx.Outputs) != len(tt.outputs) {
+				t.Fatalf("got outputs %+v, want %+v", plan.Outputs, tt.outputs)
+			}
+			for i, want := range tt.outputs {
+				if got := plan.Outputs[i]; got.Vout != i || got.Kind != want.kind || got.AtomicAmount != want.amount {
+					t.Fatalf("output %d is %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
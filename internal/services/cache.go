@@ -0,0 +1,284 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/metrics"
+)
+
+// Cache stores encoded SDK responses. MemoryCache is the default; other
+// backends, e.g. one shared by several instances, implement the same two
+// methods.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration)
+}
+
+// CacheTTLs is how long the responses of each cached method are kept. A zero
+// TTL disables caching for that method.
+type CacheTTLs struct {
+	Config   time.Duration
+	Balances time.Duration
+	Utxos    time.Duration
+}
+
+// ResponseCache and ResponseCacheTTLs apply to the clients NewClient builds.
+// A nil ResponseCache disables caching.
+var (
+	ResponseCache     Cache
+	ResponseCacheTTLs CacheTTLs
+)
+
+// MemoryCache is a Cache in process memory.
+type MemoryCache struct {
+	mutex     sync.Mutex
+	entries   map[string]memoryEntry
+	lastSweep time.Time
+}
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]memoryEntry)}
+}
+
+func (m *MemoryCache) Get(_ context.Context, key string) ([]byte, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (m *MemoryCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := time.Now()
+	if now.Sub(m.lastSweep) >= time.Minute {
+		m.lastSweep = now
+		for k, entry := range m.entries {
+			if now.After(entry.expiresAt) {
+				delete(m.entries, k)
+			}
+		}
+	}
+	m.entries[key] = memoryEntry{value: value, expiresAt: now.Add(ttl)}
+}
+
+type noCacheKey struct{}
+
+// WithoutCache returns a copy of ctx whose reads skip the cache, for callers
+// that build transactions from what they read.
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCacheKey{}, true)
+}
+
+// offlineNamespaces tells offline clients apart: each has its own ledger.
+var offlineNamespaces atomic.Uint64
+
+// cachingClient answers config, balance and UTXO reads from cache. Entries
+// belong to one environment and API key, so a tenant whose key the cosigner
+// refuses is never answered with another tenant's reads. They are keyed on a
+// generation per address, shared by every client of the environment, which
+// transfers through any of them bump so the addresses involved are read
+// afresh.
+type cachingClient struct {
+	MneeClient
+	cache     Cache
+	ttls      CacheTTLs
+	namespace string
+	scope     string
+}
+
+func withCache(client MneeClient, cache Cache, ttls CacheTTLs, namespace string, apiKey string) MneeClient {
+	if cache == nil {
+		return client
+	}
+
+	// The key itself stays out of the cache, which another backend may
+	// share or expose.
+	sum := sha256.Sum256([]byte(apiKey))
+	scope := namespace + "|" + hex.EncodeToString(sum[:8])
+	return cachingClient{MneeClient: client, cache: cache, ttls: ttls, namespace: namespace, scope: scope}
+}
+
+func (c cachingClient) generationKey(address string) string {
+	return c.namespace + "|gen|" + address
+}
+
+// key is the cache key of method called with args on addresses, at their
+// current generations.
+func (c cachingClient) key(ctx context.Context, method string, addresses []string, args ...string) string {
+	var b strings.Builder
+	b.WriteString(method)
+	for _, arg := range args {
+		b.WriteString("|" + arg)
+	}
+	for _, address := range addresses {
+		generation, _ := c.cache.Get(ctx, c.generationKey(address))
+		b.WriteString("|" + address + "@" + string(generation))
+	}
+
+	// Hash the key so it stays short however many addresses are read.
+	sum := sha256.Sum256([]byte(b.String()))
+	return c.scope + "|" + method + "|" + hex.EncodeToString(sum[:])
+}
+
+// invalidate bumps the generation of addresses. Generations outlive every
+// entry, so an entry can never become current again.
+func (c cachingClient) invalidate(ctx context.Context, addresses []string) {
+	generation := []byte(strconv.FormatInt(time.Now().UnixNano(), 36))
+	ttl := max(24*time.Hour, 2*max(c.ttls.Config, c.ttls.Balances, c.ttls.Utxos))
+	for _, address := range addresses {
+		c.cache.Set(ctx, c.generationKey(address), generation, ttl)
+	}
+}
+
+// cached returns the cached result for key, or calls fetch and caches its
+// result for ttl.
+func cached[T any](ctx context.Context, c cachingClient, method string, key string, ttl time.Duration, fetch func() (T, error)) (T, error) {
+	if ttl <= 0 || ctx.Value(noCacheKey{}) != nil {
+		return fetch()
+	}
+
+	if data, ok := c.cache.Get(ctx, key); ok {
+		var result T
+		if err := json.Unmarshal(data, &result); err == nil {
+			metrics.CacheRequests.WithLabelValues(method, "hit").Inc()
+			return result, nil
+		}
+	}
+	metrics.CacheRequests.WithLabelValues(method, "miss").Inc()
+
+	result, err := fetch()
+	if err != nil {
+		return result, err
+	}
+	if data, err := json.Marshal(result); err == nil {
+		c.cache.Set(ctx, key, data, ttl)
+	}
+	return result, nil
+}
+
+func (c cachingClient) GetConfig(ctx context.Context) (*mnee.SystemConfig, error) {
+	return cached(ctx, c, "GetConfig", c.key(ctx, "GetConfig", nil), c.ttls.Config, func() (*mnee.SystemConfig, error) {
+		return c.MneeClient.GetConfig(ctx)
+	})
+}
+
+func (c cachingClient) GetBalances(ctx context.Context, addresses []string) ([]mnee.BalanceDataDTO, error) {
+	return cached(ctx, c, "GetBalances", c.key(ctx, "GetBalances", addresses), c.ttls.Balances, func() ([]mnee.BalanceDataDTO, error) {
+		return c.MneeClient.GetBalances(ctx, addresses)
+	})
+}
+
+func (c cachingClient) GetUnspentTxos(ctx context.Context, addresses []string) ([]mnee.MneeTxo, error) {
+	return cached(ctx, c, "GetUnspentTxos", c.key(ctx, "GetUnspentTxos", addresses), c.ttls.Utxos, func() ([]mnee.MneeTxo, error) {
+		return c.MneeClient.GetUnspentTxos(ctx, addresses)
+	})
+}
+
+func (c cachingClient) GetPaginatedUnspentTxos(ctx context.Context, addresses []string, page int, size int) ([]mnee.MneeTxo, error) {
+	key := c.key(ctx, "GetPaginatedUnspentTxos", addresses, strconv.Itoa(page), strconv.Itoa(size))
+	return cached(ctx, c, "GetPaginatedUnspentTxos", key, c.ttls.Utxos, func() ([]mnee.MneeTxo, error) {
+		return c.MneeClient.GetPaginatedUnspentTxos(ctx, addresses, page, size)
+	})
+}
+
+func (c cachingClient) SynchronousTransfer(ctx context.Context, wifs []string, mneeTransferDTO []mnee.TransferMneeDTO, withTxos bool,
+	mneeTxos []mnee.MneeTxo) (*mnee.TransferResponseDTO, error) {
+	response, err := c.MneeClient.SynchronousTransfer(ctx, wifs, mneeTransferDTO, withTxos, mneeTxos)
+	if err == nil {
+		c.invalidate(ctx, transferAddresses(wifs, mneeTransferDTO))
+	}
+	return response, err
+}
+
+func (c cachingClient) AsynchronousTransfer(ctx context.Context, wifs []string, mneeTransferDTO []mnee.TransferMneeDTO, withTxos bool,
+	mneeTxos []mnee.MneeTxo, callbackURL *string, callbackSecret *string) (*string, error) {
+	ticketID, err := c.MneeClient.AsynchronousTransfer(ctx, wifs, mneeTransferDTO, withTxos, mneeTxos, callbackURL, callbackSecret)
+	if err == nil {
+		c.invalidate(ctx, transferAddresses(wifs, mneeTransferDTO))
+	}
+	return ticketID, err
+}
+
+func (c cachingClient) SubmitRawTxSync(ctx context.Context, rawTxHex string) (*mnee.TransferResponseDTO, error) {
+	response, err := c.MneeClient.SubmitRawTxSync(ctx, rawTxHex)
+	if err == nil {
+		c.invalidate(ctx, rawTxAddresses(rawTxHex))
+	}
+	return response, err
+}
+
+func (c cachingClient) SubmitRawTxAsync(ctx context.Context, rawTxHex string, callbackURL *string, callbackSecret *string) (*string, error) {
+	ticketID, err := c.MneeClient.SubmitRawTxAsync(ctx, rawTxHex, callbackURL, callbackSecret)
+	if err == nil {
+		c.invalidate(ctx, rawTxAddresses(rawTxHex))
+	}
+	return ticketID, err
+}
+
+// GetTicket and PollTicket invalidate the addresses of a finished ticket's
+// transaction again, as an asynchronous transfer only changes balances once
+// it is broadcast.
+func (c cachingClient) GetTicket(ctx context.Context, ticketID string) (*mnee.Ticket, error) {
+	ticket, err := c.MneeClient.GetTicket(ctx, ticketID)
+	c.invalidateTicket(ctx, ticket)
+	return ticket, err
+}
+
+func (c cachingClient) PollTicket(ctx context.Context, ticketID string, pollingInterval time.Duration) (*mnee.Ticket, error) {
+	ticket, err := c.MneeClient.PollTicket(ctx, ticketID, pollingInterval)
+	c.invalidateTicket(ctx, ticket)
+	return ticket, err
+}
+
+func (c cachingClient) invalidateTicket(ctx context.Context, ticket *mnee.Ticket) {
+	if ticket != nil && ticket.TxHex != nil && TicketFinished(ticket) {
+		c.invalidate(ctx, rawTxAddresses(*ticket.TxHex))
+	}
+}
+
+// transferAddresses returns the addresses a transfer spends from and sends to.
+func transferAddresses(wifs []string, mneeTransferDTO []mnee.TransferMneeDTO) []string {
+	addresses := WifAddresses(wifs)
+	for _, dto := range mneeTransferDTO {
+		addresses = append(addresses, dto.Address)
+	}
+	return addresses
+}
+
+// rawTxAddresses returns the owners of a transaction's MNEE outputs, which
+// include the sender's change, and the signers of its inputs.
+func rawTxAddresses(rawTxHex string) []string {
+	tx, err := transaction.NewTransactionFromHex(rawTxHex)
+	if err != nil {
+		return nil
+	}
+
+	var addresses []string
+	for _, output := range tx.Outputs {
+		if address, _, err := parseTransferOutput(output.LockingScript); err == nil {
+			addresses = append(addresses, address)
+		}
+	}
+	return append(addresses, txSigners(tx)...)
+}
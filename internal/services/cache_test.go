@@ -0,0 +1,46 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCacheScope(t *testing.T) {
+	address := testAddress(t)
+	errRefused := errors.New("status received from mnee-cosigner -> 401")
+	ttls := CacheTTLs{Balances: time.Minute}
+
+	tests := []struct {
+		name       string
+		apiKey     string
+		invalidate bool
+		hit        bool
+	}{
+		{name: "same API key", apiKey: "alice-key", hit: true},
+		{name: "another API key", apiKey: "bob-key"},
+		{name: "same API key after a transfer elsewhere", apiKey: "alice-key", invalidate: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := NewFakeClient()
+			cache := NewMemoryCache()
+			alice := withCache(fake, cache, ttls, "production", "alice-key").(cachingClient)
+			other := withCache(fake, cache, ttls, "production", tt.apiKey).(cachingClient)
+
+			if _, err := alice.GetBalances(t.Context(), []string{address}); err != nil {
+				t.Fatal(err)
+			}
+			if tt.invalidate {
+				withCache(fake, cache, ttls, "production", "bob-key").(cachingClient).invalidate(t.Context(), []string{address})
+			}
+
+			fake.FailWith("GetBalances", errRefused)
+			_, err := other.GetBalances(t.Context(), []string{address})
+			if hit := err == nil; hit != tt.hit {
+				t.Fatalf("got error %v, want a cache hit %v", err, tt.hit)
+			}
+		})
+	}
+}
@@ -0,0 +1,729 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	primitives "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	"github.com/bsv-blockchain/go-sdk/transaction/template/p2pkh"
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+)
+
+// FakeFixture seeds a FakeClient. Config fields left empty keep their generated
+// defaults; balances are minted as one UTXO per address, in atomic units.
+type FakeFixture struct {
+	Config   *mnee.SystemConfig `json:"config,omitempty"`
+	Balances map[string]uint64  `json:"balances,omitempty"`
+	Tickets  []mnee.Ticket      `json:"tickets,omitempty"`
+}
+
+// FakeClient is an in-memory MneeClient backed by a UTXO ledger. Transfers are
+// built and signed exactly like real MNEE transactions and applied to the
+// ledger when submitted, so balances, UTXOs, history and tickets stay
+// consistent. Failures can be scripted per method with FailWith.
+type FakeClient struct {
+	mutex    sync.Mutex
+	config   mnee.SystemConfig
+	approver *primitives.PublicKey
+	utxos    []mnee.MneeTxo
+	history  []mnee.TransactionHistoryDTO
+	tickets  map[string]mnee.Ticket
+	failures map[string]error
+	score    uint64
+}
+
+var _ MneeClient = (*FakeClient)(nil)
+
+func NewFakeClient() *FakeClient {
+	approverKey, _ := primitives.NewPrivateKey()
+	feeKey, _ := primitives.NewPrivateKey()
+	feeAddress, _ := script.NewAddressFromPublicKey(feeKey.PubKey(), true)
+
+	approver := hex.EncodeToString(approverKey.PubKey().Compressed())
+	tokenID := randomHex(32) + "_0"
+
+	return &FakeClient{
+		config: mnee.SystemConfig{
+			Decimals:   5,
+			Approver:   &approver,
+			FeeAddress: &feeAddress.AddressString,
+			TokenId:    &tokenID,
+			Fees: []mnee.Fee{
+				{MinAmt: 0, MaxAmt: 1000000, Fee: 100},
+				{MinAmt: 1000001, MaxAmt: math.MaxUint64, Fee: 1000},
+			},
+		},
+		approver: approverKey.PubKey(),
+		tickets:  make(map[string]mnee.Ticket),
+		failures: make(map[string]error),
+	}
+}
+
+// LoadFixture reads a JSON FakeFixture from path and applies it.
+func (f *FakeClient) LoadFixture(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var fixture FakeFixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return fmt.Errorf("invalid fixture %s: %w", path, err)
+	}
+
+	return f.ApplyFixture(&fixture)
+}
+
+func (f *FakeClient) ApplyFixture(fixture *FakeFixture) error {
+	if fixture.Config != nil {
+		if err := f.SetConfig(*fixture.Config); err != nil {
+			return err
+		}
+	}
+
+	addresses := make([]string, 0, len(fixture.Balances))
+	for address := range fixture.Balances {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	for _, address := range addresses {
+		if err := f.Fund(address, fixture.Balances[address]); err != nil {
+			return err
+		}
+	}
+
+	for _, ticket := range fixture.Tickets {
+		if err := f.PutTicket(ticket); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetConfig overrides the system config. Empty fields keep their current value.
+func (f *FakeClient) SetConfig(config mnee.SystemConfig) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if config.Approver != nil {
+		approver, err := primitives.PublicKeyFromString(*config.Approver)
+		if err != nil {
+			return fmt.Errorf("invalid approver public key: %w", err)
+		}
+		f.approver = approver
+		f.config.Approver = config.Approver
+	}
+	if config.Decimals != 0 {
+		f.config.Decimals = config.Decimals
+	}
+	if config.FeeAddress != nil {
+		f.config.FeeAddress = config.FeeAddress
+	}
+	if config.BurnAddress != nil {
+		f.config.BurnAddress = config.BurnAddress
+	}
+	if config.MintAddress != nil {
+		f.config.MintAddress = config.MintAddress
+	}
+	if config.TokenId != nil {
+		f.config.TokenId = config.TokenId
+	}
+	if config.Fees != nil {
+		f.config.Fees = config.Fees
+	}
+
+	return nil
+}
+
+// Fund mints a new UTXO of amount atomic units owned by address.
+func (f *FakeClient) Fund(address string, amount uint64) error {
+	if amount == 0 {
+		return mnee.ErrTransferAmountGreaterThan0
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	tx := transaction.NewTransaction()
+	if err := inscribeTransfer(tx, &f.config, f.approver, address, amount); err != nil {
+		return err
+	}
+
+	// A mint has no inputs, so salt the lock time to keep txids unique.
+	tx.LockTime = uint32(f.score)
+	f.score++
+	f.addUtxo(tx.TxID().String(), 0, tx.Outputs[0].LockingScript, address, amount, nil)
+
+	return nil
+}
+
+// PutTicket stores or replaces a ticket returned by PollTicket.
+func (f *FakeClient) PutTicket(ticket mnee.Ticket) error {
+	if ticket.ID == nil || *ticket.ID == "" {
+		return errors.New("ticket id is required")
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.tickets[*ticket.ID] = ticket
+	return nil
+}
+
+// Ticket returns the stored ticket without waiting for it to appear.
+func (f *FakeClient) Ticket(ticketID string) (*mnee.Ticket, bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	ticket, ok := f.tickets[ticketID]
+	return &ticket, ok
+}
+
+// FailWith makes every call to the named MneeClient method return err until it
+// is cleared with a nil error.
+func (f *FakeClient) FailWith(method string, err error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if err == nil {
+		delete(f.failures, method)
+		return
+	}
+	f.failures[method] = err
+}
+
+func (f *FakeClient) GetBalances(ctx context.Context, addresses []string) ([]mnee.BalanceDataDTO, error) {
+	if err := f.check(ctx, "GetBalances"); err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	balances := make([]mnee.BalanceDataDTO, 0, len(addresses))
+	for _, address := range addresses {
+		var amount uint64
+		for _, utxo := range f.utxos {
+			if utxo.Owners[0] == address {
+				amount += utxo.Data.Bsv21.Amt
+			}
+		}
+
+		addr := address
+		balances = append(balances, mnee.BalanceDataDTO{
+			Address:  &addr,
+			Amt:      float64(amount),
+			Precised: float64(amount) / math.Pow10(int(f.config.Decimals)),
+		})
+	}
+
+	return balances, nil
+}
+
+func (f *FakeClient) GetConfig(ctx context.Context) (*mnee.SystemConfig, error) {
+	if err := f.check(ctx, "GetConfig"); err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	config := f.config
+	return &config, nil
+}
+
+func (f *FakeClient) GetSpecificTransactionHistory(ctx context.Context, addresses []string, from int, limit int) ([]mnee.TransactionHistoryDTO, error) {
+	if err := f.check(ctx, "GetSpecificTransactionHistory"); err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	history := make([]mnee.TransactionHistoryDTO, 0)
+	for _, entry := range f.history {
+		if entry.Score < uint64(from) {
+			continue
+		}
+		if !slices.ContainsFunc(addresses, func(a string) bool {
+			return slices.Contains(entry.Senders, a) || slices.Contains(entry.Receivers, a)
+		}) {
+			continue
+		}
+
+		history = append(history, entry)
+		if limit > 0 && len(history) == limit {
+			break
+		}
+	}
+
+	return history, nil
+}
+
+func (f *FakeClient) GetUnspentTxos(ctx context.Context, addresses []string) ([]mnee.MneeTxo, error) {
+	if err := f.check(ctx, "GetUnspentTxos"); err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.utxosOwnedBy(addresses), nil
+}
+
+func (f *FakeClient) GetPaginatedUnspentTxos(ctx context.Context, addresses []string, page int, size int) ([]mnee.MneeTxo, error) {
+	if err := f.check(ctx, "GetPaginatedUnspentTxos"); err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	txos := f.utxosOwnedBy(addresses)
+	start := (page - 1) * size
+	if page < 1 || size < 1 || start >= len(txos) {
+		return make([]mnee.MneeTxo, 0), nil
+	}
+
+	return txos[start:min(start+size, len(txos))], nil
+}
+
+func (f *FakeClient) PollTicket(ctx context.Context, ticketID string, pollingInterval time.Duration) (*mnee.Ticket, error) {
+	for {
+		if err := f.check(ctx, "PollTicket"); err != nil {
+			return nil, err
+		}
+
+		if ticket, ok := f.Ticket(ticketID); ok {
+			return ticket, nil
+		}
+
+		select {
+		case <-time.After(pollingInterval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Network is NetworkTestnet, which accepts mainnet addresses too, so
+// fixtures may use either.
+func (f *FakeClient) Network() Network {
+	return NetworkTestnet
+}
+
+func (f *FakeClient) GetTicket(ctx context.Context, ticketID string) (*mnee.Ticket, error) {
+	if err := f.check(ctx, "GetTicket"); err != nil {
+		return nil, err
+	}
+
+	ticket, ok := f.Ticket(ticketID)
+	if !ok {
+		return nil, ErrTicketNotFound
+	}
+	return ticket, nil
+}
+
+func (f *FakeClient) SynchronousTransfer(ctx context.Context, wifs []string, mneeTransferDTO []mnee.TransferMneeDTO, withTxos bool,
+	mneeTxos []mnee.MneeTxo) (*mnee.TransferResponseDTO, error) {
+	if err := f.check(ctx, "SynchronousTransfer"); err != nil {
+		return nil, err
+	}
+
+	tx, err := f.buildTransfer(wifs, mneeTransferDTO, withTxos, mneeTxos)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.submit(tx)
+}
+
+func (f *FakeClient) AsynchronousTransfer(ctx context.Context, wifs []string, mneeTransferDTO []mnee.TransferMneeDTO, withTxos bool,
+	mneeTxos []mnee.MneeTxo, callbackURL *string, callbackSecret *string) (*string, error) {
+	if err := f.check(ctx, "AsynchronousTransfer"); err != nil {
+		return nil, err
+	}
+
+	tx, err := f.buildTransfer(wifs, mneeTransferDTO, withTxos, mneeTxos)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.submitAsync(tx, callbackURL, callbackSecret)
+}
+
+func (f *FakeClient) PartialSign(ctx context.Context, wifs []string, mneeTransferDTO []mnee.TransferMneeDTO, withTxos bool,
+	mneeTxos []mnee.MneeTxo) (*string, error) {
+	if err := f.check(ctx, "PartialSign"); err != nil {
+		return nil, err
+	}
+
+	tx, err := f.buildTransfer(wifs, mneeTransferDTO, withTxos, mneeTxos)
+	if err != nil {
+		return nil, err
+	}
+
+	partialHex := tx.Hex()
+	return &partialHex, nil
+}
+
+func (f *FakeClient) SubmitRawTxSync(ctx context.Context, rawTxHex string) (*mnee.TransferResponseDTO, error) {
+	if err := f.check(ctx, "SubmitRawTxSync"); err != nil {
+		return nil, err
+	}
+
+	tx, err := transaction.NewTransactionFromHex(rawTxHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid raw transaction hex: %w", err)
+	}
+
+	return f.submit(tx)
+}
+
+func (f *FakeClient) SubmitRawTxAsync(ctx context.Context, rawTxHex string, callbackURL *string, callbackSecret *string) (*string, error) {
+	if err := f.check(ctx, "SubmitRawTxAsync"); err != nil {
+		return nil, err
+	}
+
+	tx, err := transaction.NewTransactionFromHex(rawTxHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid raw transaction hex: %w", err)
+	}
+
+	return f.submitAsync(tx, callbackURL, callbackSecret)
+}
+
+func (f *FakeClient) check(ctx context.Context, method string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.failures[method]
+}
+
+func (f *FakeClient) submit(tx *transaction.Transaction) (*mnee.TransferResponseDTO, error) {
+	if err := f.apply(tx); err != nil {
+		return nil, err
+	}
+
+	txID := tx.TxID().String()
+	txHex := tx.Hex()
+	return &mnee.TransferResponseDTO{Txid: &txID, Txhex: &txHex}, nil
+}
+
+func (f *FakeClient) submitAsync(tx *transaction.Transaction, callbackURL *string, callbackSecret *string) (*string, error) {
+	if err := f.apply(tx); err != nil {
+		return nil, err
+	}
+
+	ticketID := randomUUID()
+	txID := tx.TxID().String()
+	txHex := tx.Hex()
+	action := mnee.ACTION_TRANSFER
+	now := time.Now().UTC()
+
+	err := f.PutTicket(mnee.Ticket{
+		ID:              &ticketID,
+		TxID:            &txID,
+		TxHex:           &txHex,
+		ActionRequested: &action,
+		CallbackURL:     callbackURL,
+		CallbackSecret:  callbackSecret,
+		Status:          mnee.SUCCESS,
+		CreatedAt:       &now,
+		UpdatedAt:       &now,
+		Errors:          []string{},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if callbackURL != nil && *callbackURL != "" {
+		go f.notify(ticketID, *callbackURL, callbackSecret)
+	}
+
+	return &ticketID, nil
+}
+
+// notify posts the ticket to its callback URL, signing the body with
+// HMAC-SHA256 of the callback secret in X-Signature.
+func (f *FakeClient) notify(ticketID string, callbackURL string, callbackSecret *string) {
+	ticket, ok := f.Ticket(ticketID)
+	if !ok {
+		return
+	}
+	ticket.CallbackURL = nil
+	ticket.CallbackSecret = nil
+
+	body, err := json.Marshal(ticket)
+	if err != nil {
+		return
+	}
+
+	request, err := http.NewRequest(http.MethodPost, callbackURL, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if callbackSecret != nil {
+		mac := hmac.New(sha256.New, []byte(*callbackSecret))
+		mac.Write(body)
+		request.Header.Set("X-Signature", hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	response, err := client.Do(request)
+	if err != nil {
+		return
+	}
+	response.Body.Close()
+}
+
+// buildTransfer selects UTXOs owned by wifs and returns a signed transfer with
+// fee and change outputs, following the same rules as the SDK.
+func (f *FakeClient) buildTransfer(wifs []string, mneeTransferDTO []mnee.TransferMneeDTO, withTxos bool,
+	mneeTxos []mnee.MneeTxo) (*transaction.Transaction, error) {
+
+	keys := make(map[string]*primitives.PrivateKey)
+	addresses := make([]string, 0, len(wifs))
+	for _, wif := range wifs {
+		privateKey, err := primitives.PrivateKeyFromWif(wif)
+		if err != nil {
+			return nil, err
+		}
+
+		address, err := script.NewAddressFromPublicKey(privateKey.PubKey(), true)
+		if err != nil {
+			return nil, err
+		}
+
+		keys[address.AddressString] = privateKey
+		addresses = append(addresses, address.AddressString)
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.config.Approver == nil || f.config.FeeAddress == nil || f.config.Fees == nil || f.config.TokenId == nil {
+		return nil, mnee.ErrInvalidConfig
+	}
+
+	tx := transaction.NewTransaction()
+	for _, dto := range mneeTransferDTO {
+		if dto.Amount == 0 {
+			return nil, mnee.ErrTransferAmountGreaterThan0
+		}
+
+		if err := inscribeTransfer(tx, &f.config, f.approver, dto.Address, dto.Amount); err != nil {
+			return nil, err
+		}
+	}
+
+	txos := mneeTxos
+	if !withTxos {
+		txos = f.utxosOwnedBy(addresses)
+	}
+
+	selected, totalInputAmount, fee, err := selectInputs(txos, func(owner string) bool {
+		_, ok := keys[owner]
+		return ok
+	}, mneeTransferDTO, f.config.Fees)
+	if err != nil {
+		return nil, err
+	}
+	totalTransferAmt, _ := TotalAmount(mneeTransferDTO)
+
+	for _, txo := range selected {
+		scriptBytes, err := base64.StdEncoding.DecodeString(*txo.Script)
+		if err != nil {
+			return nil, err
+		}
+
+		sighashFlags := TransferSighashFlags
+		unlocker, err := p2pkh.Unlock(keys[txo.Owners[0]], &sighashFlags)
+		if err != nil {
+			return nil, err
+		}
+
+		err = tx.AddInputFrom(*txo.Txid, uint32(txo.Vout), hex.EncodeToString(scriptBytes), uint64(txo.Satoshis), unlocker)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if fee > 0 {
+		if err := inscribeTransfer(tx, &f.config, f.approver, *f.config.FeeAddress, fee); err != nil {
+			return nil, err
+		}
+	}
+
+	if change := totalInputAmount - totalTransferAmt - fee; change > 0 {
+		if err := inscribeTransfer(tx, &f.config, f.approver, selected[0].Owners[0], change); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Sign(); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// apply validates tx against the ledger, spends its inputs and records its outputs.
+func (f *FakeClient) apply(tx *transaction.Transaction) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(tx.Inputs) == 0 {
+		return errors.New("transaction has no inputs")
+	}
+
+	spent := make([]int, 0, len(tx.Inputs))
+	var senders []string
+	var totalIn uint64
+	for _, input := range tx.Inputs {
+		sourceTxID := input.SourceTXID.String()
+		idx := slices.IndexFunc(f.utxos, func(utxo mnee.MneeTxo) bool {
+			return *utxo.Txid == sourceTxID && utxo.Vout == uint64(input.SourceTxOutIndex)
+		})
+		if idx < 0 || slices.Contains(spent, idx) {
+			return fmt.Errorf("input %s_%d is not an unspent MNEE output", sourceTxID, input.SourceTxOutIndex)
+		}
+
+		spent = append(spent, idx)
+		totalIn += f.utxos[idx].Data.Bsv21.Amt
+		if !slices.Contains(senders, f.utxos[idx].Owners[0]) {
+			senders = append(senders, f.utxos[idx].Owners[0])
+		}
+	}
+
+	type output struct {
+		address string
+		amount  uint64
+	}
+	outputs := make([]output, 0, len(tx.Outputs))
+	var receivers []string
+	var totalOut, feePaid, transferred uint64
+	for vout, out := range tx.Outputs {
+		address, amount, err := parseTransferOutput(out.LockingScript)
+		if err != nil {
+			return fmt.Errorf("output %d: %w", vout, err)
+		}
+
+		outputs = append(outputs, output{address: address, amount: amount})
+		totalOut += amount
+		switch {
+		case f.config.FeeAddress != nil && address == *f.config.FeeAddress:
+			feePaid += amount
+		case !slices.Contains(senders, address):
+			transferred += amount
+		}
+		if !slices.Contains(receivers, address) {
+			receivers = append(receivers, address)
+		}
+	}
+
+	if totalIn != totalOut {
+		return fmt.Errorf("transaction inputs (%d) and outputs (%d) do not balance", totalIn, totalOut)
+	}
+
+	if required := feeForAmount(f.config.Fees, transferred); feePaid < required {
+		return fmt.Errorf("transaction pays a fee of %d, %d is required", feePaid, required)
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(spent)))
+	for _, idx := range spent {
+		f.utxos = slices.Delete(f.utxos, idx, idx+1)
+	}
+
+	txID := tx.TxID().String()
+	f.score++
+	outs := make([]uint64, 0, len(outputs))
+	for vout, out := range outputs {
+		f.addUtxo(txID, vout, tx.Outputs[vout].LockingScript, out.address, out.amount, senders)
+		outs = append(outs, uint64(vout))
+	}
+
+	rawTx := base64.StdEncoding.EncodeToString(tx.Bytes())
+	f.history = append(f.history, mnee.TransactionHistoryDTO{
+		Height:    f.score,
+		Score:     f.score,
+		Rawtx:     &rawTx,
+		Txid:      &txID,
+		Outs:      outs,
+		Senders:   senders,
+		Receivers: receivers,
+	})
+
+	return nil
+}
+
+func (f *FakeClient) addUtxo(txID string, vout int, lockingScript *script.Script, owner string, amount uint64, senders []string) {
+	outpoint := fmt.Sprintf("%s_%d", txID, vout)
+	encodedScript := base64.StdEncoding.EncodeToString(lockingScript.Bytes())
+	op := string(mnee.TRANSFER)
+
+	f.utxos = append(f.utxos, mnee.MneeTxo{
+		Satoshis: 1,
+		Height:   f.score,
+		Score:    f.score,
+		Vout:     uint64(vout),
+		Outpoint: &outpoint,
+		Script:   &encodedScript,
+		Txid:     &txID,
+		Data: &mnee.Data{
+			Bsv21: &mnee.BsvData{
+				Decimals: f.config.Decimals,
+				Amt:      amount,
+				Id:       f.config.TokenId,
+				Op:       &op,
+			},
+		},
+		Owners:  []string{owner},
+		Senders: senders,
+	})
+}
+
+func (f *FakeClient) utxosOwnedBy(addresses []string) []mnee.MneeTxo {
+	txos := make([]mnee.MneeTxo, 0)
+	for _, utxo := range f.utxos {
+		if slices.Contains(addresses, utxo.Owners[0]) {
+			txos = append(txos, utxo)
+		}
+	}
+	return txos
+}
+
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+func randomUUID() string {
+	id := randomHex(16)
+	return id[0:8] + "-" + id[8:12] + "-" + id[12:16] + "-" + id[16:20] + "-" + id[20:32]
+}
@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/logging"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/metrics"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentedClient traces, logs and records the calls, latency and error
+// classes of the client it wraps, the volume of its transfers and the outcome
+// of the tickets it returns. Spans and log lines carry ticket IDs, txids and
+// counts, never keys.
+type instrumentedClient struct {
+	client MneeClient
+}
+
+var _ MneeClient = instrumentedClient{}
+
+func instrument(client MneeClient) MneeClient {
+	return instrumentedClient{client: client}
+}
+
+// TicketFinished reports whether a ticket will not change any more.
+func TicketFinished(ticket *mnee.Ticket) bool {
+	return ticket.Status == mnee.SUCCESS || len(ticket.Errors) > 0 ||
+		(ticket.Status != "" && ticket.Status != mnee.BROADCASTING)
+}
+
+// call is one instrumented call to the client, collecting the attributes
+// its span and log line carry.
+type call struct {
+	ctx     context.Context
+	span    trace.Span
+	method  string
+	started time.Time
+	attrs   []attribute.KeyValue
+}
+
+func startCall(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, *call) {
+	ctx, span := tracing.Tracer.Start(ctx, "mnee."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	return ctx, &call{ctx: ctx, span: span, method: method, started: time.Now(), attrs: attrs}
+}
+
+func (c *call) set(attrs ...attribute.KeyValue) {
+	c.span.SetAttributes(attrs...)
+	c.attrs = append(c.attrs, attrs...)
+}
+
+// end records the call's outcome in the metrics, its span and the request's
+// log. It is deferred, so it reads *errp once the call has returned.
+func (c *call) end(errp *error) {
+	defer c.span.End()
+
+	err := *errp
+	duration := time.Since(c.started)
+	metrics.SDKDuration.WithLabelValues(c.method).Observe(duration.Seconds())
+
+	logAttrs := make([]any, 0, 2*len(c.attrs)+6)
+	logAttrs = append(logAttrs, "method", c.method, "duration_ms", duration.Milliseconds())
+	for _, attr := range c.attrs {
+		logAttrs = append(logAttrs, strings.TrimPrefix(string(attr.Key), "mnee."), attr.Value.AsInterface())
+	}
+	logger := logging.FromContext(c.ctx)
+
+	if err != nil {
+		class := errorClass(err)
+		metrics.SDKCalls.WithLabelValues(c.method, "error").Inc()
+		metrics.SDKErrors.WithLabelValues(c.method, class).Inc()
+		c.span.SetAttributes(attribute.String("mnee.error_class", class))
+		// Exported spans must not carry the API key in SDK errors.
+		c.span.RecordError(errors.New(logging.Redact(err.Error())))
+		c.span.SetStatus(codes.Error, class)
+		logger.Warn("mnee sdk call failed", append(logAttrs, "error_class", class, "error", err)...)
+		return
+	}
+	metrics.SDKCalls.WithLabelValues(c.method, "ok").Inc()
+	logger.Info("mnee sdk call", logAttrs...)
+}
+
+// errorClass buckets err for mnee_sdk_errors_total. Errors it does not
+// recognise are messages from the cosigner rejecting the request.
+func errorClass(err error) string {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, mnee.ErrForbidden):
+		return "forbidden"
+	case errors.Is(err, mnee.ErrInsufficientMneeBalance):
+		return "insufficient_balance"
+	case errors.Is(err, ErrTicketNotFound):
+		return "not_found"
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout"
+	case errors.As(err, &netErr):
+		return "network"
+	case CosignerDown(err):
+		return "server_error"
+	}
+	return "rejected"
+}
+
+func transferAttributes(transfers []mnee.TransferMneeDTO, withTxos bool, txos []mnee.MneeTxo) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.Int("mnee.recipient_count", len(transfers)),
+		attribute.Int64("mnee.amount_atomic", int64(transferTotal(transfers))),
+		attribute.Bool("mnee.with_txos", withTxos),
+		attribute.Int("mnee.txo_count", len(txos)),
+	}
+}
+
+func transferTotal(transfers []mnee.TransferMneeDTO) uint64 {
+	var total uint64
+	for _, transfer := range transfers {
+		total += transfer.Amount
+	}
+	return total
+}
+
+func (c *call) setTicket(ticket *mnee.Ticket) {
+	if ticket == nil {
+		return
+	}
+	c.set(attribute.String("mnee.ticket_status", string(ticket.Status)))
+	if ticket.TxID != nil {
+		c.set(attribute.String("mnee.txid", *ticket.TxID))
+	}
+	if ticket.ID == nil || !TicketFinished(ticket) {
+		return
+	}
+	outcome := "success"
+	if ticket.Status != mnee.SUCCESS || len(ticket.Errors) > 0 {
+		outcome = "failed"
+	}
+	metrics.ObserveTicketOutcome(*ticket.ID, outcome)
+}
+
+func (c *call) setResponse(response *mnee.TransferResponseDTO) {
+	if response != nil && response.Txid != nil {
+		c.set(attribute.String("mnee.txid", *response.Txid))
+	}
+}
+
+func (c *call) setTicketID(ticketID *string) {
+	if ticketID != nil {
+		c.set(attribute.String("mnee.ticket_id", *ticketID))
+	}
+}
+
+func (i instrumentedClient) Network() Network {
+	return i.client.Network()
+}
+
+func (i instrumentedClient) GetBalances(ctx context.Context, addresses []string) (balances []mnee.BalanceDataDTO, err error) {
+	ctx, call := startCall(ctx, "GetBalances", attribute.Int("mnee.address_count", len(addresses)))
+	defer call.end(&err)
+	return i.client.GetBalances(ctx, addresses)
+}
+
+func (i instrumentedClient) GetConfig(ctx context.Context) (config *mnee.SystemConfig, err error) {
+	ctx, call := startCall(ctx, "GetConfig")
+	defer call.end(&err)
+	return i.client.GetConfig(ctx)
+}
+
+func (i instrumentedClient) GetSpecificTransactionHistory(ctx context.Context, addresses []string, from int, limit int) (history []mnee.TransactionHistoryDTO, err error) {
+	ctx, call := startCall(ctx, "GetSpecificTransactionHistory", attribute.Int("mnee.address_count", len(addresses)))
+	defer call.end(&err)
+	return i.client.GetSpecificTransactionHistory(ctx, addresses, from, limit)
+}
+
+func (i instrumentedClient) GetUnspentTxos(ctx context.Context, addresses []string) (txos []mnee.MneeTxo, err error) {
+	ctx, call := startCall(ctx, "GetUnspentTxos", attribute.Int("mnee.address_count", len(addresses)))
+	defer call.end(&err)
+	return i.client.GetUnspentTxos(ctx, addresses)
+}
+
+func (i instrumentedClient) GetPaginatedUnspentTxos(ctx context.Context, addresses []string, page int, size int) (txos []mnee.MneeTxo, err error) {
+	ctx, call := startCall(ctx, "GetPaginatedUnspentTxos", attribute.Int("mnee.address_count", len(addresses)))
+	defer call.end(&err)
+	return i.client.GetPaginatedUnspentTxos(ctx, addresses, page, size)
+}
+
+func (i instrumentedClient) PollTicket(ctx context.Context, ticketID string, pollingInterval time.Duration) (ticket *mnee.Ticket, err error) {
+	ctx, call := startCall(ctx, "PollTicket", attribute.String("mnee.ticket_id", ticketID))
+	defer call.end(&err)
+	ticket, err = i.client.PollTicket(ctx, ticketID, pollingInterval)
+	call.setTicket(ticket)
+	return ticket, err
+}
+
+func (i instrumentedClient) GetTicket(ctx context.Context, ticketID string) (ticket *mnee.Ticket, err error) {
+	ctx, call := startCall(ctx, "GetTicket", attribute.String("mnee.ticket_id", ticketID))
+	defer call.end(&err)
+	ticket, err = i.client.GetTicket(ctx, ticketID)
+	call.setTicket(ticket)
+	return ticket, err
+}
+
+func (i instrumentedClient) SynchronousTransfer(ctx context.Context, wifs []string, mneeTransferDTO []mnee.TransferMneeDTO, withTxos bool,
+	mneeTxos []mnee.MneeTxo) (response *mnee.TransferResponseDTO, err error) {
+	ctx, call := startCall(ctx, "SynchronousTransfer", transferAttributes(mneeTransferDTO, withTxos, mneeTxos)...)
+	defer call.end(&err)
+	response, err = i.client.SynchronousTransfer(ctx, wifs, mneeTransferDTO, withTxos, mneeTxos)
+	if err == nil {
+		metrics.TransferVolume.WithLabelValues("SynchronousTransfer").Add(float64(transferTotal(mneeTransferDTO)))
+		call.setResponse(response)
+	}
+	return response, err
+}
+
+func (i instrumentedClient) AsynchronousTransfer(ctx context.Context, wifs []string, mneeTransferDTO []mnee.TransferMneeDTO, withTxos bool,
+	mneeTxos []mnee.MneeTxo, callbackURL *string, callbackSecret *string) (ticketID *string, err error) {
+	ctx, call := startCall(ctx, "AsynchronousTransfer", transferAttributes(mneeTransferDTO, withTxos, mneeTxos)...)
+	defer call.end(&err)
+	ticketID, err = i.client.AsynchronousTransfer(ctx, wifs, mneeTransferDTO, withTxos, mneeTxos, callbackURL, callbackSecret)
+	if err == nil {
+		metrics.TransferVolume.WithLabelValues("AsynchronousTransfer").Add(float64(transferTotal(mneeTransferDTO)))
+		call.setTicketID(ticketID)
+	}
+	return ticketID, err
+}
+
+func (i instrumentedClient) PartialSign(ctx context.Context, wifs []string, mneeTransferDTO []mnee.TransferMneeDTO, withTxos bool,
+	mneeTxos []mnee.MneeTxo) (rawTx *string, err error) {
+	ctx, call := startCall(ctx, "PartialSign", transferAttributes(mneeTransferDTO, withTxos, mneeTxos)...)
+	defer call.end(&err)
+	return i.client.PartialSign(ctx, wifs, mneeTransferDTO, withTxos, mneeTxos)
+}
+
+func (i instrumentedClient) SubmitRawTxSync(ctx context.Context, rawTxHex string) (response *mnee.TransferResponseDTO, err error) {
+	ctx, call := startCall(ctx, "SubmitRawTxSync", attribute.Int("mnee.raw_tx_bytes", len(rawTxHex)/2))
+	defer call.end(&err)
+	response, err = i.client.SubmitRawTxSync(ctx, rawTxHex)
+	call.setResponse(response)
+	return response, err
+}
+
+func (i instrumentedClient) SubmitRawTxAsync(ctx context.Context, rawTxHex string, callbackURL *string, callbackSecret *string) (ticketID *string, err error) {
+	ctx, call := startCall(ctx, "SubmitRawTxAsync", attribute.Int("mnee.raw_tx_bytes", len(rawTxHex)/2))
+	defer call.end(&err)
+	ticketID, err = i.client.SubmitRawTxAsync(ctx, rawTxHex, callbackURL, callbackSecret)
+	call.setTicketID(ticketID)
+	return ticketID, err
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/metrics"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestErrorClass(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{err: context.DeadlineExceeded, want: "timeout"},
+		{err: fmt.Errorf("polling: %w", context.DeadlineExceeded), want: "timeout"},
+		{err: context.Canceled, want: "canceled"},
+		{err: mnee.ErrForbidden, want: "forbidden"},
+		{err: mnee.ErrInsufficientMneeBalance, want: "insufficient_balance"},
+		{err: ErrTicketNotFound, want: "not_found"},
+		{err: &net.DNSError{Err: "timeout", IsTimeout: true}, want: "timeout"},
+		{err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, want: "network"},
+		{err: errDown, want: "server_error"},
+		{err: errors.New("invalid recipient"), want: "rejected"},
+	}
+
+	for _, tt := range tests {
+		if got := errorClass(tt.err); got != tt.want {
+			t.Errorf("errorClass(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestInstrumentedMetrics(t *testing.T) {
+	wif, sender := testKey(t)
+	_, recipient := testKey(t)
+
+	fake := NewFakeClient()
+	if err := fake.Fund(sender, 100000); err != nil {
+		t.Fatal(err)
+	}
+	client := instrument(fake)
+
+	calls := func(method, outcome string) float64 {
+		return metricValue(t, "mnee_sdk_calls_total", map[string]string{"method": method, "outcome": outcome})
+	}
+	okBefore, errorBefore := calls("GetBalances", "ok"), calls("GetBalances", "error")
+	classBefore := metricValue(t, "mnee_sdk_errors_total", map[string]string{"method": "GetBalances", "class": "server_error"})
+	volumeBefore := metricValue(t, "mnee_transfer_volume_atomic_total", map[string]string{"method": "SynchronousTransfer"})
+
+	if _, err := client.GetBalances(t.Context(), []string{sender}); err != nil {
+		t.Fatal(err)
+	}
+	fake.FailWith("GetBalances", errDown)
+	if _, err := client.GetBalances(t.Context(), []string{sender}); err == nil {
+		t.Fatal("GetBalances succeeded while failing")
+	}
+
+	if got := calls("GetBalances", "ok") - okBefore; got != 1 {
+		t.Errorf("counted %v successful calls, want 1", got)
+	}
+	if got := calls("GetBalances", "error") - errorBefore; got != 1 {
+		t.Errorf("counted %v failed calls, want 1", got)
+	}
+	if got := metricValue(t, "mnee_sdk_errors_total", map[string]string{"method": "GetBalances", "class": "server_error"}) - classBefore; got != 1 {
+		t.Errorf("counted %v server errors, want 1", got)
+	}
+
+	dtos := []mnee.TransferMneeDTO{{Address: recipient, Amount: 1000}, {Address: recipient, Amount: 2500}}
+	if _, err := client.SynchronousTransfer(t.Context(), []string{wif}, dtos, false, nil); err != nil {
+		t.Fatal(err)
+	}
+	// A refused transfer moves nothing.
+	if _, err := client.SynchronousTransfer(t.Context(), []string{wif}, []mnee.TransferMneeDTO{{Address: recipient, Amount: 1000000}}, false, nil); err == nil {
+		t.Fatal("overdrawn transfer succeeded")
+	}
+	if got := metricValue(t, "mnee_transfer_volume_atomic_total", map[string]string{"method": "SynchronousTransfer"}) - volumeBefore; got != 3500 {
+		t.Errorf("counted a volume of %v, want 3500", got)
+	}
+}
+
+// metricValue reads the sample of counter name whose labels match labels
+// from the registry /metrics serves.
+func metricValue(t *testing.T, name string, labels map[string]string) float64 {
+	t.Helper()
+
+	families, err := metrics.Registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	samples:
+		for _, metric := range family.GetMetric() {
+			for _, pair := range metric.GetLabel() {
+				if labels[pair.GetName()] != pair.GetValue() {
+					continue samples
+				}
+			}
+			return metric.GetCounter().GetValue()
+		}
+	}
+	return 0
+}
+
+func TestInstrumentedSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := tracing.Tracer
+	t.Cleanup(func() { tracing.Tracer = previous })
+	tracing.Tracer = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	wif, sender := testKey(t)
+	_, recipient := testKey(t)
+	fake := NewFakeClient()
+	if err := fake.Fund(sender, 100000); err != nil {
+		t.Fatal(err)
+	}
+	client := instrument(fake)
+
+	ctx, parent := tracing.Tracer.Start(t.Context(), "request")
+	if _, err := client.SynchronousTransfer(ctx, []string{wif}, []mnee.TransferMneeDTO{{Address: recipient, Amount: 1000}}, false, nil); err != nil {
+		t.Fatal(err)
+	}
+	fake.FailWith("GetBalances", errors.New(`Get "https://api.mnee.net/v2/balance?auth_token=sdk-key-123": connection reset`))
+	if _, err := client.GetBalances(ctx, []string{sender}); err == nil {
+		t.Fatal("GetBalances succeeded while failing")
+	}
+	parent.End()
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+
+	transfer, ok := spans["mnee.SynchronousTransfer"]
+	if !ok {
+		t.Fatalf("no transfer span among %v", spans)
+	}
+	if transfer.SpanKind() != trace.SpanKindClient || transfer.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Fatal("the transfer span is not a client span under the request span")
+	}
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, attr := range transfer.Attributes() {
+		attrs[attr.Key] = attr.Value
+	}
+	if attrs["mnee.recipient_count"].AsInt64() != 1 || attrs["mnee.amount_atomic"].AsInt64() != 1000 || attrs["mnee.txid"].AsString() == "" {
+		t.Fatalf("transfer span attributes = %v", transfer.Attributes())
+	}
+	for _, attr := range transfer.Attributes() {
+		if strings.Contains(attr.Value.Emit(), wif) {
+			t.Fatalf("transfer span attribute %s carries the WIF", attr.Key)
+		}
+	}
+
+	balances, ok := spans["mnee.GetBalances"]
+	if !ok {
+		t.Fatalf("no balance span among %v", spans)
+	}
+	if balances.Status().Code != codes.Error || balances.Status().Description != "rejected" {
+		t.Fatalf("balance span status = %+v, want an error of class rejected", balances.Status())
+	}
+	if len(balances.Events()) == 0 {
+		t.Fatal("balance span did not record the error")
+	}
+	for _, event := range balances.Events() {
+		for _, attr := range event.Attributes {
+			if strings.Contains(attr.Value.Emit(), "sdk-key-123") {
+				t.Fatalf("balance span event %s leaks the API key: %s", event.Name, attr.Value.Emit())
+			}
+		}
+	}
+}
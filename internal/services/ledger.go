@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/ledger"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/logging"
+)
+
+// ledgerClient applies every ticket it reads to the transfers recorded in
+// ledger.Instance, so polls, streams and callbacks all keep the ledger
+// current.
+type ledgerClient struct {
+	MneeClient
+}
+
+func withLedger(client MneeClient) MneeClient {
+	return ledgerClient{MneeClient: client}
+}
+
+func (l ledgerClient) GetTicket(ctx context.Context, ticketID string) (*mnee.Ticket, error) {
+	ticket, err := l.MneeClient.GetTicket(ctx, ticketID)
+	if err == nil {
+		l.updateLedger(ctx, ticket)
+	}
+	return ticket, err
+}
+
+func (l ledgerClient) PollTicket(ctx context.Context, ticketID string, pollingInterval time.Duration) (*mnee.Ticket, error) {
+	ticket, err := l.MneeClient.PollTicket(ctx, ticketID, pollingInterval)
+	if err == nil {
+		l.updateLedger(ctx, ticket)
+	}
+	return ticket, err
+}
+
+func (l ledgerClient) updateLedger(ctx context.Context, ticket *mnee.Ticket) {
+	if ledger.Instance == nil || ticket == nil || ticket.ID == nil {
+		return
+	}
+	// The update is kept even when the caller has gone away.
+	ctx = context.WithoutCancel(ctx)
+
+	update := ledger.TicketUpdate{Status: LedgerStatus(ticket), Errors: ticket.Errors}
+	if ticket.TxID != nil {
+		update.Txid = *ticket.TxID
+	}
+	if ticket.TxHex != nil && TicketFinished(ticket) {
+		if fee, ok := TxFee(ctx, l, *ticket.TxHex); ok {
+			update.AtomicFee = &fee
+		}
+	}
+
+	if err := ledger.Instance.UpdateTicket(ctx, *ticket.ID, update); err != nil {
+		logging.FromContext(ctx).Error("Failed to update the transfer ledger", "ticket_id", *ticket.ID, "error", err)
+	}
+}
+
+// LedgerStatus is the ledger status of a transfer with ticket, or empty
+// while the cosigner has not picked the ticket up.
+func LedgerStatus(ticket *mnee.Ticket) ledger.Status {
+	switch {
+	case len(ticket.Errors) > 0:
+		return ledger.StatusFailed
+	case ticket.Status == mnee.SUCCESS:
+		return ledger.StatusSuccess
+	case ticket.Status == mnee.BROADCASTING:
+		return ledger.StatusBroadcasting
+	case ticket.Status == "":
+		return ""
+	}
+	return ledger.StatusFailed
+}
+
+// TxFee returns the fee rawTxHex pays to the fee address of client's
+// cosigner, or false when it cannot be told.
+func TxFee(ctx context.Context, client MneeClient, rawTxHex string) (uint64, bool) {
+	config, err := client.GetConfig(ctx)
+	if err != nil || config.FeeAddress == nil {
+		return 0, false
+	}
+	summary, err := SummarizeTx(rawTxHex, *config.FeeAddress)
+	if err != nil {
+		return 0, false
+	}
+	return summary.Fee, true
+}
@@ -1,32 +1,144 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
-	"strings"
+	"net/url"
+	"strconv"
+	"time"
 
 	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
 	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/config"
 )
 
-var Instance *mnee.MNEE
+// EnvOffline runs the wrapper against an in-memory FakeClient instead of the
+// MNEE cosigner, so no network access or API key is needed. MNEE_ENV may also
+// be an http(s) base URL, such as a cmd/fakemnee server.
+const EnvOffline = "offline"
+
+// MneeClient is the subset of the MNEE SDK used by the handlers, plus
+// GetTicket and the Network addresses are checked against. sdkClient adds
+// them to *mnee.MNEE; FakeClient implements it in memory.
+type MneeClient interface {
+	GetBalances(ctx context.Context, addresses []string) ([]mnee.BalanceDataDTO, error)
+	GetConfig(ctx context.Context) (*mnee.SystemConfig, error)
+	GetSpecificTransactionHistory(ctx context.Context, addresses []string, from int, limit int) ([]mnee.TransactionHistoryDTO, error)
+	GetUnspentTxos(ctx context.Context, addresses []string) ([]mnee.MneeTxo, error)
+	GetPaginatedUnspentTxos(ctx context.Context, addresses []string, page int, size int) ([]mnee.MneeTxo, error)
+	PollTicket(ctx context.Context, ticketID string, pollingInterval time.Duration) (*mnee.Ticket, error)
+	GetTicket(ctx context.Context, ticketID string) (*mnee.Ticket, error)
+	SynchronousTransfer(ctx context.Context, wifs []string, mneeTransferDTO []mnee.TransferMneeDTO, withTxos bool,
+		mneeTxos []mnee.MneeTxo) (*mnee.TransferResponseDTO, error)
+	AsynchronousTransfer(ctx context.Context, wifs []string, mneeTransferDTO []mnee.TransferMneeDTO, withTxos bool,
+		mneeTxos []mnee.MneeTxo, callbackURL *string, callbackSecret *string) (*string, error)
+	PartialSign(ctx context.Context, wifs []string, mneeTransferDTO []mnee.TransferMneeDTO, withTxos bool,
+		mneeTxos []mnee.MneeTxo) (*string, error)
+	SubmitRawTxSync(ctx context.Context, rawTxHex string) (*mnee.TransferResponseDTO, error)
+	SubmitRawTxAsync(ctx context.Context, rawTxHex string, callbackURL *string, callbackSecret *string) (*string, error)
+	Network() Network
+}
+
+var Instance MneeClient
+
+var ErrMissingApiKey = errors.New("an MNEE API key is required")
 
 func InitMneeService(cfg *config.Config) {
-	if cfg.MneeApiKey == "" {
+	switch cfg.CacheBackend {
+	case "memory":
+		ResponseCache = NewMemoryCache()
+	case "none":
+	default:
+		log.Fatalf("Invalid CACHE_BACKEND %q: use memory or none", cfg.CacheBackend)
+	}
+	ResponseCacheTTLs = CacheTTLs{Config: cfg.CacheTTLConfig, Balances: cfg.CacheTTLBalances, Utxos: cfg.CacheTTLUtxos}
+
+	timeouts, err := ParseTimeouts(cfg.SDKTimeouts)
+	if err != nil {
+		log.Fatalf("Invalid SDK_TIMEOUTS: %v", err)
+	}
+	SDKPolicy = Policy{
+		ReadTimeout:     cfg.SDKReadTimeout,
+		WriteTimeout:    cfg.SDKWriteTimeout,
+		Timeouts:        timeouts,
+		Retries:         cfg.SDKRetries,
+		RetryBackoff:    cfg.SDKRetryBackoff,
+		BreakerFailures: cfg.BreakerFailures,
+		BreakerCooldown: cfg.BreakerCooldown,
+	}
+
+	client, err := NewClient(cfg.MneeEnv, cfg.MneeApiKey, cfg.MneeFixture)
+	if errors.Is(err, ErrMissingApiKey) {
 		log.Fatal("MNEE_API_KEY is required in .env")
 	}
+	if err != nil {
+		log.Fatalf("Failed to initialize MNEE SDK: %v", err)
+	}
 
-	var targetEnv string
-	if strings.ToLower(cfg.MneeEnv) == "production" {
-		targetEnv = mnee.EnvMain
+	Instance = client
+	if sdkEnv, _, _ := ParseEnv(cfg.MneeEnv); isBaseURL(sdkEnv) {
+		log.Printf("MNEE SDK Initialized against %s", sdkEnv)
 	} else {
-		targetEnv = mnee.EnvSandbox
+		log.Printf("MNEE SDK Initialized in %s mode", sdkEnv)
 	}
+}
 
-	var err error
-	Instance, err = mnee.NewMneeInstance(targetEnv, cfg.MneeApiKey)
+// NewClient builds a client for env, which ParseEnv must accept, with its
+// calls recorded in the metrics, run under SDKPolicy, its reads cached in
+// ResponseCache and the tickets it reads applied to the ledger. fixture only
+// applies offline.
+func NewClient(env string, apiKey string, fixture string) (MneeClient, error) {
+	client, err := newClient(env, apiKey, fixture)
 	if err != nil {
-		log.Fatalf("Failed to initialize MNEE SDK: %v", err)
+		return nil, err
 	}
 
-	log.Printf("MNEE SDK Initialized in %s mode", targetEnv)
+	namespace, _, _ := ParseEnv(env)
+	if namespace == EnvOffline {
+		namespace += strconv.FormatUint(offlineNamespaces.Add(1), 10)
+	}
+	breakerName := namespace
+	if u, err := url.Parse(namespace); err == nil && isBaseURL(namespace) {
+		breakerName = u.Redacted()
+	}
+
+	breaker := breakerFor(breakerName, SDKPolicy.BreakerFailures, SDKPolicy.BreakerCooldown)
+	client = withPolicy(instrument(client), SDKPolicy, breaker)
+	return withLedger(withCache(client, ResponseCache, ResponseCacheTTLs, namespace, apiKey)), nil
+}
+
+func newClient(env string, apiKey string, fixture string) (MneeClient, error) {
+	sdkEnv, network, err := ParseEnv(env)
+	if err != nil {
+		return nil, err
+	}
+
+	if sdkEnv == EnvOffline {
+		fake := NewFakeClient()
+		if fixture != "" {
+			if err := fake.LoadFixture(fixture); err != nil {
+				return nil, fmt.Errorf("loading MNEE fixture: %w", err)
+			}
+		}
+		return fake, nil
+	}
+
+	if isBaseURL(sdkEnv) {
+		instance, err := newMneeInstanceWithURL(sdkEnv, apiKey)
+		if err != nil {
+			return nil, err
+		}
+		return newSDKClient(instance, apiKey, network)
+	}
+
+	if apiKey == "" {
+		return nil, ErrMissingApiKey
+	}
+
+	instance, err := mnee.NewMneeInstance(sdkEnv, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	return newSDKClient(instance, apiKey, network)
 }
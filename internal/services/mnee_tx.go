@@ -0,0 +1,269 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/bits"
+	"slices"
+	"strconv"
+
+	primitives "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+)
+
+var (
+	ErrNotMneeOutput  = errors.New("output is not an MNEE transfer inscription")
+	ErrAmountOverflow = errors.New("amounts add up to more than the largest possible amount")
+)
+
+// cosignLockingScript mirrors the SDK's lock: a P2PKH script that additionally
+// requires the approver's signature.
+func cosignLockingScript(address *script.Address, approver *primitives.PublicKey) (*script.Script, error) {
+	if len(address.PublicKeyHash) != 20 {
+		return nil, mnee.ErrInvalidPublicKeyHash
+	}
+
+	var s script.Script
+	_ = s.AppendOpcodes(script.OpDUP, script.OpHASH160)
+	if err := s.AppendPushData(address.PublicKeyHash); err != nil {
+		return nil, err
+	}
+	_ = s.AppendOpcodes(script.OpEQUALVERIFY, script.OpCHECKSIGVERIFY)
+	if err := s.AppendPushData(approver.Compressed()); err != nil {
+		return nil, err
+	}
+	_ = s.AppendOpcodes(script.OpCHECKSIG)
+
+	return &s, nil
+}
+
+// inscribeTransfer appends a BSV-20 transfer output of amount to tx, locked to address.
+func inscribeTransfer(tx *transaction.Transaction, config *mnee.SystemConfig, approver *primitives.PublicKey, address string, amount uint64) error {
+	addr, err := script.NewAddressFromString(address)
+	if err != nil {
+		return err
+	}
+
+	lockingScript, err := cosignLockingScript(addr, approver)
+	if err != nil {
+		return err
+	}
+
+	inscription, err := json.Marshal(map[string]string{
+		"p":   string(mnee.BSV20),
+		"op":  string(mnee.TRANSFER),
+		"id":  *config.TokenId,
+		"amt": strconv.FormatUint(amount, 10),
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Inscribe(&script.InscriptionArgs{
+		ContentType:   "application/bsv-20",
+		Data:          inscription,
+		LockingScript: lockingScript,
+	})
+}
+
+// parseTransferOutput extracts the owner address and token amount from an MNEE
+// transfer output script.
+func parseTransferOutput(lockingScript *script.Script) (string, uint64, error) {
+	chunks, err := lockingScript.Chunks()
+	if err != nil {
+		return "", 0, err
+	}
+
+	// OP_FALSE OP_IF "ord" OP_1 <content-type> OP_0 <data> OP_ENDIF OP_DUP OP_HASH160 <pkh> ...
+	if len(chunks) < 11 || chunks[0].Op != script.OpFALSE || chunks[1].Op != script.OpIF ||
+		string(chunks[2].Data) != transaction.OrdinalsPrefix || chunks[7].Op != script.OpENDIF ||
+		chunks[8].Op != script.OpDUP || chunks[9].Op != script.OpHASH160 || len(chunks[10].Data) != 20 {
+		return "", 0, ErrNotMneeOutput
+	}
+
+	var inscription mnee.TransferTokenInscription
+	if err := json.Unmarshal(chunks[6].Data, &inscription); err != nil {
+		return "", 0, ErrNotMneeOutput
+	}
+	if inscription.Protocol != mnee.BSV20 || inscription.Operation != mnee.TRANSFER {
+		return "", 0, ErrNotMneeOutput
+	}
+
+	amount, err := strconv.ParseUint(inscription.Amount, 10, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid inscription amount %q: %w", inscription.Amount, err)
+	}
+
+	address, err := script.NewAddressFromPublicKeyHash(chunks[10].Data, true)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return address.AddressString, amount, nil
+}
+
+// ResolveFee returns the cosigner fee tier covering amount, if any.
+func ResolveFee(fees []mnee.Fee, amount uint64) (mnee.Fee, bool) {
+	for _, fee := range fees {
+		if amount >= fee.MinAmt && amount <= fee.MaxAmt {
+			return fee, true
+		}
+	}
+	return mnee.Fee{}, false
+}
+
+// feeForAmount returns the fee charged on amount. Amounts outside every tier
+// carry no fee, which matches how the SDK builds transfers.
+func feeForAmount(fees []mnee.Fee, amount uint64) uint64 {
+	fee, _ := ResolveFee(fees, amount)
+	return fee.Fee
+}
+
+// TotalAmount returns the sum of the amounts of mneeTransferDTO, or
+// ErrAmountOverflow if it does not fit in a uint64.
+func TotalAmount(mneeTransferDTO []mnee.TransferMneeDTO) (uint64, error) {
+	return ChargeableAmount(mneeTransferDTO, nil)
+}
+
+// ChargeableAmount returns the sum of the amounts sent to addresses other
+// than sources, on which the fee tier is resolved.
+func ChargeableAmount(mneeTransferDTO []mnee.TransferMneeDTO, sources []string) (uint64, error) {
+	var total uint64
+	for _, dto := range mneeTransferDTO {
+		if slices.Contains(sources, dto.Address) {
+			continue
+		}
+		var err error
+		if total, err = addAmounts(total, dto.Amount); err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+// addAmounts returns a+b, or ErrAmountOverflow if it does not fit in a uint64.
+func addAmounts(a, b uint64) (uint64, error) {
+	sum, carry := bits.Add64(a, b, 0)
+	if carry != 0 {
+		return 0, ErrAmountOverflow
+	}
+	return sum, nil
+}
+
+// selectInputs walks txos in order, taking those canSpend accepts until they
+// cover the transfer plus the fee. As in the SDK, the fee tier is resolved on
+// the amount sent to addresses other than the ones being spent from. The
+// selected inputs never amount to less than the transfer plus the fee, so the
+// change is their difference.
+func selectInputs(txos []mnee.MneeTxo, canSpend func(owner string) bool, mneeTransferDTO []mnee.TransferMneeDTO,
+	fees []mnee.Fee) ([]mnee.MneeTxo, uint64, uint64, error) {
+
+	totalTransferAmt, err := TotalAmount(mneeTransferDTO)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	var selected []mnee.MneeTxo
+	var inputAddresses []string
+	var totalInputAmount uint64
+	for _, txo := range txos {
+		if txo.Data == nil || txo.Data.Bsv21 == nil || txo.Txid == nil || txo.Script == nil ||
+			txo.Data.Bsv21.Amt == 0 || len(txo.Owners) == 0 || !canSpend(txo.Owners[0]) {
+			continue
+		}
+
+		selected = append(selected, txo)
+		if totalInputAmount, err = addAmounts(totalInputAmount, txo.Data.Bsv21.Amt); err != nil {
+			return nil, 0, 0, err
+		}
+		if !slices.Contains(inputAddresses, txo.Owners[0]) {
+			inputAddresses = append(inputAddresses, txo.Owners[0])
+		}
+
+		// Sending to inputAddresses is a subset of totalTransferAmt, so it
+		// cannot overflow.
+		actualTransferAmt, _ := ChargeableAmount(mneeTransferDTO, inputAddresses)
+
+		fee := feeForAmount(fees, actualTransferAmt)
+		required, err := addAmounts(totalTransferAmt, fee)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		if totalInputAmount >= required {
+			return selected, totalInputAmount, fee, nil
+		}
+	}
+
+	return nil, 0, 0, mnee.ErrInsufficientMneeBalance
+}
+
+// WifAddresses returns the addresses of wifs, skipping invalid keys.
+func WifAddresses(wifs []string) []string {
+	addresses := make([]string, 0, len(wifs))
+	for _, wif := range wifs {
+		privateKey, err := primitives.PrivateKeyFromWif(wif)
+		if err != nil {
+			continue
+		}
+		if address, err := script.NewAddressFromPublicKey(privateKey.PubKey(), true); err == nil {
+			addresses = append(addresses, address.AddressString)
+		}
+	}
+	return addresses
+}
+
+// txSigners returns the distinct addresses that signed tx's inputs.
+func txSigners(tx *transaction.Transaction) []string {
+	var addresses []string
+	for _, input := range tx.Inputs {
+		if input.UnlockingScript == nil {
+			continue
+		}
+		chunks, err := input.UnlockingScript.Chunks()
+		if err != nil || len(chunks) == 0 {
+			continue
+		}
+		// The signer's public key is the last push of a P2PKH unlock.
+		publicKey, err := primitives.PublicKeyFromBytes(chunks[len(chunks)-1].Data)
+		if err != nil {
+			continue
+		}
+		address, err := script.NewAddressFromPublicKey(publicKey, true)
+		if err == nil && !slices.Contains(addresses, address.AddressString) {
+			addresses = append(addresses, address.AddressString)
+		}
+	}
+	return addresses
+}
+
+// TxSummary is what an MNEE transaction moves.
+type TxSummary struct {
+	Sources    []string
+	Recipients []mnee.TransferMneeDTO
+	Fee        uint64
+}
+
+// SummarizeTx reads the MNEE outputs of rawTxHex. Outputs to feeAddress are
+// the fee and outputs back to a signer are change; the rest go to recipients.
+func SummarizeTx(rawTxHex string, feeAddress string) (*TxSummary, error) {
+	tx, err := transaction.NewTransactionFromHex(rawTxHex)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &TxSummary{Sources: txSigners(tx)}
+	for _, output := range tx.Outputs {
+		address, amount, err := parseTransferOutput(output.LockingScript)
+		switch {
+		case err != nil:
+		case address == feeAddress:
+			summary.Fee += amount
+		case !slices.Contains(summary.Sources, address):
+			summary.Recipients = append(summary.Recipients, mnee.TransferMneeDTO{Address: address, Amount: amount})
+		}
+	}
+	return summary, nil
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"testing"
+
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+)
+
+var testFees = []mnee.Fee{
+	{MinAmt: 0, MaxAmt: 1000000, Fee: 100},
+	{MinAmt: 1000001, MaxAmt: math.MaxUint64, Fee: 1000},
+}
+
+func testTxo(owner string, amount uint64) mnee.MneeTxo {
+	txid := owner + strconv.FormatUint(amount, 10)
+	script := ""
+	return mnee.MneeTxo{Txid: &txid, Script: &script, Owners: []string{owner}, Data: &mnee.Data{Bsv21: &mnee.BsvData{Amt: amount}}}
+}
+
+func TestTotalAmount(t *testing.T) {
+	tests := []struct {
+		name    string
+		dtos    []mnee.TransferMneeDTO
+		sources []string
+		total   uint64
+		charged uint64
+		err     error
+	}{
+		{name: "empty"},
+		{
+			name:    "to others",
+			dtos:    []mnee.TransferMneeDTO{{Address: "b", Amount: 10}, {Address: "c", Amount: 5}},
+			sources: []string{"a"},
+			total:   15,
+			charged: 15,
+		},
+		{
+			name:    "back to a source",
+			dtos:    []mnee.TransferMneeDTO{{Address: "a", Amount: 10}, {Address: "c", Amount: 5}},
+			sources: []string{"a"},
+			total:   15,
+			charged: 5,
+		},
+		{
+			name:    "largest",
+			dtos:    []mnee.TransferMneeDTO{{Address: "b", Amount: math.MaxUint64 - 1}, {Address: "c", Amount: 1}},
+			total:   math.MaxUint64,
+			charged: math.MaxUint64,
+		},
+		{
+			name: "overflow",
+			dtos: []mnee.TransferMneeDTO{{Address: "b", Amount: math.MaxUint64}, {Address: "c", Amount: 1}},
+			err:  ErrAmountOverflow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			total, err := TotalAmount(tt.dtos)
+			if !errors.Is(err, tt.err) || total != tt.total {
+				t.Fatalf("TotalAmount = %d, %v; want %d, %v", total, err, tt.total, tt.err)
+			}
+			charged, err := ChargeableAmount(tt.dtos, tt.sources)
+			if !errors.Is(err, tt.err) || charged != tt.charged {
+				t.Fatalf("ChargeableAmount = %d, %v; want %d, %v", charged, err, tt.charged, tt.err)
+			}
+		})
+	}
+}
+
+func TestSelectInputs(t *testing.T) {
+	txos := []mnee.MneeTxo{
+		testTxo("a", 5000),
+		testTxo("c", 7),
+		{Owners: []string{"a"}},
+		testTxo("a", 0),
+		testTxo("b", 10000),
+		testTxo("a", 200000),
+	}
+	huge := []mnee.MneeTxo{testTxo("a", math.MaxUint64-10), testTxo("a", 20)}
+
+	tests := []struct {
+		name     string
+		txos     []mnee.MneeTxo
+		spenders []string
+		dtos     []mnee.TransferMneeDTO
+		selected int
+		input    uint64
+		fee      uint64
+		err      error
+	}{
+		{
+			name:     "first covers",
+			txos:     txos,
+			spenders: []string{"a"},
+			dtos:     []mnee.TransferMneeDTO{{Address: "x", Amount: 4900}},
+			selected: 1,
+			input:    5000,
+			fee:      100,
+		},
+		{
+			name:     "skips others' and empty utxos",
+			txos:     txos,
+			spenders: []string{"a"},
+			dtos:     []mnee.TransferMneeDTO{{Address: "x", Amount: 5000}},
+			selected: 2,
+			input:    205000,
+			fee:      100,
+		},
+		{
+			name:     "several signers",
+			txos:     txos,
+			spenders: []string{"a", "b"},
+			dtos:     []mnee.TransferMneeDTO{{Address: "x", Amount: 10000}},
+			selected: 2,
+			input:    15000,
+			fee:      100,
+		},
+		{
+			name:     "higher tier",
+			txos:     []mnee.MneeTxo{testTxo("a", 2000000)},
+			spenders: []string{"a"},
+			dtos:     []mnee.TransferMneeDTO{{Address: "x", Amount: 1500000}},
+			selected: 1,
+			input:    2000000,
+			fee:      1000,
+		},
+		{
+			name:     "tier on what leaves the signers",
+			txos:     []mnee.MneeTxo{testTxo("a", 2000000)},
+			spenders: []string{"a"},
+			dtos:     []mnee.TransferMneeDTO{{Address: "a", Amount: 1500000}, {Address: "x", Amount: 10}},
+			selected: 1,
+			input:    2000000,
+			fee:      100,
+		},
+		{
+			name:     "insufficient",
+			txos:     txos,
+			spenders: []string{"a"},
+			dtos:     []mnee.TransferMneeDTO{{Address: "x", Amount: 300000}},
+			err:      mnee.ErrInsufficientMneeBalance,
+		},
+		{
+			name:     "transfer overflows",
+			txos:     txos,
+			spenders: []string{"a"},
+			dtos:     []mnee.TransferMneeDTO{{Address: "x", Amount: math.MaxUint64}, {Address: "y", Amount: 1}},
+			err:      ErrAmountOverflow,
+		},
+		{
+			name:     "fee overflows",
+			txos:     huge,
+			spenders: []string{"a"},
+			dtos:     []mnee.TransferMneeDTO{{Address: "x", Amount: math.MaxUint64 - 500}},
+			err:      ErrAmountOverflow,
+		},
+		{
+			name:     "inputs overflow",
+			txos:     huge,
+			spenders: []string{"a"},
+			dtos:     []mnee.TransferMneeDTO{{Address: "x", Amount: math.MaxUint64 - 1000}},
+			err:      ErrAmountOverflow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, input, fee, err := selectInputs(tt.txos, func(owner string) bool {
+				return slices.Contains(tt.spenders, owner)
+			}, tt.dtos, testFees)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if len(selected) != tt.selected || input != tt.input || fee != tt.fee {
+				t.Fatalf("got %d inputs of %d with fee %d, want %d of %d with fee %d", len(selected), input, fee, tt.selected, tt.input, tt.fee)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+
+	base58 "github.com/bsv-blockchain/go-sdk/compat/base58"
+	"github.com/bsv-blockchain/go-sdk/script"
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+)
+
+// Network is the set of addresses an environment accepts. The SDK derives
+// mainnet addresses from keys in every environment, so NetworkTestnet
+// accepts those as well as testnet addresses, while NetworkMainnet only
+// accepts mainnet addresses.
+type Network string
+
+const (
+	NetworkMainnet Network = "mainnet"
+	NetworkTestnet Network = "testnet"
+)
+
+const mainnetP2PKH byte = 0x00
+
+var (
+	ErrWrongNetwork = errors.New("address belongs to another network")
+	ErrBadChecksum  = errors.New("address checksum does not match")
+)
+
+// ParseEnv returns the SDK environment and network for env, one of
+// EnvOffline, an http(s) base URL, "production" or "sandbox". Anything else
+// is rejected rather than silently run against the sandbox.
+func ParseEnv(env string) (string, Network, error) {
+	switch {
+	case strings.EqualFold(env, EnvOffline):
+		return EnvOffline, NetworkTestnet, nil
+	case isBaseURL(env):
+		return env, NetworkTestnet, nil
+	case strings.EqualFold(env, "production"):
+		return mnee.EnvMain, NetworkMainnet, nil
+	case strings.EqualFold(env, "sandbox"):
+		return mnee.EnvSandbox, NetworkTestnet, nil
+	}
+	return "", "", fmt.Errorf("%w: %q; want production, sandbox, %s or an http(s) URL", mnee.ErrInvalidEnvironment, env, EnvOffline)
+}
+
+// ParseAddress parses a P2PKH address and checks that network accepts it.
+// The SDK does not verify the checksum, so a mistyped address would decode
+// to another, unspendable key hash; it is checked here.
+func ParseAddress(network Network, address string) (*script.Address, error) {
+	parsed, err := script.NewAddressFromString(address)
+	if err != nil {
+		return nil, err
+	}
+
+	decoded, _ := base58.Decode(address)
+	first := sha256.Sum256(decoded[:len(decoded)-4])
+	second := sha256.Sum256(first[:])
+	if !bytes.Equal(second[:4], decoded[len(decoded)-4:]) {
+		return nil, fmt.Errorf("%w: %s", ErrBadChecksum, address)
+	}
+
+	if network == NetworkMainnet && decoded[0] != mainnetP2PKH {
+		return nil, fmt.Errorf("%w: %s is not a %s address", ErrWrongNetwork, address, network)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	primitives "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+)
+
+func TestParseEnv(t *testing.T) {
+	tests := []struct {
+		env     string
+		sdkEnv  string
+		network Network
+		err     bool
+	}{
+		{env: "production", sdkEnv: mnee.EnvMain, network: NetworkMainnet},
+		{env: "Production", sdkEnv: mnee.EnvMain, network: NetworkMainnet},
+		{env: "sandbox", sdkEnv: mnee.EnvSandbox, network: NetworkTestnet},
+		{env: "SANDBOX", sdkEnv: mnee.EnvSandbox, network: NetworkTestnet},
+		{env: "offline", sdkEnv: EnvOffline, network: NetworkTestnet},
+		{env: "Offline", sdkEnv: EnvOffline, network: NetworkTestnet},
+		{env: "http://localhost:8081", sdkEnv: "http://localhost:8081", network: NetworkTestnet},
+		{env: "https://mnee.internal/api", sdkEnv: "https://mnee.internal/api", network: NetworkTestnet},
+		{env: "", err: true},
+		{env: "staging", err: true},
+		{env: "main", err: true},
+		{env: "ftp://localhost", err: true},
+		{env: "http://", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			sdkEnv, network, err := ParseEnv(tt.env)
+			if tt.err {
+				if !errors.Is(err, mnee.ErrInvalidEnvironment) {
+					t.Fatalf("ParseEnv(%q) = %q, %q, %v; want ErrInvalidEnvironment", tt.env, sdkEnv, network, err)
+				}
+				return
+			}
+			if err != nil || sdkEnv != tt.sdkEnv || network != tt.network {
+				t.Fatalf("ParseEnv(%q) = %q, %q, %v; want %q, %q", tt.env, sdkEnv, network, err, tt.sdkEnv, tt.network)
+			}
+		})
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	privateKey, err := primitives.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mainnet, err := script.NewAddressFromPublicKey(privateKey.PubKey(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testnet, err := script.NewAddressFromPublicKey(privateKey.PubKey(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Changing the last character breaks the checksum.
+	corrupted := []byte(mainnet.AddressString)
+	if corrupted[len(corrupted)-1] == '1' {
+		corrupted[len(corrupted)-1] = '2'
+	} else {
+		corrupted[len(corrupted)-1] = '1'
+	}
+
+	tests := []struct {
+		name    string
+		network Network
+		address string
+		wrong   bool
+		invalid bool
+		err     error
+	}{
+		{name: "mainnet on mainnet", network: NetworkMainnet, address: mainnet.AddressString},
+		{name: "testnet on mainnet", network: NetworkMainnet, address: testnet.AddressString, wrong: true},
+		{name: "mainnet on testnet", network: NetworkTestnet, address: mainnet.AddressString},
+		{name: "testnet on testnet", network: NetworkTestnet, address: testnet.AddressString},
+		{name: "garbage", network: NetworkTestnet, address: "not-an-address", invalid: true},
+		{name: "empty", network: NetworkMainnet, address: "", invalid: true},
+		{name: "bad checksum", network: NetworkMainnet, address: string(corrupted), invalid: true, err: ErrBadChecksum},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseAddress(tt.network, tt.address)
+			switch {
+			case tt.wrong:
+				if !errors.Is(err, ErrWrongNetwork) {
+					t.Fatalf("ParseAddress(%s, %q) error = %v, want ErrWrongNetwork", tt.network, tt.address, err)
+				}
+			case tt.invalid:
+				if err == nil || errors.Is(err, ErrWrongNetwork) || (tt.err != nil && !errors.Is(err, tt.err)) {
+					t.Fatalf("ParseAddress(%s, %q) error = %v, want a parse error", tt.network, tt.address, err)
+				}
+			case err != nil:
+				t.Fatalf("ParseAddress(%s, %q): %v", tt.network, tt.address, err)
+			case parsed.AddressString != tt.address:
+				t.Fatalf("ParseAddress(%s, %q) = %s", tt.network, tt.address, parsed.AddressString)
+			}
+		})
+	}
+}
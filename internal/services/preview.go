@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+
+	primitives "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/bsv-blockchain/go-sdk/transaction"
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+)
+
+// PreviewTransfer builds and signs a transfer exactly as the SDK would submit
+// it, without submitting, and breaks the signed transaction down into the
+// inputs it spends and the outputs it creates. The UTXOs are fetched once and
+// handed to PartialSign so the breakdown matches the transaction it built.
+func PreviewTransfer(ctx context.Context, client MneeClient, wifs []string, mneeTransferDTO []mnee.TransferMneeDTO) (*TransferPlan, error) {
+	addresses := make([]string, 0, len(wifs))
+	for _, wif := range wifs {
+		privateKey, err := primitives.PrivateKeyFromWif(wif)
+		if err != nil {
+			return nil, err
+		}
+
+		address, err := script.NewAddressFromPublicKey(privateKey.PubKey(), true)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, address.AddressString)
+	}
+
+	config, err := client.GetConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.FeeAddress == nil {
+		return nil, mnee.ErrInvalidConfig
+	}
+
+	txos, err := client.GetUnspentTxos(WithoutCache(ctx), addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	rawTx, err := client.PartialSign(ctx, wifs, mneeTransferDTO, true, txos)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := transaction.NewTransactionFromHex(*rawTx)
+	if err != nil {
+		return nil, err
+	}
+
+	spendable := make(map[string]mnee.MneeTxo, len(txos))
+	for _, txo := range txos {
+		if txo.Txid != nil {
+			spendable[fmt.Sprintf("%s_%d", *txo.Txid, txo.Vout)] = txo
+		}
+	}
+
+	plan := &TransferPlan{Tx: tx}
+
+	var totalInputAmount uint64
+	for _, input := range tx.Inputs {
+		outpoint := fmt.Sprintf("%s_%d", input.SourceTXID.String(), input.SourceTxOutIndex)
+		txo, ok := spendable[outpoint]
+		if !ok || txo.Data == nil || txo.Data.Bsv21 == nil || len(txo.Owners) == 0 {
+			return nil, fmt.Errorf("signed transfer spends unknown input %s", outpoint)
+		}
+
+		in := TransferInput{
+			Outpoint:     outpoint,
+			Address:      txo.Owners[0],
+			AtomicAmount: txo.Data.Bsv21.Amt,
+			Satoshis:     uint64(txo.Satoshis),
+		}
+		if txo.Script != nil {
+			if scriptBytes, err := base64.StdEncoding.DecodeString(*txo.Script); err == nil {
+				in.LockingScript = hex.EncodeToString(scriptBytes)
+			}
+		}
+
+		plan.Inputs = append(plan.Inputs, in)
+		totalInputAmount += in.AtomicAmount
+	}
+
+	// The SDK writes recipients first, then the fee, then any change.
+	var totalOutputAmount uint64
+	for vout, output := range tx.Outputs {
+		address, amount, err := parseTransferOutput(output.LockingScript)
+		if err != nil {
+			return nil, err
+		}
+
+		kind := OutputRecipient
+		switch {
+		case vout < len(mneeTransferDTO):
+		case vout == len(mneeTransferDTO) && address == *config.FeeAddress:
+			kind = OutputFee
+			plan.Fee = amount
+		default:
+			kind = OutputChange
+			plan.Change += amount
+		}
+
+		plan.Outputs = append(plan.Outputs, TransferOutput{
+			Vout:         vout,
+			Address:      address,
+			AtomicAmount: amount,
+			Kind:         kind,
+		})
+		totalOutputAmount += amount
+	}
+
+	// The SDK signs transfers whose inputs cover the amount but not the fee;
+	// the cosigner would reject them, so a preview does too.
+	if totalInputAmount != totalOutputAmount || plan.Fee < feeForAmount(config.Fees, chargeableAmount(plan.Inputs, mneeTransferDTO)) {
+		return nil, mnee.ErrInsufficientMneeBalance
+	}
+
+	return plan, nil
+}
+
+// chargeableAmount is the part of a transfer the fee tier is resolved on: the
+// amount sent to addresses other than the ones being spent from.
+func chargeableAmount(inputs []TransferInput, mneeTransferDTO []mnee.TransferMneeDTO) uint64 {
+	owners := make(map[string]bool, len(inputs))
+	for _, in := range inputs {
+		owners[in.Address] = true
+	}
+
+	var amount uint64
+	for _, dto := range mneeTransferDTO {
+		if !owners[dto.Address] {
+			amount += dto.Amount
+		}
+	}
+	return amount
+}
@@ -0,0 +1,414 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/metrics"
+)
+
+// ErrCircuitOpen is returned without calling the cosigner while its circuit
+// breaker is open.
+var ErrCircuitOpen = errors.New("the MNEE cosigner is unavailable; not calling it until the circuit breaker closes")
+
+// Policy sets the timeouts, retries and circuit breaker of SDK calls.
+type Policy struct {
+	// ReadTimeout and WriteTimeout bound each call to a read or write method,
+	// unless Timeouts names the method. PollTicket waits for the ticket and is
+	// only bounded when Timeouts names it; unbounded calls never probe the
+	// circuit breaker.
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	Timeouts     map[string]time.Duration
+
+	// Retries is how many times a read failing because the cosigner seems
+	// down is retried, after a random delay of up to RetryBackoff doubled on
+	// every attempt.
+	Retries      int
+	RetryBackoff time.Duration
+
+	// BreakerFailures consecutive failures open the circuit breaker for
+	// BreakerCooldown, after which one call is let through to probe.
+	BreakerFailures int
+	BreakerCooldown time.Duration
+}
+
+// SDKPolicy applies to the clients NewClient builds.
+var SDKPolicy = Policy{
+	ReadTimeout:     10 * time.Second,
+	WriteTimeout:    60 * time.Second,
+	Retries:         2,
+	RetryBackoff:    200 * time.Millisecond,
+	BreakerFailures: 5,
+	BreakerCooldown: 30 * time.Second,
+}
+
+// readMethods are safe to retry: they do not change anything. PartialSign
+// reads config and UTXOs and signs locally, broadcasting nothing.
+var readMethods = map[string]bool{
+	"GetBalances":                   true,
+	"GetConfig":                     true,
+	"GetSpecificTransactionHistory": true,
+	"GetUnspentTxos":                true,
+	"GetPaginatedUnspentTxos":       true,
+	"GetTicket":                     true,
+	"PartialSign":                   true,
+}
+
+var writeMethods = map[string]bool{
+	"SynchronousTransfer":  true,
+	"AsynchronousTransfer": true,
+	"SubmitRawTxSync":      true,
+	"SubmitRawTxAsync":     true,
+}
+
+// ParseTimeouts parses per-method timeouts written as
+// "Method=duration,Method=duration".
+func ParseTimeouts(spec string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		method, value, ok := strings.Cut(entry, "=")
+		method = strings.TrimSpace(method)
+		if !ok || !(readMethods[method] || writeMethods[method] || method == "PollTicket") {
+			return nil, fmt.Errorf("%q is not Method=duration for an SDK method", entry)
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || timeout < 0 {
+			return nil, fmt.Errorf("invalid timeout for %s: %q", method, value)
+		}
+		timeouts[method] = timeout
+	}
+	return timeouts, nil
+}
+
+func (p Policy) timeout(method string) time.Duration {
+	if timeout, ok := p.Timeouts[method]; ok {
+		return timeout
+	}
+	switch {
+	case readMethods[method]:
+		return p.ReadTimeout
+	case writeMethods[method]:
+		return p.WriteTimeout
+	}
+	return 0
+}
+
+// cosignerStatusPattern matches the error the SDK returns for a status it
+// has no message for.
+var cosignerStatusPattern = regexp.MustCompile(`status received from mnee-cosigner -> 5\d\d`)
+
+// CosignerDown reports whether err means the cosigner could not answer, as
+// opposed to answering with a refusal.
+func CosignerDown(err error) bool {
+	var netErr net.Error
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.Is(err, context.Canceled):
+		return false
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr):
+		return true
+	case errors.As(err, &syntaxErr):
+		// A proxy in front of the cosigner answered with an error page.
+		return true
+	}
+	return cosignerStatusPattern.MatchString(err.Error())
+}
+
+// BreakerState is a circuit breaker's state: closed lets calls through, open
+// fails them fast and half-open lets one through to probe the cosigner.
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"
+	BreakerOpen     BreakerState = "open"
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// Breaker is the circuit breaker of one cosigner.
+type Breaker struct {
+	name     string
+	failures int
+	cooldown time.Duration
+
+	mutex       sync.Mutex
+	state       BreakerState
+	consecutive int
+	openedAt    time.Time
+	probing     bool
+}
+
+// BreakerStatus is a snapshot of a Breaker for the health endpoint.
+type BreakerStatus struct {
+	Name        string       `json:"name"`
+	State       BreakerState `json:"state"`
+	Failures    int          `json:"consecutiveFailures"`
+	OpenedAt    *time.Time   `json:"openedAt,omitempty"`
+	RetryAfterS int          `json:"retryAfterSeconds,omitempty"`
+}
+
+var (
+	breakersMutex sync.Mutex
+	breakers      = make(map[string]*Breaker)
+)
+
+// breakerFor returns the breaker shared by the clients of one cosigner.
+func breakerFor(name string, failures int, cooldown time.Duration) *Breaker {
+	breakersMutex.Lock()
+	defer breakersMutex.Unlock()
+
+	if breaker, ok := breakers[name]; ok {
+		return breaker
+	}
+	breaker := &Breaker{name: name, failures: failures, cooldown: cooldown, state: BreakerClosed}
+	breakers[name] = breaker
+	metrics.BreakerState.WithLabelValues(name).Set(0)
+	return breaker
+}
+
+// Breakers returns the state of every cosigner's circuit breaker, by name.
+func Breakers() []BreakerStatus {
+	breakersMutex.Lock()
+	list := make([]*Breaker, 0, len(breakers))
+	for _, breaker := range breakers {
+		list = append(list, breaker)
+	}
+	breakersMutex.Unlock()
+
+	statuses := make([]BreakerStatus, 0, len(list))
+	for _, breaker := range list {
+		statuses = append(statuses, breaker.Status())
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+func (b *Breaker) Status() BreakerStatus {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	status := BreakerStatus{Name: b.name, State: b.state, Failures: b.consecutive}
+	if b.state != BreakerClosed {
+		openedAt := b.openedAt.UTC()
+		status.OpenedAt = &openedAt
+		if wait := b.cooldown - time.Since(b.openedAt); wait > 0 {
+			status.RetryAfterS = int(wait.Round(time.Second).Seconds())
+		}
+	}
+	return status
+}
+
+// allow reports whether a call may go ahead, moving an open breaker whose
+// cooldown has passed to half-open for a single probe. A call that may not
+// probe, such as a PollTicket without a deadline, which could hold the probe
+// for as long as the ticket takes, only goes ahead while the breaker is
+// closed.
+func (b *Breaker) allow(mayProbe bool) bool {
+	if b.failures <= 0 {
+		return true
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if !mayProbe || time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.setState(BreakerHalfOpen)
+		b.probing = true
+		return true
+	case BreakerHalfOpen:
+		if !mayProbe || b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	}
+	return true
+}
+
+// record counts the outcome of a call allow let through.
+func (b *Breaker) record(err error) {
+	if b.failures <= 0 || errors.Is(err, context.Canceled) {
+		b.mutex.Lock()
+		b.probing = false
+		b.mutex.Unlock()
+		return
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.probing = false
+	if err == nil || !CosignerDown(err) {
+		b.consecutive = 0
+		b.setState(BreakerClosed)
+		return
+	}
+
+	b.consecutive++
+	if b.state == BreakerHalfOpen || b.consecutive >= b.failures {
+		b.openedAt = time.Now()
+		b.setState(BreakerOpen)
+	}
+}
+
+func (b *Breaker) setState(state BreakerState) {
+	b.state = state
+	value := map[BreakerState]float64{BreakerClosed: 0, BreakerHalfOpen: 1, BreakerOpen: 2}[state]
+	metrics.BreakerState.WithLabelValues(b.name).Set(value)
+}
+
+// resilientClient applies a Policy to the client it wraps.
+type resilientClient struct {
+	client  MneeClient
+	policy  Policy
+	breaker *Breaker
+}
+
+var _ MneeClient = resilientClient{}
+
+func withPolicy(client MneeClient, policy Policy, breaker *Breaker) MneeClient {
+	return resilientClient{client: client, policy: policy, breaker: breaker}
+}
+
+// runWithPolicy runs fn under the policy for method: through the breaker, with the
+// method's timeout, retried if it is a read.
+func runWithPolicy[T any](ctx context.Context, r resilientClient, method string, fn func(ctx context.Context) (T, error)) (T, error) {
+	attempts := 1
+	if readMethods[method] {
+		attempts += max(r.policy.Retries, 0)
+	}
+
+	var result T
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			metrics.SDKRetries.WithLabelValues(method).Inc()
+			backoff := r.policy.RetryBackoff << (attempt - 1)
+			select {
+			case <-time.After(time.Duration(rand.Int64N(int64(backoff) + 1))):
+			case <-ctx.Done():
+				return result, err
+			}
+		}
+
+		if !r.breaker.allow(r.policy.timeout(method) > 0) {
+			var zero T
+			return zero, ErrCircuitOpen
+		}
+
+		attemptCtx, cancel := r.withTimeout(ctx, method)
+		result, err = fn(attemptCtx)
+		cancel()
+		r.breaker.record(err)
+		if err == nil || !CosignerDown(err) || ctx.Err() != nil {
+			return result, err
+		}
+	}
+	return result, err
+}
+
+// withTimeout bounds ctx by the method's timeout, if it has one.
+func (r resilientClient) withTimeout(ctx context.Context, method string) (context.Context, context.CancelFunc) {
+	if timeout := r.policy.timeout(method); timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return ctx, func() {}
+}
+
+func (r resilientClient) Network() Network {
+	return r.client.Network()
+}
+
+func (r resilientClient) GetBalances(ctx context.Context, addresses []string) ([]mnee.BalanceDataDTO, error) {
+	return runWithPolicy(ctx, r, "GetBalances", func(ctx context.Context) ([]mnee.BalanceDataDTO, error) {
+		return r.client.GetBalances(ctx, addresses)
+	})
+}
+
+func (r resilientClient) GetConfig(ctx context.Context) (*mnee.SystemConfig, error) {
+	return runWithPolicy(ctx, r, "GetConfig", func(ctx context.Context) (*mnee.SystemConfig, error) {
+		return r.client.GetConfig(ctx)
+	})
+}
+
+func (r resilientClient) GetSpecificTransactionHistory(ctx context.Context, addresses []string, from int, limit int) ([]mnee.TransactionHistoryDTO, error) {
+	return runWithPolicy(ctx, r, "GetSpecificTransactionHistory", func(ctx context.Context) ([]mnee.TransactionHistoryDTO, error) {
+		return r.client.GetSpecificTransactionHistory(ctx, addresses, from, limit)
+	})
+}
+
+func (r resilientClient) GetUnspentTxos(ctx context.Context, addresses []string) ([]mnee.MneeTxo, error) {
+	return runWithPolicy(ctx, r, "GetUnspentTxos", func(ctx context.Context) ([]mnee.MneeTxo, error) {
+		return r.client.GetUnspentTxos(ctx, addresses)
+	})
+}
+
+func (r resilientClient) GetPaginatedUnspentTxos(ctx context.Context, addresses []string, page int, size int) ([]mnee.MneeTxo, error) {
+	return runWithPolicy(ctx, r, "GetPaginatedUnspentTxos", func(ctx context.Context) ([]mnee.MneeTxo, error) {
+		return r.client.GetPaginatedUnspentTxos(ctx, addresses, page, size)
+	})
+}
+
+func (r resilientClient) PollTicket(ctx context.Context, ticketID string, pollingInterval time.Duration) (*mnee.Ticket, error) {
+	return runWithPolicy(ctx, r, "PollTicket", func(ctx context.Context) (*mnee.Ticket, error) {
+		return r.client.PollTicket(ctx, ticketID, pollingInterval)
+	})
+}
+
+func (r resilientClient) GetTicket(ctx context.Context, ticketID string) (*mnee.Ticket, error) {
+	return runWithPolicy(ctx, r, "GetTicket", func(ctx context.Context) (*mnee.Ticket, error) {
+		return r.client.GetTicket(ctx, ticketID)
+	})
+}
+
+func (r resilientClient) SynchronousTransfer(ctx context.Context, wifs []string, mneeTransferDTO []mnee.TransferMneeDTO, withTxos bool,
+	mneeTxos []mnee.MneeTxo) (*mnee.TransferResponseDTO, error) {
+	return runWithPolicy(ctx, r, "SynchronousTransfer", func(ctx context.Context) (*mnee.TransferResponseDTO, error) {
+		return r.client.SynchronousTransfer(ctx, wifs, mneeTransferDTO, withTxos, mneeTxos)
+	})
+}
+
+func (r resilientClient) AsynchronousTransfer(ctx context.Context, wifs []string, mneeTransferDTO []mnee.TransferMneeDTO, withTxos bool,
+	mneeTxos []mnee.MneeTxo, callbackURL *string, callbackSecret *string) (*string, error) {
+	return runWithPolicy(ctx, r, "AsynchronousTransfer", func(ctx context.Context) (*string, error) {
+		return r.client.AsynchronousTransfer(ctx, wifs, mneeTransferDTO, withTxos, mneeTxos, callbackURL, callbackSecret)
+	})
+}
+
+func (r resilientClient) PartialSign(ctx context.Context, wifs []string, mneeTransferDTO []mnee.TransferMneeDTO, withTxos bool,
+	mneeTxos []mnee.MneeTxo) (*string, error) {
+	return runWithPolicy(ctx, r, "PartialSign", func(ctx context.Context) (*string, error) {
+		return r.client.PartialSign(ctx, wifs, mneeTransferDTO, withTxos, mneeTxos)
+	})
+}
+
+func (r resilientClient) SubmitRawTxSync(ctx context.Context, rawTxHex string) (*mnee.TransferResponseDTO, error) {
+	return runWithPolicy(ctx, r, "SubmitRawTxSync", func(ctx context.Context) (*mnee.TransferResponseDTO, error) {
+		return r.client.SubmitRawTxSync(ctx, rawTxHex)
+	})
+}
+
+func (r resilientClient) SubmitRawTxAsync(ctx context.Context, rawTxHex string, callbackURL *string, callbackSecret *string) (*string, error) {
+	return runWithPolicy(ctx, r, "SubmitRawTxAsync", func(ctx context.Context) (*string, error) {
+		return r.client.SubmitRawTxAsync(ctx, rawTxHex, callbackURL, callbackSecret)
+	})
+}
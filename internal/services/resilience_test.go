@@ -0,0 +1,269 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+)
+
+var errDown = errors.New("status received from mnee-cosigner -> 503")
+
+func TestCosignerDown(t *testing.T) {
+	var syntaxErr error = &json.SyntaxError{Offset: 1}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "5xx", err: errDown, want: true},
+		{name: "wrapped 5xx", err: fmt.Errorf("getting balances: %w", errDown), want: true},
+		{name: "deadline", err: context.DeadlineExceeded, want: true},
+		{name: "network", err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, want: true},
+		{name: "error page", err: fmt.Errorf("decoding: %w", syntaxErr), want: true},
+		{name: "canceled", err: context.Canceled},
+		{name: "4xx", err: errors.New("status received from mnee-cosigner -> 400")},
+		{name: "refusal", err: mnee.ErrInsufficientMneeBalance},
+	}
+
+	for _, tt := range tests {
+		if got := CosignerDown(tt.err); got != tt.want {
+			t.Errorf("%s: CosignerDown(%v) = %v, want %v", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestParseTimeouts(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    map[string]time.Duration
+		wantErr bool
+	}{
+		{spec: "", want: map[string]time.Duration{}},
+		{spec: "GetUnspentTxos=20s, PollTicket=2m", want: map[string]time.Duration{"GetUnspentTxos": 20 * time.Second, "PollTicket": 2 * time.Minute}},
+		{spec: "SubmitRawTxSync=0,", want: map[string]time.Duration{"SubmitRawTxSync": 0}},
+		{spec: "Unknown=1s", wantErr: true},
+		{spec: "GetConfig", wantErr: true},
+		{spec: "GetConfig=soon", wantErr: true},
+		{spec: "GetConfig=-1s", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseTimeouts(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTimeouts(%q) error = %v, want error %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("ParseTimeouts(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+		for method, timeout := range tt.want {
+			if got[method] != timeout {
+				t.Errorf("ParseTimeouts(%q)[%s] = %v, want %v", tt.spec, method, got[method], timeout)
+			}
+		}
+	}
+}
+
+func TestBreaker(t *testing.T) {
+	// A step is a call: whether allow lets it through and, if so, the
+	// error it ends with. cooled makes the cooldown pass before it, and
+	// unbounded makes it a call that may not probe.
+	type step struct {
+		cooled    bool
+		unbounded bool
+		allowed   bool
+		err       error
+		state     BreakerState
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "opens after consecutive failures",
+			steps: []step{
+				{allowed: true, err: errDown, state: BreakerClosed},
+				{allowed: true, err: errDown, state: BreakerClosed},
+				{allowed: true, err: errDown, state: BreakerOpen},
+				{allowed: false, state: BreakerOpen},
+			},
+		},
+		{
+			name: "success resets the count",
+			steps: []step{
+				{allowed: true, err: errDown, state: BreakerClosed},
+				{allowed: true, err: errDown, state: BreakerClosed},
+				{allowed: true, state: BreakerClosed},
+				{allowed: true, err: errDown, state: BreakerClosed},
+				{allowed: true, err: errDown, state: BreakerClosed},
+			},
+		},
+		{
+			name: "refusals do not count",
+			steps: []step{
+				{allowed: true, err: errDown, state: BreakerClosed},
+				{allowed: true, err: errDown, state: BreakerClosed},
+				{allowed: true, err: mnee.ErrInsufficientMneeBalance, state: BreakerClosed},
+				{allowed: true, err: errDown, state: BreakerClosed},
+			},
+		},
+		{
+			name: "cancellations do not count",
+			steps: []step{
+				{allowed: true, err: errDown, state: BreakerClosed},
+				{allowed: true, err: errDown, state: BreakerClosed},
+				{allowed: true, err: context.Canceled, state: BreakerClosed},
+				{allowed: true, err: errDown, state: BreakerOpen},
+			},
+		},
+		{
+			name: "successful probe closes",
+			steps: []step{
+				{allowed: true, err: errDown},
+				{allowed: true, err: errDown},
+				{allowed: true, err: errDown, state: BreakerOpen},
+				{cooled: true, allowed: true, state: BreakerClosed},
+				{allowed: true, state: BreakerClosed},
+			},
+		},
+		{
+			name: "failed probe reopens",
+			steps: []step{
+				{allowed: true, err: errDown},
+				{allowed: true, err: errDown},
+				{allowed: true, err: errDown, state: BreakerOpen},
+				{cooled: true, allowed: true, err: errDown, state: BreakerOpen},
+				{allowed: false, state: BreakerOpen},
+			},
+		},
+		{
+			name: "unbounded calls do not probe",
+			steps: []step{
+				{allowed: true, err: errDown},
+				{allowed: true, err: errDown},
+				{allowed: true, err: errDown, state: BreakerOpen},
+				{cooled: true, unbounded: true, allowed: false, state: BreakerOpen},
+				{cooled: true, allowed: true, state: BreakerClosed},
+				{unbounded: true, allowed: true, state: BreakerClosed},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breaker := &Breaker{name: "test", failures: 3, cooldown: time.Minute, state: BreakerClosed}
+			for i, s := range tt.steps {
+				if s.cooled {
+					breaker.openedAt = time.Now().Add(-time.Minute)
+				}
+				if allowed := breaker.allow(!s.unbounded); allowed != s.allowed {
+					t.Fatalf("step %d: allow() = %v, want %v", i, allowed, s.allowed)
+				}
+				if s.allowed {
+					breaker.record(s.err)
+				}
+				if s.state != "" && breaker.state != s.state {
+					t.Fatalf("step %d: state %s, want %s", i, breaker.state, s.state)
+				}
+			}
+		})
+	}
+}
+
+func TestBreakerSingleProbe(t *testing.T) {
+	breaker := &Breaker{name: "test", failures: 1, cooldown: time.Minute, state: BreakerClosed}
+	breaker.allow(true)
+	breaker.record(errDown)
+	breaker.openedAt = time.Now().Add(-time.Minute)
+
+	if !breaker.allow(true) {
+		t.Fatal("the probe was not let through")
+	}
+	if breaker.state != BreakerHalfOpen {
+		t.Fatalf("state %s, want %s", breaker.state, BreakerHalfOpen)
+	}
+	if breaker.allow(true) {
+		t.Fatal("a second call was let through while probing")
+	}
+	if breaker.allow(false) {
+		t.Fatal("an unbounded call was let through while probing")
+	}
+}
+
+func TestRunWithPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		errs   []error
+		calls  int
+		err    error
+	}{
+		{name: "read succeeds", method: "GetBalances", errs: []error{nil}, calls: 1},
+		{name: "read retried", method: "GetBalances", errs: []error{errDown, errDown, nil}, calls: 3},
+		{name: "read gives up", method: "GetBalances", errs: []error{errDown, errDown, errDown}, calls: 3, err: errDown},
+		{name: "refusal not retried", method: "GetBalances", errs: []error{mnee.ErrInsufficientMneeBalance}, calls: 1, err: mnee.ErrInsufficientMneeBalance},
+		{name: "write not retried", method: "SynchronousTransfer", errs: []error{errDown}, calls: 1, err: errDown},
+		{name: "partial sign retried", method: "PartialSign", errs: []error{errDown, nil}, calls: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := Policy{ReadTimeout: time.Second, WriteTimeout: time.Second, Retries: 2, RetryBackoff: time.Millisecond}
+			breaker := &Breaker{name: "test", failures: 10, cooldown: time.Minute, state: BreakerClosed}
+			r := resilientClient{policy: policy, breaker: breaker}
+
+			calls := 0
+			_, err := runWithPolicy(t.Context(), r, tt.method, func(ctx context.Context) (int, error) {
+				if _, ok := ctx.Deadline(); !ok {
+					t.Error("the call has no deadline")
+				}
+				calls++
+				return calls, tt.errs[calls-1]
+			})
+			if !errors.Is(err, tt.err) || calls != tt.calls {
+				t.Fatalf("got %v after %d calls, want %v after %d", err, calls, tt.err, tt.calls)
+			}
+		})
+	}
+
+	t.Run("unbounded poll does not probe", func(t *testing.T) {
+		breaker := &Breaker{name: "test", failures: 1, cooldown: time.Minute, state: BreakerOpen, openedAt: time.Now().Add(-time.Minute)}
+		r := resilientClient{policy: Policy{ReadTimeout: time.Second}, breaker: breaker}
+
+		_, err := runWithPolicy(t.Context(), r, "PollTicket", func(context.Context) (int, error) {
+			t.Fatal("an unbounded poll probed the cosigner")
+			return 0, nil
+		})
+		if !errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("got %v, want %v", err, ErrCircuitOpen)
+		}
+
+		r.policy.Timeouts = map[string]time.Duration{"PollTicket": time.Second}
+		if _, err := runWithPolicy(t.Context(), r, "PollTicket", func(context.Context) (int, error) { return 0, nil }); err != nil {
+			t.Fatalf("a bounded poll was not let through to probe: %v", err)
+		}
+	})
+
+	t.Run("open breaker", func(t *testing.T) {
+		breaker := &Breaker{name: "test", failures: 1, cooldown: time.Minute, state: BreakerOpen, openedAt: time.Now()}
+		r := resilientClient{policy: Policy{}, breaker: breaker}
+
+		_, err := runWithPolicy(t.Context(), r, "GetBalances", func(context.Context) (int, error) {
+			t.Fatal("called the cosigner while the breaker is open")
+			return 0, nil
+		})
+		if !errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("got %v, want %v", err, ErrCircuitOpen)
+		}
+	})
+}
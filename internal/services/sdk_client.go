@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+)
+
+// ErrTicketNotFound is returned by GetTicket when the cosigner has no record
+// of the ticket, which is also the case for a ticket it has not stored yet.
+var ErrTicketNotFound = errors.New("ticket not found")
+
+// sdkClient is the SDK plus a single, non-blocking ticket lookup. The SDK only
+// offers PollTicket, which keeps waiting while the cosigner has no record.
+type sdkClient struct {
+	*mnee.MNEE
+	baseURL    string
+	token      string
+	httpClient *http.Client
+	network    Network
+}
+
+var _ MneeClient = (*sdkClient)(nil)
+
+// newSDKClient wraps instance, which authenticates with token, so GetTicket
+// talks to the same cosigner with the same key.
+func newSDKClient(instance *mnee.MNEE, token string, network Network) (*sdkClient, error) {
+	baseURL, err := sdkBaseURL(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sdkClient{
+		MNEE:       instance,
+		baseURL:    baseURL,
+		token:      token,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		network:    network,
+	}, nil
+}
+
+func (s *sdkClient) Network() Network {
+	return s.network
+}
+
+func (s *sdkClient) GetTicket(ctx context.Context, ticketID string) (*mnee.Ticket, error) {
+	query := url.Values{"auth_token": {s.token}, "ticketID": {ticketID}}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+"/v2/ticket?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusForbidden {
+		return nil, mnee.ErrForbidden
+	}
+
+	if response.StatusCode != http.StatusOK {
+		var errorResponse struct {
+			Message string `json:"message"`
+		}
+		if err := json.NewDecoder(response.Body).Decode(&errorResponse); err != nil || errorResponse.Message == "" {
+			if response.StatusCode == http.StatusNotFound {
+				return nil, ErrTicketNotFound
+			}
+			return nil, fmt.Errorf("status received from mnee-cosigner -> %d", response.StatusCode)
+		}
+		if errorResponse.Message == "record not found" {
+			return nil, ErrTicketNotFound
+		}
+		return nil, errors.New(errorResponse.Message)
+	}
+
+	var ticket mnee.Ticket
+	if err := json.NewDecoder(response.Body).Decode(&ticket); err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
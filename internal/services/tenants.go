@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Tenant is an API client with its own MNEE API key and environment.
+type Tenant struct {
+	ClientID string
+	ApiKey   string
+	Env      string
+}
+
+// TenantRegistry maps API clients to their tenant's client. Each client is
+// built the first time its tenant makes a request and reused afterwards.
+// Clients without a tenant use Instance.
+type TenantRegistry struct {
+	tenants map[string]Tenant
+	fixture string
+
+	mutex   sync.Mutex
+	clients map[string]MneeClient
+}
+
+// Tenants is empty unless TENANTS is configured.
+var Tenants = NewTenantRegistry(nil, "")
+
+func NewTenantRegistry(tenants []Tenant, fixture string) *TenantRegistry {
+	r := &TenantRegistry{
+		tenants: make(map[string]Tenant, len(tenants)),
+		fixture: fixture,
+		clients: make(map[string]MneeClient),
+	}
+	for _, tenant := range tenants {
+		r.tenants[tenant.ClientID] = tenant
+	}
+	return r
+}
+
+// ParseTenants reads tenants from spec, a semicolon-separated list of
+// "<clientId>:<MNEE API key>:<environment>" entries. The environment takes
+// the same values as MNEE_ENV.
+func ParseTenants(spec string) ([]Tenant, error) {
+	var tenants []Tenant
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+			return nil, fmt.Errorf("invalid tenant entry %q: want <clientId>:<MNEE API key>:<environment>", parts[0])
+		}
+		if seen[parts[0]] {
+			return nil, fmt.Errorf("duplicate tenant %q", parts[0])
+		}
+		env, _, err := ParseEnv(parts[2])
+		if err != nil {
+			return nil, fmt.Errorf("tenant %q: %w", parts[0], err)
+		}
+		if parts[1] == "" && env != EnvOffline && !isBaseURL(env) {
+			return nil, fmt.Errorf("tenant %q: %w", parts[0], ErrMissingApiKey)
+		}
+
+		seen[parts[0]] = true
+		tenants = append(tenants, Tenant{ClientID: parts[0], ApiKey: parts[1], Env: parts[2]})
+	}
+	return tenants, nil
+}
+
+func (r *TenantRegistry) Len() int {
+	return len(r.tenants)
+}
+
+// Client returns the client for clientID's tenant, or Instance when
+// clientID has none.
+func (r *TenantRegistry) Client(clientID string) (MneeClient, error) {
+	tenant, ok := r.tenants[clientID]
+	if !ok {
+		return Instance, nil
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if client, ok := r.clients[clientID]; ok {
+		return client, nil
+	}
+
+	client, err := NewClient(tenant.Env, tenant.ApiKey, r.fixture)
+	if err != nil {
+		return nil, fmt.Errorf("tenant %s: %w", clientID, err)
+	}
+	r.clients[clientID] = client
+	return client, nil
+}
+
+type clientContextKey struct{}
+
+// WithClient returns a copy of ctx that carries client, for ClientFrom.
+func WithClient(ctx context.Context, client MneeClient) context.Context {
+	return context.WithValue(ctx, clientContextKey{}, client)
+}
+
+// ClientFrom returns the client a request runs against: its tenant's client
+// when one was set with WithClient, otherwise Instance.
+func ClientFrom(ctx context.Context) MneeClient {
+	if client, ok := ctx.Value(clientContextKey{}).(MneeClient); ok {
+		return client
+	}
+	return Instance
+}
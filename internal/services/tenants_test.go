@@ -0,0 +1,143 @@
+package services
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+)
+
+func TestParseTenants(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		want []Tenant
+		err  error
+	}{
+		{name: "empty", spec: ""},
+		{
+			name: "one tenant",
+			spec: "acme:key-1:production",
+			want: []Tenant{{ClientID: "acme", ApiKey: "key-1", Env: "production"}},
+		},
+		{
+			name: "several tenants with blanks",
+			spec: " acme:key-1:production ; ;globex:key-2:sandbox;",
+			want: []Tenant{
+				{ClientID: "acme", ApiKey: "key-1", Env: "production"},
+				{ClientID: "globex", ApiKey: "key-2", Env: "sandbox"},
+			},
+		},
+		{
+			name: "base URL with colons",
+			spec: "acme:key-1:http://localhost:8081",
+			want: []Tenant{{ClientID: "acme", ApiKey: "key-1", Env: "http://localhost:8081"}},
+		},
+		{
+			name: "offline without a key",
+			spec: "acme::offline",
+			want: []Tenant{{ClientID: "acme", Env: "offline"}},
+		},
+		{
+			name: "base URL without a key",
+			spec: "acme::http://localhost:8081",
+			want: []Tenant{{ClientID: "acme", Env: "http://localhost:8081"}},
+		},
+		{name: "hosted without a key", spec: "acme::production", err: ErrMissingApiKey},
+		{name: "unknown environment", spec: "acme:key-1:staging", err: mnee.ErrInvalidEnvironment},
+		{name: "missing environment", spec: "acme:key-1"},
+		{name: "missing client", spec: ":key-1:production"},
+		{name: "duplicate client", spec: "acme:key-1:production;acme:key-2:sandbox"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTenants(tt.spec)
+			wantErr := tt.err != nil || (tt.want == nil && tt.spec != "")
+			if wantErr {
+				if err == nil {
+					t.Fatalf("ParseTenants(%q) = %+v, want an error", tt.spec, got)
+				}
+				if tt.err != nil && !errors.Is(err, tt.err) {
+					t.Fatalf("ParseTenants(%q) error = %v, want %v", tt.spec, err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTenants(%q): %v", tt.spec, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ParseTenants(%q) = %+v, want %+v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTenantRegistry(t *testing.T) {
+	previous := Instance
+	t.Cleanup(func() { Instance = previous })
+	Instance = NewFakeClient()
+
+	registry := NewTenantRegistry([]Tenant{
+		{ClientID: "acme", Env: EnvOffline},
+		{ClientID: "globex", Env: EnvOffline},
+	}, "")
+	if registry.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", registry.Len())
+	}
+
+	// Every offline client is a fake with its own random token ID.
+	tokenID := func(clientID string) string {
+		t.Helper()
+		client, err := registry.Client(clientID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		config, err := client.GetConfig(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return *config.TokenId
+	}
+
+	acme := tokenID("acme")
+	if again := tokenID("acme"); again != acme {
+		t.Fatal("a tenant's client is not reused")
+	}
+	if globex := tokenID("globex"); globex == acme {
+		t.Fatal("tenants share a client")
+	}
+
+	other, err := registry.Client("initech")
+	if err != nil || other != Instance {
+		t.Fatalf("Client of a client without a tenant = %v, %v; want Instance", other, err)
+	}
+}
+
+func TestTenantRegistryFailure(t *testing.T) {
+	registry := NewTenantRegistry([]Tenant{{ClientID: "acme", Env: EnvOffline}}, filepath.Join(t.TempDir(), "missing.json"))
+
+	for range 2 {
+		// A failed client is not cached, so every request retries it.
+		if client, err := registry.Client("acme"); err == nil {
+			t.Fatalf("Client = %v, want an error for the missing fixture", client)
+		}
+	}
+}
+
+func TestClientFrom(t *testing.T) {
+	previous := Instance
+	t.Cleanup(func() { Instance = previous })
+	Instance = NewFakeClient()
+
+	if got := ClientFrom(t.Context()); got != Instance {
+		t.Fatalf("ClientFrom without a client = %v, want Instance", got)
+	}
+
+	tenant := NewFakeClient()
+	if got := ClientFrom(WithClient(t.Context(), tenant)); got != tenant {
+		t.Fatalf("ClientFrom = %v, want the tenant's client", got)
+	}
+}
@@ -0,0 +1,96 @@
+// Package tracing sets up OpenTelemetry tracing and the tracer the server's
+// spans come from.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+)
+
+// Tracer starts the server's spans. It is a no-op until Init installs an
+// exporter.
+var Tracer trace.Tracer = otel.Tracer("github.com/mnee-xyz/go-mnee-1sat-sdk-docker")
+
+// Init installs a tracer provider exporting to exporter and the W3C trace
+// context propagator. The OTLP exporter reads its endpoint and headers from
+// the standard OTEL_EXPORTER_OTLP_* variables; the file exporter appends
+// JSON spans to path. The returned function flushes and closes the exporter.
+func Init(exporter string, path string, serviceName string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var spanExporter sdktrace.SpanExporter
+	var file io.Closer
+	switch strings.ToLower(exporter) {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		otlp, err := otlptracehttp.New(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		spanExporter = otlp
+	case ExporterStdout:
+		stdout, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, err
+		}
+		spanExporter = stdout
+	case ExporterFile:
+		if path == "" {
+			return nil, fmt.Errorf("the %s exporter needs a file path", ExporterFile)
+		}
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, err
+		}
+		stdout, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		spanExporter = stdout
+		file = f
+	default:
+		return nil, fmt.Errorf("unknown exporter %q: use %s, %s, %s or %s", exporter, ExporterNone, ExporterOTLP, ExporterStdout, ExporterFile)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(spanExporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if file != nil {
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		return err
+	}, nil
+}
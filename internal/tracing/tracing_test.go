@@ -0,0 +1,72 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+)
+
+func TestInit(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	tests := []struct {
+		name     string
+		exporter string
+		path     string
+		err      bool
+	}{
+		{name: "disabled", exporter: ""},
+		{name: "none", exporter: "none"},
+		{name: "file", exporter: "FILE", path: "spans.json"},
+		{name: "file without a path", exporter: "file", err: true},
+		{name: "file in a missing directory", exporter: "file", path: "missing/spans.json", err: true},
+		{name: "unknown", exporter: "jaeger", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := tt.path
+			if path != "" {
+				path = filepath.Join(t.TempDir(), path)
+			}
+
+			stop, err := Init(tt.exporter, path, "mnee-test")
+			if tt.err {
+				if err == nil {
+					t.Fatal("Init succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, span := otel.GetTracerProvider().Tracer("test").Start(context.Background(), "test-span")
+			span.End()
+			if err := stop(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			if path == "" {
+				return
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range []string{`"Name":"test-span"`, `"Value":"mnee-test"`} {
+				if !strings.Contains(string(data), want) {
+					t.Fatalf("exported spans lack %s: %s", want, data)
+				}
+			}
+			if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+				t.Fatalf("span file mode = %v, %v; want 0600", info.Mode().Perm(), err)
+			}
+		})
+	}
+}
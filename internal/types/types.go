@@ -1,23 +1,27 @@
 package types
 
 import (
-	"errors"
 	"time"
+
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
 )
 
-var ErrForbidden = errors.New("forbidden access to cosigner")
+// The sentinel errors are the SDK's own values so errors.Is matches what the
+// SDK returns.
+
+var ErrForbidden = mnee.ErrForbidden
 
-var ErrInvalidConfig = errors.New("invalid config")
+var ErrInvalidConfig = mnee.ErrInvalidConfig
 
-var ErrInvalidEnvironment = errors.New("invalid environment")
+var ErrInvalidEnvironment = mnee.ErrInvalidEnvironment
 
-var ErrInsufficientMneeBalance = errors.New("insufficient mnee balance")
+var ErrInsufficientMneeBalance = mnee.ErrInsufficientMneeBalance
 
-var ErrTransferAmountGreaterThan0 = errors.New("transfer amount must be greater than 0")
+var ErrTransferAmountGreaterThan0 = mnee.ErrTransferAmountGreaterThan0
 
-var ErrInvalidPublicKeyHash = errors.New("invalid public key hash")
+var ErrInvalidPublicKeyHash = mnee.ErrInvalidPublicKeyHash
 
-var ErrReceivedEmptyTicketID = errors.New("received an empty ticket ID from server")
+var ErrReceivedEmptyTicketID = mnee.ErrReceivedEmptyTicketID
 
 type TokenOperation string
 
@@ -67,14 +71,28 @@ type Ticket struct {
 	TxID            *string      `json:"tx_id,omitempty"`
 	TxHex           *string      `json:"tx_hex,omitempty"`
 	ActionRequested *string      `json:"action_requested,omitempty"`
-	CallbackURL     *string      `json:"callback_url,omitempty"`
-	CallbackSecret  *string      `json:"callback_secret,omitempty"`
 	Status          TicketStatus `json:"status,omitempty,omitzero"`
 	CreatedAt       *time.Time   `json:"createdAt,omitempty"`
 	UpdatedAt       *time.Time   `json:"updatedAt,omitempty"`
 	Errors          []string     `json:"errors"`
 }
 
+// NewTicket copies ticket for a response. The callback URL and secret it was
+// submitted with are left out: with the webhook relay on they are the relay's
+// own, shared by every client.
+func NewTicket(ticket *mnee.Ticket) Ticket {
+	return Ticket{
+		ID:              ticket.ID,
+		TxID:            ticket.TxID,
+		TxHex:           ticket.TxHex,
+		ActionRequested: ticket.ActionRequested,
+		Status:          TicketStatus(ticket.Status),
+		CreatedAt:       ticket.CreatedAt,
+		UpdatedAt:       ticket.UpdatedAt,
+		Errors:          ticket.Errors,
+	}
+}
+
 type BsvData struct {
 	Decimals uint8   `json:"dec"`
 	Amt      uint64  `json:"amt"`
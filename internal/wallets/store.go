@@ -0,0 +1,306 @@
+package wallets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	primitives "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/config"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/jsonstore"
+)
+
+var (
+	ErrDisabled       = errors.New("wallets are disabled: WALLET_MASTER_KEY is not set")
+	ErrNotFound       = errors.New("wallet not found")
+	ErrInvalidKey     = errors.New("WALLET_MASTER_KEY must be 32 bytes, hex or base64 encoded")
+	ErrAlreadyExists  = errors.New("a wallet for this key already exists")
+	ErrNotEmpty       = errors.New("the wallet's address still holds MNEE; move it first or delete with force=true")
+	ErrNotExported    = errors.New("the wallet's generated key was never exported; export it first or delete with force=true")
+	ErrExportDisabled = errors.New("wallet export is disabled: WALLET_EXPORT_ENABLED is not set")
+)
+
+// Wallet is a managed private key. The key is only ever held encrypted with
+// the master key; ClientID is the API client that owns the wallet. A
+// Generated key exists nowhere else until it is exported.
+type Wallet struct {
+	ID           string     `json:"id"`
+	Label        string     `json:"label,omitempty"`
+	Address      string     `json:"address"`
+	ClientID     string     `json:"clientId,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	Generated    bool       `json:"generated,omitempty"`
+	ExportedAt   *time.Time `json:"exportedAt,omitempty"`
+	EncryptedWif string     `json:"encryptedWif"`
+}
+
+func (w Wallet) RecordID() string           { return w.ID }
+func (w Wallet) RecordOwner() string        { return w.ClientID }
+func (w Wallet) RecordKey() string          { return w.Address }
+func (w Wallet) RecordCreatedAt() time.Time { return w.CreatedAt }
+
+// Store keeps wallets with their keys encrypted by the master key. Keys are
+// only returned by Export once EnableExport was called.
+type Store struct {
+	aead    cipher.AEAD
+	wallets *jsonstore.Store[Wallet]
+	export  bool
+}
+
+// Instance is nil when no master key is configured.
+var Instance *Store
+
+func InitWallets(cfg *config.Config) {
+	if cfg.WalletMasterKey == "" {
+		return
+	}
+
+	store, err := NewStore(cfg.WalletMasterKey, cfg.WalletStorePath)
+	if err != nil {
+		log.Fatalf("Failed to load wallets: %v", err)
+	}
+	if cfg.WalletStorePath == "" {
+		log.Printf("WALLET_STORE_PATH is not set; managed wallets are lost on restart")
+	}
+	if cfg.WalletExportEnabled {
+		store.EnableExport()
+		log.Printf("WALLET_EXPORT_ENABLED is set; clients with the export:wallets scope can read managed keys")
+	}
+
+	Instance = store
+	log.Printf("Loaded %d managed wallets", store.wallets.Len())
+}
+
+func NewStore(masterKey string, path string) (*Store, error) {
+	key, err := decodeMasterKey(masterKey)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	wallets, err := jsonstore.Open[Wallet](path, ErrNotFound, ErrAlreadyExists)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Store{aead: aead, wallets: wallets}
+	for _, wallet := range wallets.All() {
+		// Decrypting every key up front fails startup on a wrong master key
+		// instead of on the first transfer.
+		if _, err := s.decrypt(wallet); err == nil {
+			continue
+		}
+		if err := s.reseal(wallet); err != nil {
+			return nil, fmt.Errorf("wallet %s: %w", wallet.ID, err)
+		}
+	}
+
+	return s, nil
+}
+
+// reseal binds a key sealed with its wallet ID alone, as stores written
+// before the address was bound hold, to the ID and address. The key must be
+// the wallet's, so an edited address is not sealed in.
+func (s *Store) reseal(wallet Wallet) error {
+	wif, err := s.Open(wallet.EncryptedWif, wallet.ID)
+	if err != nil {
+		return err
+	}
+
+	privateKey, err := primitives.PrivateKeyFromWif(wif)
+	if err != nil {
+		return err
+	}
+	address, err := script.NewAddressFromPublicKey(privateKey.PubKey(), true)
+	if err != nil {
+		return err
+	}
+	if address.AddressString != wallet.Address {
+		return fmt.Errorf("key is for %s, not %s", address.AddressString, wallet.Address)
+	}
+
+	if wallet.EncryptedWif, err = s.Seal(wif, walletContext(wallet)); err != nil {
+		return err
+	}
+	return s.wallets.Update(wallet)
+}
+
+// EnableExport lets Export return keys.
+func (s *Store) EnableExport() {
+	s.export = true
+}
+
+// Import stores wif as a new wallet owned by clientID.
+func (s *Store) Import(wif string, label string, clientID string) (*Wallet, error) {
+	privateKey, err := primitives.PrivateKeyFromWif(wif)
+	if err != nil {
+		return nil, err
+	}
+	return s.add(privateKey, label, clientID, false)
+}
+
+// Generate creates a wallet with a new random key owned by clientID.
+func (s *Store) Generate(label string, clientID string) (*Wallet, error) {
+	privateKey, err := primitives.NewPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	return s.add(privateKey, label, clientID, true)
+}
+
+func (s *Store) add(privateKey *primitives.PrivateKey, label string, clientID string, generated bool) (*Wallet, error) {
+	address, err := script.NewAddressFromPublicKey(privateKey.PubKey(), true)
+	if err != nil {
+		return nil, err
+	}
+
+	wallet := Wallet{
+		ID:        randomID(),
+		Label:     label,
+		Address:   address.AddressString,
+		ClientID:  clientID,
+		CreatedAt: time.Now().UTC(),
+		Generated: generated,
+	}
+
+	wallet.EncryptedWif, err = s.Seal(privateKey.Wif(), walletContext(wallet))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.wallets.Add(wallet); err != nil {
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+// List returns the wallets owned by clientID, oldest first.
+func (s *Store) List(clientID string) []Wallet {
+	return s.wallets.List(clientID)
+}
+
+// Get returns wallet id if clientID owns it.
+func (s *Store) Get(id string, clientID string) (*Wallet, error) {
+	wallet, err := s.wallets.Get(id, clientID)
+	if err != nil {
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+func (s *Store) Delete(id string, clientID string) error {
+	return s.wallets.Delete(id, clientID)
+}
+
+// Export decrypts the key of wallet id, which clientID must own, and records
+// when it was exported. It fails with ErrExportDisabled unless EnableExport
+// was called.
+func (s *Store) Export(id string, clientID string) (*Wallet, string, error) {
+	if !s.export {
+		return nil, "", ErrExportDisabled
+	}
+
+	wallet, err := s.wallets.Get(id, clientID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	wif, err := s.decrypt(wallet)
+	if err != nil {
+		return nil, "", err
+	}
+
+	exportedAt := time.Now().UTC()
+	wallet.ExportedAt = &exportedAt
+	if err := s.wallets.Update(wallet); err != nil {
+		return nil, "", err
+	}
+	return &wallet, wif, nil
+}
+
+// Wifs decrypts the keys of the wallets ids, all of which clientID must own.
+func (s *Store) Wifs(ids []string, clientID string) ([]string, error) {
+	wifs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		wallet, err := s.wallets.Get(id, clientID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, id)
+		}
+
+		wif, err := s.decrypt(wallet)
+		if err != nil {
+			return nil, err
+		}
+		wifs = append(wifs, wif)
+	}
+	return wifs, nil
+}
+
+func (s *Store) decrypt(wallet Wallet) (string, error) {
+	return s.Open(wallet.EncryptedWif, walletContext(wallet))
+}
+
+// walletContext binds a wallet's key to its ID and address, so it cannot be
+// moved to another wallet or shown under another address.
+func walletContext(wallet Wallet) string {
+	return "wallets.wif|" + wallet.ID + "|" + wallet.Address
+}
+
+// Seal encrypts plaintext with the master key, for the keys here and other
+// secrets the service keeps at rest. The result only opens with the same
+// context, which names what the secret belongs to, so it cannot be moved to
+// another record.
+func (s *Store) Seal(plaintext string, context string) (string, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := s.aead.Seal(nonce, nonce, []byte(plaintext), []byte(context))
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts what Seal returned for context.
+func (s *Store) Open(sealed string, context string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil || len(data) < s.aead.NonceSize() {
+		return "", errors.New("malformed encrypted key")
+	}
+
+	nonce, ciphertext := data[:s.aead.NonceSize()], data[s.aead.NonceSize():]
+	plaintext, err := s.aead.Open(nil, nonce, ciphertext, []byte(context))
+	if err != nil {
+		return "", errors.New("cannot decrypt key; is WALLET_MASTER_KEY correct?")
+	}
+	return string(plaintext), nil
+}
+
+func decodeMasterKey(value string) ([]byte, error) {
+	if key, err := hex.DecodeString(value); err == nil && len(key) == 32 {
+		return key, nil
+	}
+	if key, err := base64.StdEncoding.DecodeString(value); err == nil && len(key) == 32 {
+		return key, nil
+	}
+	return nil, ErrInvalidKey
+}
+
+func randomID() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return "w_" + hex.EncodeToString(b)
+}
@@ -0,0 +1,331 @@
+package wallets
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	primitives "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/bsv-blockchain/go-sdk/script"
+)
+
+var testMasterKey = strings.Repeat("ab", 32)
+
+func TestDecodeMasterKey(t *testing.T) {
+	raw := make([]byte, 32)
+	for i := range raw {
+		raw[i] = byte(i)
+	}
+
+	tests := []struct {
+		name  string
+		value string
+		err   error
+	}{
+		{name: "hex", value: hex.EncodeToString(raw)},
+		{name: "base64", value: base64.StdEncoding.EncodeToString(raw)},
+		{name: "short hex", value: hex.EncodeToString(raw[:16]), err: ErrInvalidKey},
+		{name: "short base64", value: base64.StdEncoding.EncodeToString(raw[:16]), err: ErrInvalidKey},
+		{name: "garbage", value: "not a key", err: ErrInvalidKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := decodeMasterKey(tt.value)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if err == nil && string(key) != string(raw) {
+				t.Fatalf("got key %x, want %x", key, raw)
+			}
+		})
+	}
+}
+
+func TestStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wallets.json")
+	privateKey, err := primitives.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wif := privateKey.Wif()
+
+	s, err := NewStore(testMasterKey, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported, err := s.Import(wif, "imported", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated, err := s.Generate("generated", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.Generated || !generated.Generated {
+		t.Fatalf("got generated %v and %v, want false and true", imported.Generated, generated.Generated)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), wif) {
+		t.Fatal("the store file holds the plain WIF")
+	}
+
+	tests := []struct {
+		name      string
+		masterKey string
+		err       bool
+	}{
+		{name: "same process"},
+		{name: "reloaded", masterKey: testMasterKey},
+		{name: "wrong master key", masterKey: strings.Repeat("cd", 32), err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := s
+			if tt.masterKey != "" {
+				var err error
+				store, err = NewStore(tt.masterKey, path)
+				if (err != nil) != tt.err {
+					t.Fatalf("got error %v, want error %v", err, tt.err)
+				}
+				if err != nil {
+					return
+				}
+			}
+
+			wifs, err := store.Wifs([]string{imported.ID}, "alice")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if wifs[0] != wif {
+				t.Fatalf("got WIF %q, want %q", wifs[0], wif)
+			}
+
+			if _, err := store.Wifs([]string{imported.ID}, "bob"); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("another client's wallet: got %v, want %v", err, ErrNotFound)
+			}
+		})
+	}
+}
+
+func TestStoreExport(t *testing.T) {
+	s, err := NewStore(testMasterKey, filepath.Join(t.TempDir(), "wallets.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallet, err := s.Generate("", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := s.Export(wallet.ID, "alice"); !errors.Is(err, ErrExportDisabled) {
+		t.Fatalf("export before EnableExport: got %v, want %v", err, ErrExportDisabled)
+	}
+	if stored, err := s.Get(wallet.ID, "alice"); err != nil || stored.ExportedAt != nil {
+		t.Fatalf("a refused export was recorded: %+v, %v", stored, err)
+	}
+
+	s.EnableExport()
+	if _, _, err := s.Export(wallet.ID, "bob"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("another client's wallet: got %v, want %v", err, ErrNotFound)
+	}
+
+	exported, wif, err := s.Export(wallet.ID, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exported.ExportedAt == nil {
+		t.Fatal("ExportedAt is not set")
+	}
+	privateKey, err := primitives.PrivateKeyFromWif(wif)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if address := mustAddress(t, privateKey); address != wallet.Address {
+		t.Fatalf("exported key is for %s, want %s", address, wallet.Address)
+	}
+
+	stored, err := s.Get(wallet.ID, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.ExportedAt == nil {
+		t.Fatal("the export was not recorded")
+	}
+}
+
+func TestStoreRejects(t *testing.T) {
+	privateKey, err := primitives.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewStore(testMasterKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := s.Import(privateKey.Wif(), "", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := s.Generate("", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Import(privateKey.Wif(), "", "alice"); !errors.Is(err, ErrAlreadyExists) {
+		t.Fatalf("importing twice: got %v, want %v", err, ErrAlreadyExists)
+	}
+	if _, err := s.Import(privateKey.Wif(), "", "bob"); err != nil {
+		t.Fatalf("another client importing the same key: %v", err)
+	}
+	if _, err := s.Import("not-a-wif", "", "alice"); err == nil {
+		t.Fatal("imported an invalid WIF")
+	}
+
+	// The wallet ID and address are authenticated, so a key copied to
+	// another wallet, or shown under another address, does not decrypt.
+	swapped := *second
+	swapped.EncryptedWif = first.EncryptedWif
+	if _, err := s.decrypt(swapped); err == nil {
+		t.Fatal("decrypted a key sealed for another wallet")
+	}
+	moved := *first
+	moved.Address = second.Address
+	if _, err := s.decrypt(moved); err == nil {
+		t.Fatal("decrypted a key under another address")
+	}
+}
+
+func TestStoreReseal(t *testing.T) {
+	_, other := testWallet(t)
+
+	tests := []struct {
+		name    string
+		address string
+		err     bool
+	}{
+		{name: "sealed with the ID alone"},
+		{name: "address edited", address: other, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "wallets.json")
+			s, err := NewStore(testMasterKey, path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wif, _ := testWallet(t)
+			wallet, err := s.Import(wif, "", "alice")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Stores written before the address was bound seal with the ID.
+			legacy := *wallet
+			if legacy.EncryptedWif, err = s.Seal(wif, wallet.ID); err != nil {
+				t.Fatal(err)
+			}
+			if tt.address != "" {
+				legacy.Address = tt.address
+			}
+			if err := s.wallets.Update(legacy); err != nil {
+				t.Fatal(err)
+			}
+
+			reloaded, err := NewStore(testMasterKey, path)
+			if (err != nil) != tt.err {
+				t.Fatalf("got error %v, want error %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+
+			wifs, err := reloaded.Wifs([]string{wallet.ID}, "alice")
+			if err != nil || wifs[0] != wif {
+				t.Fatalf("got %v, %v; want the imported WIF", wifs, err)
+			}
+
+			// The key was sealed again, bound to the address, and saved.
+			again, err := NewStore(testMasterKey, path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			stored, err := again.Get(wallet.ID, "alice")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := again.Open(stored.EncryptedWif, wallet.ID); err == nil {
+				t.Fatal("the key still opens with the wallet ID alone")
+			}
+		})
+	}
+}
+
+func testWallet(t *testing.T) (string, string) {
+	t.Helper()
+
+	privateKey, err := primitives.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return privateKey.Wif(), mustAddress(t, privateKey)
+}
+
+func TestSeal(t *testing.T) {
+	s, err := NewStore(testMasterKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := NewStore(strings.Repeat("cd", 32), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sealed, err := s.Seal("secret", "ticket-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(sealed, "secret") {
+		t.Fatal("the sealed value holds the plaintext")
+	}
+
+	tests := []struct {
+		name    string
+		store   *Store
+		sealed  string
+		context string
+		err     bool
+	}{
+		{name: "same context", store: s, sealed: sealed, context: "ticket-1"},
+		{name: "other context", store: s, sealed: sealed, context: "ticket-2", err: true},
+		{name: "other master key", store: other, sealed: sealed, context: "ticket-1", err: true},
+		{name: "malformed", store: s, sealed: "not base64!", context: "ticket-1", err: true},
+	}
+	for _, tt := range tests {
+		plaintext, err := tt.store.Open(tt.sealed, tt.context)
+		if (err != nil) != tt.err || err == nil && plaintext != "secret" {
+			t.Errorf("%s: got %q, %v; want error %v", tt.name, plaintext, err, tt.err)
+		}
+	}
+}
+
+func mustAddress(t *testing.T, privateKey *primitives.PrivateKey) string {
+	t.Helper()
+
+	address, err := script.NewAddressFromPublicKey(privateKey.PubKey(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return address.AddressString
+}
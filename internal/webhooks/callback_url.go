@@ -0,0 +1,94 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrUnsafeCallbackURL is returned for a request's callbackUrl that the relay
+// refuses to call: callers must not be able to make it reach hosts inside
+// the deployment, such as cloud metadata endpoints.
+var ErrUnsafeCallbackURL = errors.New("unsafe callbackUrl")
+
+// CheckCallbackURL reports whether the relay may deliver to rawURL. It must
+// be https, and its host must be in the configured CallbackHosts or, without
+// them, resolve only to public addresses. Without the relay the cosigner calls
+// the URL itself, so anything goes.
+func (r *Relay) CheckCallbackURL(ctx context.Context, rawURL string) error {
+	if !r.Enabled() {
+		return nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+		return fmt.Errorf("%w: it must be an https URL", ErrUnsafeCallbackURL)
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if len(r.config.CallbackHosts) > 0 {
+		if !slices.Contains(r.config.CallbackHosts, host) {
+			return fmt.Errorf("%w: %s is not an allowed callback host", ErrUnsafeCallbackURL, host)
+		}
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("%w: cannot resolve %s", ErrUnsafeCallbackURL, host)
+	}
+	for _, addr := range addrs {
+		if !publicIP(addr.IP) {
+			return fmt.Errorf("%w: %s resolves to a non-public address", ErrUnsafeCallbackURL, host)
+		}
+	}
+	return nil
+}
+
+// publicIP reports whether ip is a globally routable unicast address.
+func publicIP(ip net.IP) bool {
+	return ip.IsGlobalUnicast() && !ip.IsPrivate() && !sharedAddressSpace.Contains(ip)
+}
+
+// sharedAddressSpace is carrier-grade NAT space (RFC 6598), which cloud
+// providers also use internally.
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// newCallbackClient returns the client requests' callbacks are delivered
+// with. Unless an allowlist vouches for the hosts, it refuses to connect to
+// non-public addresses, so a host that resolves differently at delivery than
+// when it was checked is still caught. Redirects are not followed.
+func newCallbackClient(timeout time.Duration, allowlisted bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowlisted {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+				return fmt.Errorf("%w: %s is not a public address", ErrUnsafeCallbackURL, host)
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,96 @@
+package webhooks
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCheckCallbackURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		hosts  []string
+		url    string
+		public string
+		unsafe bool
+	}{
+		{name: "public address", url: "https://93.184.216.34/hook"},
+		{name: "public IPv6 address", url: "https://[2606:2800:220:1:248:1893:25c8:1946]/hook"},
+		{name: "http", url: "http://93.184.216.34/hook", unsafe: true},
+		{name: "no host", url: "https:///hook", unsafe: true},
+		{name: "not a URL", url: "://", unsafe: true},
+		{name: "loopback", url: "https://127.0.0.1/hook", unsafe: true},
+		{name: "loopback IPv6", url: "https://[::1]/hook", unsafe: true},
+		{name: "localhost", url: "https://localhost/hook", unsafe: true},
+		{name: "private", url: "https://10.1.2.3/hook", unsafe: true},
+		{name: "private IPv6", url: "https://[fd00::1]/hook", unsafe: true},
+		{name: "link-local metadata", url: "https://169.254.169.254/latest/meta-data", unsafe: true},
+		{name: "link-local IPv6", url: "https://[fe80::1]/hook", unsafe: true},
+		{name: "shared address space", url: "https://100.64.0.1/hook", unsafe: true},
+		{name: "unspecified", url: "https://0.0.0.0/hook", unsafe: true},
+		{name: "unresolvable", url: "https://does-not-exist.invalid/hook", unsafe: true},
+		{name: "allowlisted host", hosts: []string{"hooks.example.com"}, url: "https://Hooks.Example.com/hook"},
+		{name: "allowlisted host over http", hosts: []string{"hooks.example.com"}, url: "http://hooks.example.com/hook", unsafe: true},
+		{name: "host not in allowlist", hosts: []string{"hooks.example.com"}, url: "https://93.184.216.34/hook", unsafe: true},
+		{name: "relay disabled", public: "-", url: "http://127.0.0.1/hook"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Config{PublicURL: "https://relay.example.com", CallbackHosts: tt.hosts}
+			if tt.public == "-" {
+				config.PublicURL = ""
+			}
+
+			err := NewRelay(config).CheckCallbackURL(t.Context(), tt.url)
+			if errors.Is(err, ErrUnsafeCallbackURL) != tt.unsafe || err != nil && !tt.unsafe {
+				t.Fatalf("CheckCallbackURL(%q) = %v, want unsafe %v", tt.url, err, tt.unsafe)
+			}
+		})
+	}
+}
+
+func TestCallbackClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/hook", http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		allowlisted bool
+		path        string
+		status      int
+		unsafe      bool
+	}{
+		// The test server listens on loopback, standing in for a host that
+		// resolved to a public address when it was checked but not when
+		// the callback is delivered.
+		{name: "refused at dial time", path: "/hook", unsafe: true},
+		{name: "allowlisted", allowlisted: true, path: "/hook", status: http.StatusNoContent},
+		{name: "redirect not followed", allowlisted: true, path: "/redirect", status: http.StatusFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newCallbackClient(time.Second, tt.allowlisted)
+			response, err := client.Post(server.URL+tt.path, "application/json", nil)
+			if errors.Is(err, ErrUnsafeCallbackURL) != tt.unsafe {
+				t.Fatalf("got error %v, want unsafe %v", err, tt.unsafe)
+			}
+			if err != nil {
+				return
+			}
+			defer response.Body.Close()
+			if response.StatusCode != tt.status {
+				t.Fatalf("got status %d, want %d", response.StatusCode, tt.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,588 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	mnee "github.com/mnee-xyz/go-mnee-1sat-sdk"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/ledger"
+)
+
+const (
+	EventTicketUpdated = "ticket.updated"
+	EventTicketStuck   = "ticket.stuck"
+
+	// CallbackPath is where the relay receives cosigner callbacks, below /api.
+	CallbackPath = "/webhooks/mnee"
+
+	IDHeader        = "X-Webhook-Id"
+	TimestampHeader = "X-Webhook-Timestamp"
+	SignatureHeader = "X-Webhook-Signature"
+
+	// CallbackSignatureHeader carries the cosigner's signature of a callback.
+	CallbackSignatureHeader = "X-Signature"
+)
+
+// Config configures a Relay. Without PublicURL the relay is disabled and
+// callbacks given on requests go straight to the cosigner. CallbackHosts, when
+// set, are the only hosts requests' callbacks may point at. Tickets, when set,
+// keeps tracked tickets across restarts. Callback secrets are only stored
+// sealed by Secrets; without it, a callback with a secret is not stored and
+// lasts until the next restart.
+type Config struct {
+	PublicURL      string
+	CallbackSecret string
+	Subscribers    []string
+	SigningSecret  string
+	CallbackHosts  []string
+	MaxAttempts    int
+	Timeout        time.Duration
+	Tickets        TicketStore
+	Secrets        Sealer
+}
+
+// TicketStore keeps the tickets the relay tracks; ledger.Store is one.
+type TicketStore interface {
+	Track(ctx context.Context, ticket ledger.TrackedTicket) error
+	Tracked(ctx context.Context, ticketID string) (*ledger.TrackedTicket, error)
+	ForgetTracked(ctx context.Context, before time.Time) error
+}
+
+// Sealer encrypts the callback secrets stored in a TicketStore;
+// wallets.Store is one. context names what a secret belongs to, and a sealed
+// secret only opens with the same one.
+type Sealer interface {
+	Seal(plaintext string, context string) (string, error)
+	Open(sealed string, context string) (string, error)
+}
+
+// Event is the payload delivered to subscribers.
+type Event struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"createdAt"`
+	Data      TicketEvent `json:"data"`
+}
+
+type TicketEvent struct {
+	TicketID        string            `json:"ticketId"`
+	Status          mnee.TicketStatus `json:"status"`
+	TxID            *string           `json:"txid,omitempty"`
+	ActionRequested *string           `json:"actionRequested,omitempty"`
+	Errors          []string          `json:"errors"`
+	UpdatedAt       *time.Time        `json:"updatedAt,omitempty"`
+}
+
+// subscriber is a delivery target. Callbacks come from requests rather than
+// the operator, so they are delivered with the guarded callback client.
+type subscriber struct {
+	url      string
+	secret   string
+	callback bool
+}
+
+type trackedTicket struct {
+	clientID    string
+	callback    *subscriber
+	lastStatus  mnee.TicketStatus
+	lastEventID string
+	lastEvent   []byte
+	trackedAt   time.Time
+	finishedAt  time.Time
+}
+
+const (
+	// finishedRetention is how long a ticket in a final status stays
+	// tracked, so a request registering its callback after the cosigner
+	// already called back still gets the update.
+	finishedRetention = 10 * time.Minute
+
+	// unfinishedRetention is how long a ticket that never reaches a final
+	// status stays tracked.
+	unfinishedRetention = 24 * time.Hour
+
+	// maxTrackedTickets caps the tickets tracked in memory at once; the
+	// oldest is forgotten to make room. With a TicketStore it is loaded again
+	// when needed.
+	maxTrackedTickets = 10000
+
+	storeTimeout = 5 * time.Second
+)
+
+// Relay registers itself as the cosigner callback for asynchronous transfers,
+// and relays each ticket update it receives to the configured subscribers and
+// to the callback given on the original request. Deliveries are signed with
+// HMAC-SHA256 and retried with exponential backoff.
+type Relay struct {
+	config         Config
+	httpClient     *http.Client
+	callbackClient *http.Client
+	backoff        time.Duration
+
+	mutex   sync.Mutex
+	tickets map[string]*trackedTicket
+	wg      sync.WaitGroup
+	pending atomic.Int64
+}
+
+var Instance = NewRelay(Config{})
+
+func NewRelay(config Config) *Relay {
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 5
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	config.PublicURL = strings.TrimRight(config.PublicURL, "/")
+	for i, host := range config.CallbackHosts {
+		config.CallbackHosts[i] = strings.ToLower(host)
+	}
+
+	return &Relay{
+		config:         config,
+		httpClient:     &http.Client{Timeout: config.Timeout},
+		callbackClient: newCallbackClient(config.Timeout, len(config.CallbackHosts) > 0),
+		backoff:        time.Second,
+		tickets:        make(map[string]*trackedTicket),
+	}
+}
+
+// InitRelay replaces Instance with a relay built from config. A relay that
+// receives callbacks needs a secret to verify them; one is generated when
+// none is configured, which only holds until the next restart.
+func InitRelay(config Config) {
+	if config.PublicURL != "" && config.CallbackSecret == "" {
+		config.CallbackSecret = randomHex(32)
+		log.Printf("WEBHOOK_CALLBACK_SECRET is not set; callbacks for tickets created before a restart will be rejected")
+	}
+	if len(config.Subscribers) > 0 && config.SigningSecret == "" {
+		log.Fatal("WEBHOOK_SIGNING_SECRET is required when WEBHOOK_SUBSCRIBERS is set")
+	}
+
+	Instance = NewRelay(config)
+	if Instance.Enabled() {
+		log.Printf("Webhook relay receiving callbacks at %s", Instance.callbackURL())
+	}
+}
+
+func (r *Relay) Enabled() bool {
+	return r.config.PublicURL != ""
+}
+
+func (r *Relay) callbackURL() string {
+	return r.config.PublicURL + "/api" + CallbackPath
+}
+
+// Callback returns the callback URL and secret to hand to the cosigner for a
+// request that asked for callbackURL. When the relay is enabled it always
+// registers itself; otherwise the request's own callback passes through.
+func (r *Relay) Callback(callbackURL, callbackSecret *string) (*string, *string) {
+	if !r.Enabled() {
+		return callbackURL, callbackSecret
+	}
+
+	url := r.callbackURL()
+	secret := r.config.CallbackSecret
+	return &url, &secret
+}
+
+// Track starts relaying updates for ticketID, which clientID created.
+// callbackURL, when given, gets every update in addition to the configured
+// subscribers, signed with callbackSecret; the request must have passed it
+// through CheckCallbackURL. An update that arrived before Track is replayed
+// to it.
+func (r *Relay) Track(ticketID string, clientID string, callbackURL, callbackSecret *string) {
+	if !r.Enabled() {
+		return
+	}
+
+	stored := ledger.TrackedTicket{TicketID: ticketID, ClientID: clientID}
+	if callbackURL != nil {
+		stored.CallbackURL = *callbackURL
+	}
+	var secret string
+	if callbackSecret != nil {
+		secret = *callbackSecret
+	}
+
+	r.mutex.Lock()
+	tracked := r.ticket(ticketID)
+	tracked.setOwner(clientID, stored.CallbackURL, secret)
+	stored.TrackedAt = tracked.trackedAt
+	callback := tracked.callback
+	eventID, event := tracked.lastEventID, tracked.lastEvent
+	r.mutex.Unlock()
+
+	if callback != nil && event != nil {
+		r.dispatch([]subscriber{*callback}, eventID, event)
+	}
+
+	if r.config.Tickets == nil {
+		return
+	}
+	if secret != "" {
+		sealed, err := r.sealSecret(stored, secret)
+		if err != nil {
+			// The callback cannot be signed after a restart, so it is not
+			// kept past one.
+			log.Printf("webhooks: not storing the callback of ticket %s: %v", ticketID, err)
+			stored.CallbackURL = ""
+		}
+		stored.SealedCallbackSecret = sealed
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+	if err := r.config.Tickets.Track(ctx, stored); err != nil {
+		log.Printf("webhooks: storing ticket %s: %v", ticketID, err)
+		return
+	}
+	if err := r.config.Tickets.ForgetTracked(ctx, time.Now().Add(-unfinishedRetention)); err != nil {
+		log.Printf("webhooks: forgetting old tickets: %v", err)
+	}
+}
+
+func (t *trackedTicket) setOwner(clientID, callbackURL, callbackSecret string) {
+	t.clientID = clientID
+	t.callback = nil
+	if callbackURL != "" {
+		t.callback = &subscriber{url: callbackURL, secret: callbackSecret, callback: true}
+	}
+}
+
+var errNoSealer = errors.New("no key to seal callback secrets with; set WALLET_MASTER_KEY")
+
+// sealSecret seals the callback secret of ticket, bound to its ticket and
+// callback URL.
+func (r *Relay) sealSecret(ticket ledger.TrackedTicket, secret string) (string, error) {
+	if r.config.Secrets == nil {
+		return "", errNoSealer
+	}
+	return r.config.Secrets.Seal(secret, callbackSecretContext(ticket))
+}
+
+// openSecret opens the callback secret sealSecret sealed for ticket.
+func (r *Relay) openSecret(ticket ledger.TrackedTicket) (string, error) {
+	if r.config.Secrets == nil {
+		return "", errNoSealer
+	}
+	return r.config.Secrets.Open(ticket.SealedCallbackSecret, callbackSecretContext(ticket))
+}
+
+func callbackSecretContext(ticket ledger.TrackedTicket) string {
+	return "webhooks.callback|" + ticket.TicketID + "|" + ticket.CallbackURL
+}
+
+// restore loads ticketID from the TicketStore when it is not tracked in
+// memory, as after a restart.
+func (r *Relay) restore(ticketID string) {
+	if r.config.Tickets == nil {
+		return
+	}
+
+	r.mutex.Lock()
+	_, ok := r.tickets[ticketID]
+	r.mutex.Unlock()
+	if ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+	stored, err := r.config.Tickets.Tracked(ctx, ticketID)
+	if err != nil {
+		log.Printf("webhooks: loading ticket %s: %v", ticketID, err)
+		return
+	}
+	if stored == nil || time.Since(stored.TrackedAt) > unfinishedRetention {
+		return
+	}
+
+	var secret string
+	if stored.SealedCallbackSecret != "" {
+		if secret, err = r.openSecret(*stored); err != nil {
+			log.Printf("webhooks: dropping the callback of ticket %s: %v", ticketID, err)
+			stored.CallbackURL = ""
+		}
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, ok := r.tickets[ticketID]; ok {
+		return
+	}
+	tracked := r.ticket(ticketID)
+	tracked.setOwner(stored.ClientID, stored.CallbackURL, secret)
+	tracked.trackedAt = stored.TrackedAt
+}
+
+// Owner returns the client that created ticketID, or empty when the ticket
+// is not tracked.
+func (r *Relay) Owner(ticketID string) string {
+	r.restore(ticketID)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if tracked, ok := r.tickets[ticketID]; ok {
+		return tracked.clientID
+	}
+	return ""
+}
+
+// ticket returns the tracked ticket for ticketID, creating it if needed, and
+// forgets tickets that finished a while ago or never finished at all. The
+// caller holds the mutex.
+func (r *Relay) ticket(ticketID string) *trackedTicket {
+	now := time.Now()
+	for id, tracked := range r.tickets {
+		if !tracked.finishedAt.IsZero() && now.Sub(tracked.finishedAt) > finishedRetention ||
+			now.Sub(tracked.trackedAt) > unfinishedRetention {
+			delete(r.tickets, id)
+		}
+	}
+
+	tracked, ok := r.tickets[ticketID]
+	if !ok {
+		if len(r.tickets) >= maxTrackedTickets {
+			r.forgetOldest()
+		}
+		tracked = &trackedTicket{trackedAt: now}
+		r.tickets[ticketID] = tracked
+	}
+	return tracked
+}
+
+// forgetOldest stops tracking the ticket tracked the longest. The caller
+// holds the mutex.
+func (r *Relay) forgetOldest() {
+	var oldestID string
+	var oldest time.Time
+	for id, tracked := range r.tickets {
+		if oldestID == "" || tracked.trackedAt.Before(oldest) {
+			oldestID, oldest = id, tracked.trackedAt
+		}
+	}
+	if oldestID != "" {
+		log.Printf("webhooks: tracking %d tickets; forgetting ticket %s", len(r.tickets), oldestID)
+		delete(r.tickets, oldestID)
+	}
+}
+
+// Verify checks that a callback was sent by the cosigner: X-Signature must
+// be the hex HMAC-SHA256 of the body keyed with the relay's callback secret.
+// Nothing else is accepted. The MNEE SDK only forwards callback_secret to the
+// cosigner and does not say how callbacks are signed, so this scheme is an
+// assumption, pinned by TestVerify; confirm it against the cosigner before
+// relying on the relay.
+func (r *Relay) Verify(header http.Header, body []byte) bool {
+	if r.config.CallbackSecret == "" {
+		return false
+	}
+
+	signature, err := hex.DecodeString(header.Get(CallbackSignatureHeader))
+	if err != nil || len(signature) != sha256.Size {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(r.config.CallbackSecret))
+	mac.Write(body)
+	return hmac.Equal(signature, mac.Sum(nil))
+}
+
+// Publish relays ticket to the subscribers and the ticket's callback when its
+// status changed since the last update. Delivery happens in the background.
+func (r *Relay) Publish(ticket *mnee.Ticket) {
+	event, body, ok := newEvent(EventTicketUpdated, ticket)
+	if !ok {
+		return
+	}
+	targets := r.subscribers()
+	r.restore(*ticket.ID)
+
+	r.mutex.Lock()
+	tracked := r.ticket(*ticket.ID)
+	if tracked.lastStatus == ticket.Status {
+		r.mutex.Unlock()
+		return
+	}
+	tracked.lastStatus = ticket.Status
+	tracked.lastEventID = event.ID
+	tracked.lastEvent = body
+	if ticket.Status != "" && ticket.Status != mnee.BROADCASTING {
+		tracked.finishedAt = time.Now()
+	}
+	if tracked.callback != nil {
+		targets = append(targets, *tracked.callback)
+	}
+	r.mutex.Unlock()
+
+	r.dispatch(targets, event.ID, body)
+}
+
+// PublishStuck tells the subscribers, and the ticket's callback while it is
+// tracked, that ticket has not finished long after it was submitted.
+func (r *Relay) PublishStuck(ticket *mnee.Ticket) {
+	event, body, ok := newEvent(EventTicketStuck, ticket)
+	if !ok {
+		return
+	}
+	targets := r.subscribers()
+	r.restore(*ticket.ID)
+
+	r.mutex.Lock()
+	if tracked, ok := r.tickets[*ticket.ID]; ok && tracked.callback != nil {
+		targets = append(targets, *tracked.callback)
+	}
+	r.mutex.Unlock()
+
+	r.dispatch(targets, event.ID, body)
+}
+
+func newEvent(eventType string, ticket *mnee.Ticket) (Event, []byte, bool) {
+	if ticket == nil || ticket.ID == nil {
+		return Event{}, nil, false
+	}
+
+	event := Event{
+		ID:        randomHex(16),
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		Data: TicketEvent{
+			TicketID:        *ticket.ID,
+			Status:          ticket.Status,
+			TxID:            ticket.TxID,
+			ActionRequested: ticket.ActionRequested,
+			Errors:          ticket.Errors,
+			UpdatedAt:       ticket.UpdatedAt,
+		},
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("webhooks: encoding event for ticket %s: %v", *ticket.ID, err)
+		return Event{}, nil, false
+	}
+	return event, body, true
+}
+
+func (r *Relay) subscribers() []subscriber {
+	targets := make([]subscriber, 0, len(r.config.Subscribers)+1)
+	for _, url := range r.config.Subscribers {
+		targets = append(targets, subscriber{url: url, secret: r.config.SigningSecret})
+	}
+	return targets
+}
+
+func (r *Relay) dispatch(targets []subscriber, eventID string, body []byte) {
+	for _, target := range targets {
+		r.wg.Add(1)
+		r.pending.Add(1)
+		go func(target subscriber) {
+			defer r.wg.Done()
+			defer r.pending.Add(-1)
+			r.deliver(target, eventID, body)
+		}(target)
+	}
+}
+
+// Close waits for pending deliveries until ctx is done.
+func (r *Relay) Close(ctx context.Context) error {
+	if r.pending.Load() == 0 {
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *Relay) deliver(target subscriber, eventID string, body []byte) {
+	backoff := r.backoff
+	for attempt := 1; attempt <= r.config.MaxAttempts; attempt++ {
+		err := r.send(target, eventID, body)
+		if err == nil {
+			return
+		}
+
+		if attempt == r.config.MaxAttempts {
+			log.Printf("webhooks: giving up on event %s to %s after %d attempts: %v", eventID, target.url, attempt, err)
+			return
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (r *Relay) send(target subscriber, eventID string, body []byte) error {
+	request, err := http.NewRequest(http.MethodPost, target.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(IDHeader, eventID)
+	request.Header.Set(TimestampHeader, timestamp)
+	if target.secret != "" {
+		request.Header.Set(SignatureHeader, "sha256="+Sign(target.secret, timestamp, body))
+	}
+
+	client := r.httpClient
+	if target.callback {
+		client = r.callbackClient
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("subscriber answered %d", response.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the hex HMAC-SHA256 subscribers check X-Webhook-Signature
+// against: the signature of "<timestamp>.<body>" with their secret.
+func Sign(secret, timestamp string, body []byte) string {
+	return hmacHex([]byte(secret), append([]byte(timestamp+"."), body...))
+}
+
+func hmacHex(secret, data []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
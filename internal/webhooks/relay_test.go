@@ -0,0 +1,123 @@
+package webhooks
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/ledger"
+	"github.com/mnee-xyz/go-mnee-1sat-sdk-docker/internal/wallets"
+)
+
+func TestRelayRestoresTrackedTickets(t *testing.T) {
+	store, err := ledger.OpenSQLite(filepath.Join(t.TempDir(), "ledger.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	secrets, err := wallets.NewStore(strings.Repeat("ab", 32), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callbackURL, callbackSecret := "https://example.com/callback", "s3cr3t"
+	config := Config{PublicURL: "https://relay.example.com", CallbackSecret: "secret", Tickets: store, Secrets: secrets}
+	NewRelay(config).Track("t1", "alice", &callbackURL, &callbackSecret)
+	NewRelay(config).Track("t2", "bob", nil, nil)
+	unsealed := config
+	unsealed.Secrets = nil
+	NewRelay(unsealed).Track("t3", "carol", &callbackURL, &callbackSecret)
+	if err := store.Track(t.Context(), ledger.TrackedTicket{TicketID: "expired", ClientID: "carol", TrackedAt: time.Now().Add(-2 * unfinishedRetention)}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The secret is stored sealed, bound to its ticket and URL.
+	stored, err := store.Tracked(t.Context(), "t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.SealedCallbackSecret == "" || strings.Contains(stored.SealedCallbackSecret, callbackSecret) {
+		t.Fatalf("got sealed secret %q", stored.SealedCallbackSecret)
+	}
+	moved := *stored
+	moved.TicketID, moved.CallbackURL = "t4", "https://attacker.example/callback"
+	if err := store.Track(t.Context(), moved); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		tickets  TicketStore
+		secrets  Sealer
+		ticketID string
+		owner    string
+		callback *subscriber
+	}{
+		{name: "with callback", tickets: store, secrets: secrets, ticketID: "t1", owner: "alice", callback: &subscriber{url: callbackURL, secret: callbackSecret, callback: true}},
+		{name: "without callback", tickets: store, secrets: secrets, ticketID: "t2", owner: "bob"},
+		{name: "callback not stored without a sealer", tickets: store, secrets: secrets, ticketID: "t3", owner: "carol"},
+		{name: "secret moved to another ticket", tickets: store, secrets: secrets, ticketID: "t4", owner: "alice"},
+		{name: "no sealer after the restart", tickets: store, ticketID: "t1", owner: "alice"},
+		{name: "expired", tickets: store, secrets: secrets, ticketID: "expired"},
+		{name: "unknown", tickets: store, secrets: secrets, ticketID: "t5"},
+		{name: "in memory only", ticketID: "t1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restarted := config
+			restarted.Tickets = tt.tickets
+			restarted.Secrets = tt.secrets
+			relay := NewRelay(restarted)
+
+			if owner := relay.Owner(tt.ticketID); owner != tt.owner {
+				t.Fatalf("got owner %q, want %q", owner, tt.owner)
+			}
+
+			var callback *subscriber
+			if tracked, ok := relay.tickets[tt.ticketID]; ok {
+				callback = tracked.callback
+			}
+			if (callback == nil) != (tt.callback == nil) || callback != nil && *callback != *tt.callback {
+				t.Fatalf("got callback %+v, want %+v", callback, tt.callback)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"id":"t1","status":"SUCCESS"}`)
+	signature := hmacHex([]byte("secret"), body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		signature string
+		body      []byte
+		want      bool
+	}{
+		{name: "good signature", secret: "secret", signature: signature, body: body, want: true},
+		{name: "upper case hex", secret: "secret", signature: strings.ToUpper(signature), body: body, want: true},
+		{name: "missing signature", secret: "secret", body: body},
+		{name: "other secret", secret: "secret", signature: hmacHex([]byte("other"), body), body: body},
+		{name: "tampered body", secret: "secret", signature: signature, body: []byte(`{"id":"t2","status":"SUCCESS"}`)},
+		{name: "not hex", secret: "secret", signature: "sha256=" + signature, body: body},
+		{name: "truncated", secret: "secret", signature: signature[:32], body: body},
+		{name: "relay without secret", signature: hmacHex(nil, body), body: body},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			relay := NewRelay(Config{PublicURL: "https://relay.example.com", CallbackSecret: tt.secret})
+			header := http.Header{}
+			if tt.signature != "" {
+				header.Set(CallbackSignatureHeader, tt.signature)
+			}
+			if got := relay.Verify(header, tt.body); got != tt.want {
+				t.Fatalf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}